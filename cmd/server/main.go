@@ -1,12 +1,19 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/config"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/infrastructure/database"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/infrastructure/firebase"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/repository"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/router"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/scheduler"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/version"
 )
 
@@ -24,7 +31,35 @@ func main() {
 		log.Fatalf("Failed to initialize MongoDB: %v", err)
 	}
 	defer database.CloseDatabase()
+	if err := repository.NewRallyParticipantRepository(database.GetDB()).EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("Failed to create participant indexes: %v", err)
+	}
+	if err := repository.NewPollVoteRepository(database.GetDB()).EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("Failed to create poll vote indexes: %v", err)
+	}
+	if err := repository.NewEmailInvitationRepository(database.GetDB()).EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("Failed to create email invitation indexes: %v", err)
+	}
 	firebase.MustInitialize(cfg.Firebase.CredentialsPath)
-	app := router.Setup(cfg)
-	log.Fatal(app.Listen(":" + cfg.Server.Port))
+	// Background jobs coordinate through a Mongo lock so only one instance runs each job
+	sched := scheduler.New(repository.NewJobLockRepository(database.GetDB()))
+	app := router.Setup(cfg, sched)
+	sched.Start()
+
+	go func() {
+		if err := app.Listen(":" + cfg.Server.Port); err != nil {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// Cloud Run sends SIGTERM before stopping an instance
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down...")
+	if err := app.ShutdownWithTimeout(10 * time.Second); err != nil {
+		log.Printf("Server shutdown error: %v", err)
+	}
+	sched.Stop()
 }
@@ -2,15 +2,20 @@ package config
 
 import (
 	"log"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server     ServerConfig
-	Database   DatabaseConfig
-	Firebase   FirebaseConfig
-	Cloudinary CloudinaryConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	Firebase    FirebaseConfig
+	Cloudinary  CloudinaryConfig
+	Jobs        JobsConfig
+	InviteLinks InviteLinksConfig
+	Mailer      MailerConfig
+	Push        PushConfig
 }
 
 type ServerConfig struct {
@@ -32,6 +37,35 @@ type CloudinaryConfig struct {
 	URL string
 }
 
+type JobsConfig struct {
+	TrashRetention      time.Duration // How long soft-deleted content stays in the trash before being purged
+	TrashPurgeInterval  time.Duration
+	RallyStatusInterval time.Duration // How often rallies are moved to active/completed based on their dates
+
+	InviteLinkExpiryInterval time.Duration // How often expired invite links are deactivated
+	InvitationTTL            time.Duration // How long an unanswered invitation stays pending before it expires
+	InvitationExpiryInterval time.Duration
+}
+
+type InviteLinksConfig struct {
+	BaseURL string // Deep-link base that invite link QR codes point to; the token is appended as the last path segment
+}
+
+type MailerConfig struct {
+	Driver       string // smtp, file or console
+	From         string
+	AppURL       string // Where invitation emails send people to sign up
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	OutboxPath   string // Used by the file driver
+}
+
+type PushConfig struct {
+	Driver string // fcm or log
+}
+
 // Load loads configuration from .env file and environment variables
 func Load() *Config {
 	viper.SetConfigFile(".env")
@@ -60,6 +94,31 @@ func Load() *Config {
 		Cloudinary: CloudinaryConfig{
 			URL: getEnv("CLOUDINARY_URL", ""),
 		},
+		Jobs: JobsConfig{
+			TrashRetention:      getEnvDuration("TRASH_RETENTION", 30*24*time.Hour),
+			TrashPurgeInterval:  getEnvDuration("TRASH_PURGE_INTERVAL", time.Hour),
+			RallyStatusInterval: getEnvDuration("RALLY_STATUS_INTERVAL", 15*time.Minute),
+
+			InviteLinkExpiryInterval: getEnvDuration("INVITE_LINK_EXPIRY_INTERVAL", 15*time.Minute),
+			InvitationTTL:            getEnvDuration("INVITATION_TTL", 30*24*time.Hour),
+			InvitationExpiryInterval: getEnvDuration("INVITATION_EXPIRY_INTERVAL", time.Hour),
+		},
+		InviteLinks: InviteLinksConfig{
+			BaseURL: getEnv("INVITE_LINK_BASE_URL", "https://rally.app/invite"),
+		},
+		Mailer: MailerConfig{
+			Driver:       getEnv("MAILER_DRIVER", "console"),
+			From:         getEnv("MAIL_FROM", "Rally <no-reply@rally.app>"),
+			AppURL:       getEnv("APP_URL", "https://rally.app"),
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			OutboxPath:   getEnv("MAIL_OUTBOX_PATH", "mail_outbox.log"),
+		},
+		Push: PushConfig{
+			Driver: getEnv("PUSH_DRIVER", "log"),
+		},
 	}
 
 	return cfg
@@ -72,3 +131,15 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvDuration is a helper for viper that parses Go duration strings (e.g. "720h").
+// Every duration in the config is a TTL or an interval, so zero and negative values are rejected.
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if viper.IsSet(key) {
+		if d, err := time.ParseDuration(viper.GetString(key)); err == nil && d > 0 {
+			return d
+		}
+		log.Printf("Warning: invalid duration for %s, using default %s", key, defaultValue)
+	}
+	return defaultValue
+}
@@ -34,6 +34,7 @@ func NewActivityHandler(activityService *service.ActivityService) *ActivityHandl
 // @Failure 401 {object} model.ErrorResponse "Unauthorized"
 // @Failure 403 {object} model.ErrorResponse "Forbidden"
 // @Failure 404 {object} model.ErrorResponse "Event not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived or completed"
 // @Router /events/{id}/activities [post]
 func (h *ActivityHandler) CreateActivity(c *fiber.Ctx) error {
 	eventID := c.Params("id")
@@ -72,6 +73,10 @@ func (h *ActivityHandler) CreateActivity(c *fiber.Ctx) error {
 			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
 				Message: err.Error(),
 			})
+		case "rally is archived or completed and cannot be edited":
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
 				Message: "Failed to create activity",
@@ -79,6 +84,7 @@ func (h *ActivityHandler) CreateActivity(c *fiber.Ctx) error {
 		}
 	}
 
+	setETag(c, response.Version)
 	return c.Status(fiber.StatusCreated).JSON(response)
 }
 
@@ -91,12 +97,16 @@ func (h *ActivityHandler) CreateActivity(c *fiber.Ctx) error {
 // @Produce json
 // @Param id path string true "Activity ID"
 // @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param If-Match header string false "Version (ETag) the update is based on"
 // @Param request body model.UpdateActivityRequest true "Activity update payload"
 // @Success 200 {object} model.ActivityResponse
+// @Header 200 {string} ETag "Current version of the activity"
 // @Failure 400 {object} model.ErrorResponse "Invalid request"
 // @Failure 401 {object} model.ErrorResponse "Unauthorized"
 // @Failure 403 {object} model.ErrorResponse "Forbidden"
 // @Failure 404 {object} model.ErrorResponse "Activity not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived or completed"
+// @Failure 412 {object} model.ActivityResponse "Activity was modified since the given version; body contains the current activity"
 // @Router /activities/{id} [put]
 func (h *ActivityHandler) UpdateActivity(c *fiber.Ctx) error {
 	activityID := c.Params("id")
@@ -108,6 +118,13 @@ func (h *ActivityHandler) UpdateActivity(c *fiber.Ctx) error {
 
 	user := c.Locals("user").(*model.User)
 
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
 	var req model.UpdateActivityRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
@@ -118,9 +135,12 @@ func (h *ActivityHandler) UpdateActivity(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	response, err := h.activityService.UpdateActivity(ctx, user, activityID, &req)
+	response, err := h.activityService.UpdateActivity(ctx, user, activityID, &req, expectedVersion)
 	if err != nil {
 		switch err.Error() {
+		case "precondition failed: resource has been modified":
+			setETag(c, response.Version)
+			return c.Status(fiber.StatusPreconditionFailed).JSON(response)
 		case "unauthorized: insufficient permissions", "unauthorized: not a participant of this rally", "unauthorized: participant status is not active (must be joined)":
 			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
 				Message: err.Error(),
@@ -129,6 +149,10 @@ func (h *ActivityHandler) UpdateActivity(c *fiber.Ctx) error {
 			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
 				Message: err.Error(),
 			})
+		case "rally is archived or completed and cannot be edited":
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
 				Message: "Failed to update activity",
@@ -136,5 +160,223 @@ func (h *ActivityHandler) UpdateActivity(c *fiber.Ctx) error {
 		}
 	}
 
+	setETag(c, response.Version)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetActivitiesList godoc
+// @Summary Get activities of an event
+// @Description Get all activities of an event ordered by activity order. Requires user to be a joined participant of the event's rally.
+// @Tags Activity
+// @ID getEventActivitiesList
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 200 {object} model.ActivityListResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Event not found"
+// @Router /events/{id}/activities [get]
+func (h *ActivityHandler) GetActivitiesList(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if eventID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Event ID is required",
+		})
+	}
+
+	user := c.Locals("user").(*model.User)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.activityService.GetActivitiesList(ctx, user, eventID)
+	if err != nil {
+		switch err.Error() {
+		case "unauthorized: insufficient permissions", "unauthorized: not a participant of this rally", "unauthorized: participant status is not active (must be joined)":
+			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "event not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to get activities list",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// DeleteActivity godoc
+// @Summary Delete an activity
+// @Description Move an activity to the trash. Requires owner or editor role in the activity's rally.
+// @Tags Activity
+// @ID deleteActivity
+// @Produce json
+// @Param id path string true "Activity ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 204 "No Content"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Activity not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived or completed"
+// @Router /activities/{id} [delete]
+func (h *ActivityHandler) DeleteActivity(c *fiber.Ctx) error {
+	activityID := c.Params("id")
+	if activityID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Activity ID is required",
+		})
+	}
+
+	user := c.Locals("user").(*model.User)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.activityService.DeleteActivity(ctx, user, activityID); err != nil {
+		switch err.Error() {
+		case "unauthorized: insufficient permissions", "unauthorized: not a participant of this rally", "unauthorized: participant status is not active (must be joined)":
+			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "activity not found", "event not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "rally is archived or completed and cannot be edited":
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to delete activity",
+			})
+		}
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RestoreActivity godoc
+// @Summary Restore an activity from the trash
+// @Description Restore a trashed activity. Activities whose event is also in the trash must be restored through the event. Requires owner or editor role in the activity's rally.
+// @Tags Activity
+// @ID restoreActivity
+// @Produce json
+// @Param id path string true "Activity ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 200 {object} model.ActivityResponse
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Activity not found in trash"
+// @Failure 409 {object} model.ErrorResponse "Event is deleted, or rally is archived or completed"
+// @Router /activities/{id}/restore [post]
+func (h *ActivityHandler) RestoreActivity(c *fiber.Ctx) error {
+	activityID := c.Params("id")
+	if activityID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Activity ID is required",
+		})
+	}
+
+	user := c.Locals("user").(*model.User)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.activityService.RestoreActivity(ctx, user, activityID)
+	if err != nil {
+		switch err.Error() {
+		case "unauthorized: insufficient permissions", "unauthorized: not a participant of this rally", "unauthorized: participant status is not active (must be joined)":
+			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "activity not found in trash", "activity not found", "event not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "cannot restore activity: its event is deleted", "rally is archived or completed and cannot be edited":
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to restore activity",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// ReorderActivities godoc
+// @Summary Reorder the activities of an event
+// @Description Atomically rewrite the activity order of all activities of an event. The request must list every current activity exactly once; a stale list is rejected with 409 and nothing is changed. Requires owner or editor role in the event's rally.
+// @Tags Activity
+// @ID reorderActivities
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.ReorderActivitiesRequest true "Ordered activity IDs"
+// @Success 200 {object} model.ActivityListResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Event not found"
+// @Failure 409 {object} model.ErrorResponse "Stale order, or rally is archived or completed"
+// @Router /events/{id}/activities/order [put]
+func (h *ActivityHandler) ReorderActivities(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if eventID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Event ID is required",
+		})
+	}
+
+	user := c.Locals("user").(*model.User)
+
+	var req model.ReorderActivitiesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.activityService.ReorderActivities(ctx, user, eventID, &req)
+	if err != nil {
+		switch err.Error() {
+		case "invalid order: malformed ID", "invalid order: duplicate ID":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "unauthorized: insufficient permissions", "unauthorized: not a participant of this rally", "unauthorized: participant status is not active (must be joined)":
+			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "event not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "stale order: activities have changed", "rally is archived or completed and cannot be edited":
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to reorder activities",
+			})
+		}
+	}
+
 	return c.Status(fiber.StatusOK).JSON(response)
 }
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/service"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditLogHandler struct {
+	auditLogService *service.AuditLogService
+}
+
+func NewAuditLogHandler(auditLogService *service.AuditLogService) *AuditLogHandler {
+	return &AuditLogHandler{
+		auditLogService: auditLogService,
+	}
+}
+
+// GetRallyHistory godoc
+// @Summary Get the change history of a rally
+// @Description Get a paginated, newest-first audit log of every change made to a rally, its events, activities, participants and invite links. Each entry holds the actor, action, target and a before/after field diff. Requires user to be a joined participant.
+// @Tags Rally
+// @ID getRallyHistory
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param page query int false "Page number (starts from 1)" default(1)
+// @Param pageSize query int false "Number of items per page" default(20)
+// @Success 200 {object} model.AuditLogListResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid rally ID"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Router /rallies/{id}/history [get]
+func (h *AuditLogHandler) GetRallyHistory(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+
+	page, pageSize := utils.ClampPagination(c.QueryInt("page", 1), c.QueryInt("pageSize", 20), 50)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.auditLogService.GetRallyHistory(ctx, rallyID, page, pageSize)
+	if err != nil {
+		switch err.Error() {
+		case "invalid rally ID":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to get rally history",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// RevertHistoryEntry godoc
+// @Summary Revert a change from the rally history
+// @Description Re-apply the previous values recorded in an event or activity update entry; fields that had no value before the change are cleared. Refused with 409 if a later change touched any of the same fields (including a bulk reorder of the events or activities around it) or the target no longer exists. Requires owner or editor role.
+// @Tags Rally
+// @ID revertRallyHistoryEntry
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param entryId path string true "History entry ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 200 {object} model.RevertResponse
+// @Failure 400 {object} model.ErrorResponse "Entry cannot be reverted"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "History entry not found"
+// @Failure 409 {object} model.ErrorResponse "Conflicting later change, or rally is archived or completed"
+// @Router /rallies/{id}/history/{entryId}/revert [post]
+func (h *AuditLogHandler) RevertHistoryEntry(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	entryID := c.Params("entryId")
+	user := c.Locals("user").(*model.User)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.auditLogService.RevertEntry(ctx, user, rallyID, entryID)
+	if err != nil {
+		switch err.Error() {
+		case "invalid history entry ID", "history entry cannot be reverted", "cannot revert: a changed field has no restorable previous value":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "history entry not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "cannot revert: a later change modified the same fields", "cannot revert: target no longer exists", "rally is archived or completed and cannot be edited":
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to revert change",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
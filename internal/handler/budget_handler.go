@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+type BudgetHandler struct {
+	budgetService *service.BudgetService
+}
+
+func NewBudgetHandler(budgetService *service.BudgetService) *BudgetHandler {
+	return &BudgetHandler{
+		budgetService: budgetService,
+	}
+}
+
+// CreateBudget godoc
+// @Summary Create a budget
+// @Description Plan spend for one expense category (lodging, food, transport, tickets or other) of a rally, or of one of its events when eventId is set. A rally has at most one budget per category at each level. The currency defaults to the rally's base currency. Requires owner or editor role.
+// @Tags Budget
+// @ID createBudget
+// @Accept json
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.CreateBudgetRequest true "Budget creation payload"
+// @Success 201 {object} model.BudgetResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Rally or event not found"
+// @Failure 409 {object} model.ErrorResponse "Budget already exists for the category, or rally is archived"
+// @Router /rallies/{id}/budgets [post]
+func (h *BudgetHandler) CreateBudget(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	user := c.Locals("user").(*model.User)
+
+	var req model.CreateBudgetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.budgetService.CreateBudget(ctx, user, rallyID, &req)
+	if err != nil {
+		return budgetErrorResponse(c, err, "Failed to create budget")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// GetBudgets godoc
+// @Summary Get the budgets of a rally
+// @Description Get every budget of a rally, rally-wide budgets first. Requires user to be a joined participant.
+// @Tags Budget
+// @ID getBudgets
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 200 {object} model.BudgetListResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid rally ID"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Router /rallies/{id}/budgets [get]
+func (h *BudgetHandler) GetBudgets(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.budgetService.GetBudgets(ctx, rallyID)
+	if err != nil {
+		switch err.Error() {
+		case "invalid rally ID":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to get budgets",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// UpdateBudget godoc
+// @Summary Update a budget
+// @Description Update the category, amount, currency or notes of a budget. Requires owner or editor role.
+// @Tags Budget
+// @ID updateBudget
+// @Accept json
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param budgetId path string true "Budget ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.UpdateBudgetRequest true "Budget update payload"
+// @Success 200 {object} model.BudgetResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Budget not found"
+// @Failure 409 {object} model.ErrorResponse "Budget already exists for the category, or rally is archived"
+// @Router /rallies/{id}/budgets/{budgetId} [put]
+func (h *BudgetHandler) UpdateBudget(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	budgetID := c.Params("budgetId")
+	user := c.Locals("user").(*model.User)
+
+	var req model.UpdateBudgetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.budgetService.UpdateBudget(ctx, user, rallyID, budgetID, &req)
+	if err != nil {
+		return budgetErrorResponse(c, err, "Failed to update budget")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// DeleteBudget godoc
+// @Summary Delete a budget
+// @Description Permanently delete a budget. Requires owner or editor role.
+// @Tags Budget
+// @ID deleteBudget
+// @Param id path string true "Rally ID"
+// @Param budgetId path string true "Budget ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 204 "No Content"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Budget not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived"
+// @Router /rallies/{id}/budgets/{budgetId} [delete]
+func (h *BudgetHandler) DeleteBudget(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	budgetID := c.Params("budgetId")
+	user := c.Locals("user").(*model.User)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.budgetService.DeleteBudget(ctx, user, rallyID, budgetID); err != nil {
+		return budgetErrorResponse(c, err, "Failed to delete budget")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetBudgetSummary godoc
+// @Summary Get the budget summary of a rally
+// @Description Compare planned and actual spend from the expense ledger, per category and per event, with over-budget flags. Rally and event totals only count spend in categories that have a budget; spend in other categories is reported as unbudgeted. It also projects what each joined participant will owe once the remaining budget is spent (shared equally). Everything is converted into the rally's base currency; amounts in currencies without an exchange rate are left out and listed in missingRates. Requires user to be a joined participant.
+// @Tags Budget
+// @ID getBudgetSummary
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 200 {object} model.BudgetSummaryResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid rally ID"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Rally not found"
+// @Failure 409 {object} model.ErrorResponse "Rally has no base currency"
+// @Router /rallies/{id}/budget/summary [get]
+func (h *BudgetHandler) GetBudgetSummary(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.budgetService.GetSummary(ctx, rallyID)
+	if err != nil {
+		switch err.Error() {
+		case "invalid rally ID":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "rally not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "rally has no base currency":
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to get budget summary",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// budgetErrorResponse maps the errors shared by the budget write endpoints to HTTP responses
+func budgetErrorResponse(c *fiber.Ctx, err error, fallback string) error {
+	switch err.Error() {
+	case "invalid expense category", "budget amount must be positive", "budget currency is required",
+		"invalid currency", "budget notes are too long":
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	case "rally not found", "event not found", "budget not found":
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	case "a budget already exists for this category", "rally is archived and its budget cannot be changed":
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Message: fallback,
+		})
+	}
+}
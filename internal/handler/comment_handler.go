@@ -0,0 +1,348 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/service"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CommentHandler struct {
+	commentService *service.CommentService
+}
+
+func NewCommentHandler(commentService *service.CommentService) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+	}
+}
+
+// CreateRallyComment godoc
+// @Summary Comment on a rally
+// @Description Start a discussion thread on a rally. Joined participants mentioned with @username are notified. Requires user to be a joined participant.
+// @Tags Comment
+// @ID createRallyComment
+// @Accept json
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.CreateCommentRequest true "Comment payload"
+// @Success 201 {object} model.CommentResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Rally not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived"
+// @Router /rallies/{id}/comments [post]
+func (h *CommentHandler) CreateRallyComment(c *fiber.Ctx) error {
+	return h.createComment(c, model.CommentTargetRally)
+}
+
+// GetRallyComments godoc
+// @Summary Get comments on a rally
+// @Description Get a paginated list of the discussion threads on a rally, newest first, with their reply counts. Requires user to be a joined participant.
+// @Tags Comment
+// @ID getRallyComments
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param page query int false "Page number (starts from 1)" default(1)
+// @Param pageSize query int false "Number of items per page" default(20)
+// @Success 200 {object} model.CommentListResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid rally ID"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Router /rallies/{id}/comments [get]
+func (h *CommentHandler) GetRallyComments(c *fiber.Ctx) error {
+	return h.getComments(c, model.CommentTargetRally)
+}
+
+// CreateEventComment godoc
+// @Summary Comment on an event
+// @Description Start a discussion thread on an event. Joined participants mentioned with @username are notified. Requires user to be a joined participant of the event's rally.
+// @Tags Comment
+// @ID createEventComment
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.CreateCommentRequest true "Comment payload"
+// @Success 201 {object} model.CommentResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Event not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived"
+// @Router /events/{id}/comments [post]
+func (h *CommentHandler) CreateEventComment(c *fiber.Ctx) error {
+	return h.createComment(c, model.CommentTargetEvent)
+}
+
+// GetEventComments godoc
+// @Summary Get comments on an event
+// @Description Get a paginated list of the discussion threads on an event, newest first, with their reply counts. Requires user to be a joined participant of the event's rally.
+// @Tags Comment
+// @ID getEventComments
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param page query int false "Page number (starts from 1)" default(1)
+// @Param pageSize query int false "Number of items per page" default(20)
+// @Success 200 {object} model.CommentListResponse
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Event not found"
+// @Router /events/{id}/comments [get]
+func (h *CommentHandler) GetEventComments(c *fiber.Ctx) error {
+	return h.getComments(c, model.CommentTargetEvent)
+}
+
+// CreateActivityComment godoc
+// @Summary Comment on an activity
+// @Description Start a discussion thread on an activity. Joined participants mentioned with @username are notified. Requires user to be a joined participant of the activity's rally.
+// @Tags Comment
+// @ID createActivityComment
+// @Accept json
+// @Produce json
+// @Param id path string true "Activity ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.CreateCommentRequest true "Comment payload"
+// @Success 201 {object} model.CommentResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Activity not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived"
+// @Router /activities/{id}/comments [post]
+func (h *CommentHandler) CreateActivityComment(c *fiber.Ctx) error {
+	return h.createComment(c, model.CommentTargetActivity)
+}
+
+// GetActivityComments godoc
+// @Summary Get comments on an activity
+// @Description Get a paginated list of the discussion threads on an activity, newest first, with their reply counts. Requires user to be a joined participant of the activity's rally.
+// @Tags Comment
+// @ID getActivityComments
+// @Produce json
+// @Param id path string true "Activity ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param page query int false "Page number (starts from 1)" default(1)
+// @Param pageSize query int false "Number of items per page" default(20)
+// @Success 200 {object} model.CommentListResponse
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Activity not found"
+// @Router /activities/{id}/comments [get]
+func (h *CommentHandler) GetActivityComments(c *fiber.Ctx) error {
+	return h.getComments(c, model.CommentTargetActivity)
+}
+
+// CreateReply godoc
+// @Summary Reply to a comment
+// @Description Reply in a comment's thread. Replying to a reply adds to the same thread. Joined participants mentioned with @username are notified. Requires user to be a joined participant of the comment's rally.
+// @Tags Comment
+// @ID createCommentReply
+// @Accept json
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.CreateCommentRequest true "Reply payload"
+// @Success 201 {object} model.CommentResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Comment not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived"
+// @Router /comments/{id}/replies [post]
+func (h *CommentHandler) CreateReply(c *fiber.Ctx) error {
+	commentID := c.Params("id")
+	user := c.Locals("user").(*model.User)
+
+	var req model.CreateCommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.commentService.CreateReply(ctx, user, commentID, &req)
+	if err != nil {
+		return commentErrorResponse(c, err, "Failed to create reply")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// GetReplies godoc
+// @Summary Get the replies to a comment
+// @Description Get a paginated list of the replies in a comment's thread, oldest first. Requires user to be a joined participant of the comment's rally.
+// @Tags Comment
+// @ID getCommentReplies
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param page query int false "Page number (starts from 1)" default(1)
+// @Param pageSize query int false "Number of items per page" default(20)
+// @Success 200 {object} model.CommentListResponse
+// @Failure 400 {object} model.ErrorResponse "Comment is a reply"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Comment not found"
+// @Router /comments/{id}/replies [get]
+func (h *CommentHandler) GetReplies(c *fiber.Ctx) error {
+	commentID := c.Params("id")
+	user := c.Locals("user").(*model.User)
+
+	page, pageSize := utils.ClampPagination(c.QueryInt("page", 1), c.QueryInt("pageSize", 20), 50)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.commentService.GetReplies(ctx, user, commentID, page, pageSize)
+	if err != nil {
+		return commentErrorResponse(c, err, "Failed to get replies")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// UpdateComment godoc
+// @Summary Edit a comment
+// @Description Replace the body of a comment. Participants mentioned for the first time are notified. Requires user to be the comment's author and a joined participant of its rally.
+// @Tags Comment
+// @ID updateComment
+// @Accept json
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.UpdateCommentRequest true "Comment update payload"
+// @Success 200 {object} model.CommentResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Comment not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived"
+// @Router /comments/{id} [put]
+func (h *CommentHandler) UpdateComment(c *fiber.Ctx) error {
+	commentID := c.Params("id")
+	user := c.Locals("user").(*model.User)
+
+	var req model.UpdateCommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.commentService.UpdateComment(ctx, user, commentID, &req)
+	if err != nil {
+		return commentErrorResponse(c, err, "Failed to update comment")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// DeleteComment godoc
+// @Summary Delete a comment
+// @Description Delete a comment. A thread's first comment that still has replies is blanked and shown as deleted until its last reply is gone. Requires user to be the comment's author, or an owner or editor of its rally to moderate.
+// @Tags Comment
+// @ID deleteComment
+// @Param id path string true "Comment ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 204 "No Content"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Comment not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived"
+// @Router /comments/{id} [delete]
+func (h *CommentHandler) DeleteComment(c *fiber.Ctx) error {
+	commentID := c.Params("id")
+	user := c.Locals("user").(*model.User)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.commentService.DeleteComment(ctx, user, commentID); err != nil {
+		return commentErrorResponse(c, err, "Failed to delete comment")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// createComment starts a thread on the rally, event or activity named by the id path parameter
+func (h *CommentHandler) createComment(c *fiber.Ctx, targetType model.CommentTargetType) error {
+	targetID := c.Params("id")
+	user := c.Locals("user").(*model.User)
+
+	var req model.CreateCommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.commentService.CreateComment(ctx, user, targetType, targetID, &req)
+	if err != nil {
+		return commentErrorResponse(c, err, "Failed to create comment")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// getComments lists the threads on the rally, event or activity named by the id path parameter
+func (h *CommentHandler) getComments(c *fiber.Ctx, targetType model.CommentTargetType) error {
+	targetID := c.Params("id")
+	user := c.Locals("user").(*model.User)
+
+	page, pageSize := utils.ClampPagination(c.QueryInt("page", 1), c.QueryInt("pageSize", 20), 50)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.commentService.GetComments(ctx, user, targetType, targetID, page, pageSize)
+	if err != nil {
+		return commentErrorResponse(c, err, "Failed to get comments")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// commentErrorResponse maps the errors shared by the comment endpoints to HTTP responses
+func commentErrorResponse(c *fiber.Ctx, err error, fallback string) error {
+	switch err.Error() {
+	case "invalid rally ID", "comment body is required", "comment is too long", "too many mentions in comment",
+		"replies cannot have replies":
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	case "unauthorized: not a participant of this rally", "unauthorized: participant status is not active (must be joined)",
+		"unauthorized: insufficient permissions", "unauthorized: only the author can edit a comment":
+		return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	case "rally not found", "event not found", "activity not found", "comment not found":
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	case "rally is archived and its comments cannot be changed":
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Message: fallback,
+		})
+	}
+}
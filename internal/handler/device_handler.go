@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+type DeviceHandler struct {
+	deviceService *service.DeviceService
+}
+
+func NewDeviceHandler(deviceService *service.DeviceService) *DeviceHandler {
+	return &DeviceHandler{
+		deviceService: deviceService,
+	}
+}
+
+// RegisterDevice godoc
+// @Summary Register a device for push notifications
+// @Description Register the FCM token of one of the authenticated user's app installations. Registering a known token updates its platform and locale; a token registered by another user is moved to the caller. The locale selects the language of push messages.
+// @Tags Notifications
+// @ID registerMyDevice
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.RegisterDeviceRequest true "Device registration payload"
+// @Success 200 {object} model.DeviceResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request payload, token, platform or locale"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Router /user/me/devices [post]
+func (h *DeviceHandler) RegisterDevice(c *fiber.Ctx) error {
+	idToken := c.Locals("idToken").(string)
+
+	var req model.RegisterDeviceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.deviceService.RegisterDevice(ctx, idToken, &req)
+	if err != nil {
+		switch err.Error() {
+		case "invalid or expired token", "user not found":
+			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "device token is required", "invalid platform", "invalid locale":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to register device",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// UnregisterDevice godoc
+// @Summary Unregister a device from push notifications
+// @Description Remove one of the authenticated user's FCM tokens, e.g. when signing out on that device.
+// @Tags Notifications
+// @ID unregisterMyDevice
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.UnregisterDeviceRequest true "Token to remove"
+// @Success 204 "No Content"
+// @Failure 400 {object} model.ErrorResponse "Invalid request payload or missing token"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 404 {object} model.ErrorResponse "Device not found"
+// @Router /user/me/devices [delete]
+func (h *DeviceHandler) UnregisterDevice(c *fiber.Ctx) error {
+	idToken := c.Locals("idToken").(string)
+
+	var req model.UnregisterDeviceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.deviceService.UnregisterDevice(ctx, idToken, req.Token); err != nil {
+		switch err.Error() {
+		case "invalid or expired token", "user not found":
+			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "device token is required":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "device not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to unregister device",
+			})
+		}
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
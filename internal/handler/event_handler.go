@@ -34,6 +34,7 @@ func NewEventHandler(eventService *service.EventService) *EventHandler {
 // @Failure 401 {object} model.ErrorResponse "Unauthorized"
 // @Failure 403 {object} model.ErrorResponse "Forbidden"
 // @Failure 404 {object} model.ErrorResponse "Rally not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived or completed"
 // @Router /rallies/{id}/events [post]
 func (h *EventHandler) CreateEvent(c *fiber.Ctx) error {
 	rallyID := c.Params("id")
@@ -62,6 +63,10 @@ func (h *EventHandler) CreateEvent(c *fiber.Ctx) error {
 			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
 				Message: err.Error(),
 			})
+		case "rally is archived or completed and cannot be edited":
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
 				Message: "Failed to create event",
@@ -69,6 +74,7 @@ func (h *EventHandler) CreateEvent(c *fiber.Ctx) error {
 		}
 	}
 
+	setETag(c, response.Version)
 	return c.Status(fiber.StatusCreated).JSON(response)
 }
 
@@ -81,12 +87,16 @@ func (h *EventHandler) CreateEvent(c *fiber.Ctx) error {
 // @Produce json
 // @Param id path string true "Event ID"
 // @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param If-Match header string false "Version (ETag) the update is based on"
 // @Param request body model.UpdateEventRequest true "Event update payload"
 // @Success 200 {object} model.EventResponse
+// @Header 200 {string} ETag "Current version of the event"
 // @Failure 400 {object} model.ErrorResponse "Invalid request"
 // @Failure 401 {object} model.ErrorResponse "Unauthorized"
 // @Failure 403 {object} model.ErrorResponse "Forbidden"
 // @Failure 404 {object} model.ErrorResponse "Event not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived or completed"
+// @Failure 412 {object} model.EventResponse "Event was modified since the given version; body contains the current event"
 // @Router /events/{id} [put]
 func (h *EventHandler) UpdateEvent(c *fiber.Ctx) error {
 	eventID := c.Params("id")
@@ -98,6 +108,13 @@ func (h *EventHandler) UpdateEvent(c *fiber.Ctx) error {
 
 	user := c.Locals("user").(*model.User)
 
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	}
+
 	var req model.UpdateEventRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
@@ -108,9 +125,12 @@ func (h *EventHandler) UpdateEvent(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	response, err := h.eventService.UpdateEvent(ctx, user, eventID, &req)
+	response, err := h.eventService.UpdateEvent(ctx, user, eventID, &req, expectedVersion)
 	if err != nil {
 		switch err.Error() {
+		case "precondition failed: resource has been modified":
+			setETag(c, response.Version)
+			return c.Status(fiber.StatusPreconditionFailed).JSON(response)
 		case "unauthorized: insufficient permissions", "unauthorized: not a participant of this rally", "unauthorized: participant status is not active (must be joined)":
 			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
 				Message: err.Error(),
@@ -119,6 +139,10 @@ func (h *EventHandler) UpdateEvent(c *fiber.Ctx) error {
 			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
 				Message: err.Error(),
 			})
+		case "rally is archived or completed and cannot be edited":
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
 				Message: "Failed to update event",
@@ -126,5 +150,273 @@ func (h *EventHandler) UpdateEvent(c *fiber.Ctx) error {
 		}
 	}
 
+	setETag(c, response.Version)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetEventsList godoc
+// @Summary Get events of a rally
+// @Description Get all events of a rally ordered by visit order. Requires user to be a joined participant.
+// @Tags Event
+// @ID getRallyEventsList
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 200 {object} model.EventListResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid rally ID"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Router /rallies/{id}/events [get]
+func (h *EventHandler) GetEventsList(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.eventService.GetEventsList(ctx, rallyID)
+	if err != nil {
+		switch err.Error() {
+		case "invalid rally ID":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to get events list",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetItinerary godoc
+// @Summary Get the full itinerary of a rally
+// @Description Get all events of a rally ordered by visit order, each with its activities ordered by activity order. Requires user to be a joined participant.
+// @Tags Event
+// @ID getRallyItinerary
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 200 {object} model.ItineraryResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid rally ID"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Router /rallies/{id}/itinerary [get]
+func (h *EventHandler) GetItinerary(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.eventService.GetItinerary(ctx, rallyID)
+	if err != nil {
+		switch err.Error() {
+		case "invalid rally ID":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to get itinerary",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// DeleteEvent godoc
+// @Summary Delete an event
+// @Description Move an event and all of its activities to the trash. Requires owner or editor role in the event's rally.
+// @Tags Event
+// @ID deleteEvent
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 204 "No Content"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Event not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived or completed"
+// @Router /events/{id} [delete]
+func (h *EventHandler) DeleteEvent(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if eventID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Event ID is required",
+		})
+	}
+
+	user := c.Locals("user").(*model.User)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.eventService.DeleteEvent(ctx, user, eventID); err != nil {
+		switch err.Error() {
+		case "unauthorized: insufficient permissions", "unauthorized: not a participant of this rally", "unauthorized: participant status is not active (must be joined)":
+			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "event not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "rally is archived or completed and cannot be edited":
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to delete event",
+			})
+		}
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RestoreEvent godoc
+// @Summary Restore an event from the trash
+// @Description Restore a trashed event together with the activities that were deleted along with it. Requires owner or editor role in the event's rally.
+// @Tags Event
+// @ID restoreEvent
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 200 {object} model.EventResponse
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Event not found in trash"
+// @Failure 409 {object} model.ErrorResponse "Rally is deleted, or rally is archived or completed"
+// @Router /events/{id}/restore [post]
+func (h *EventHandler) RestoreEvent(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if eventID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Event ID is required",
+		})
+	}
+
+	user := c.Locals("user").(*model.User)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.eventService.RestoreEvent(ctx, user, eventID)
+	if err != nil {
+		switch err.Error() {
+		case "unauthorized: insufficient permissions", "unauthorized: not a participant of this rally", "unauthorized: participant status is not active (must be joined)":
+			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "event not found in trash", "event not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "cannot restore event: its rally is deleted", "rally is archived or completed and cannot be edited":
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to restore event",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetTrash godoc
+// @Summary Get the trash of a rally
+// @Description Get the events and activities of a rally that are in the trash, most recently deleted first. Requires owner or editor role.
+// @Tags Event
+// @ID getRallyTrash
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 200 {object} model.TrashResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid rally ID"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Router /rallies/{id}/trash [get]
+func (h *EventHandler) GetTrash(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.eventService.GetTrash(ctx, rallyID)
+	if err != nil {
+		switch err.Error() {
+		case "invalid rally ID":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to get trash",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// ReorderEvents godoc
+// @Summary Reorder the events of a rally
+// @Description Atomically rewrite the visit order of all events of a rally. The request must list every current event exactly once; a stale list is rejected with 409 and nothing is changed. Requires owner or editor role.
+// @Tags Event
+// @ID reorderEvents
+// @Accept json
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.ReorderEventsRequest true "Ordered event IDs"
+// @Success 200 {object} model.EventListResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Rally not found"
+// @Failure 409 {object} model.ErrorResponse "Stale order, or rally is archived or completed"
+// @Router /rallies/{id}/events/order [put]
+func (h *EventHandler) ReorderEvents(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	user := c.Locals("user").(*model.User)
+
+	var req model.ReorderEventsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.eventService.ReorderEvents(ctx, user, rallyID, &req)
+	if err != nil {
+		switch err.Error() {
+		case "invalid rally ID", "invalid order: malformed ID", "invalid order: duplicate ID":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "rally not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "stale order: events have changed", "rally is archived or completed and cannot be edited":
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to reorder events",
+			})
+		}
+	}
+
 	return c.Status(fiber.StatusOK).JSON(response)
 }
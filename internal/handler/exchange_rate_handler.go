@@ -0,0 +1,310 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ExchangeRateHandler struct {
+	exchangeRateService *service.ExchangeRateService
+}
+
+func NewExchangeRateHandler(exchangeRateService *service.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		exchangeRateService: exchangeRateService,
+	}
+}
+
+// GetGlobalRates godoc
+// @Summary Get the global exchange rates
+// @Description Get the global exchange rate table, used for rally balances when a rally has no rate of its own for a currency pair.
+// @Tags Exchange Rate
+// @ID getGlobalExchangeRates
+// @Produce json
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 200 {object} model.ExchangeRateListResponse
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Router /exchange-rates [get]
+func (h *ExchangeRateHandler) GetGlobalRates(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.exchangeRateService.GetGlobalRates(ctx)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Message: "Failed to get exchange rates",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// ReplaceGlobalRates godoc
+// @Summary Upload the global exchange rates
+// @Description Replace the whole global exchange rate table. A rate also converts its pair the other way round, so each pair may only appear once. Requires an admin account.
+// @Tags Exchange Rate
+// @ID replaceGlobalExchangeRates
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.ReplaceExchangeRatesRequest true "Exchange rate table"
+// @Success 200 {object} model.ExchangeRateListResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Router /exchange-rates [put]
+func (h *ExchangeRateHandler) ReplaceGlobalRates(c *fiber.Ctx) error {
+	user := c.Locals("user").(*model.User)
+
+	var req model.ReplaceExchangeRatesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.exchangeRateService.ReplaceGlobalRates(ctx, user, &req)
+	if err != nil {
+		return exchangeRateErrorResponse(c, err, "Failed to save exchange rates")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// SetGlobalRate godoc
+// @Summary Set a global exchange rate
+// @Description Add or edit the global rate of a currency pair, replacing a rate stored for the inverse pair. Requires an admin account.
+// @Tags Exchange Rate
+// @ID setGlobalExchangeRate
+// @Accept json
+// @Produce json
+// @Param from path string true "Currency converted from" example(USD)
+// @Param to path string true "Currency converted to" example(VND)
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.SetExchangeRateRequest true "Exchange rate"
+// @Success 200 {object} model.ExchangeRateResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Router /exchange-rates/{from}/{to} [put]
+func (h *ExchangeRateHandler) SetGlobalRate(c *fiber.Ctx) error {
+	user := c.Locals("user").(*model.User)
+
+	var req model.SetExchangeRateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.exchangeRateService.SetGlobalRate(ctx, user, c.Params("from"), c.Params("to"), &req)
+	if err != nil {
+		return exchangeRateErrorResponse(c, err, "Failed to save exchange rate")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// DeleteGlobalRate godoc
+// @Summary Delete a global exchange rate
+// @Description Remove the global rate of a currency pair, whichever direction it was stored in. Requires an admin account.
+// @Tags Exchange Rate
+// @ID deleteGlobalExchangeRate
+// @Param from path string true "Currency converted from" example(USD)
+// @Param to path string true "Currency converted to" example(VND)
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 204 "No Content"
+// @Failure 400 {object} model.ErrorResponse "Invalid currency"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Exchange rate not found"
+// @Router /exchange-rates/{from}/{to} [delete]
+func (h *ExchangeRateHandler) DeleteGlobalRate(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.exchangeRateService.DeleteGlobalRate(ctx, c.Params("from"), c.Params("to")); err != nil {
+		return exchangeRateErrorResponse(c, err, "Failed to delete exchange rate")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetRallyRates godoc
+// @Summary Get the exchange rates of a rally
+// @Description Get the rally's own exchange rate table. Its rates take precedence over the global table when balances are converted into the rally's base currency. Requires user to be a joined participant.
+// @Tags Exchange Rate
+// @ID getRallyExchangeRates
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 200 {object} model.ExchangeRateListResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid rally ID"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Router /rallies/{id}/exchange-rates [get]
+func (h *ExchangeRateHandler) GetRallyRates(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.exchangeRateService.GetRallyRates(ctx, rallyID)
+	if err != nil {
+		switch err.Error() {
+		case "invalid rally ID":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to get exchange rates",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// ReplaceRallyRates godoc
+// @Summary Upload the exchange rates of a rally
+// @Description Replace the rally's whole exchange rate table. A rate also converts its pair the other way round, so each pair may only appear once. Requires owner or editor role.
+// @Tags Exchange Rate
+// @ID replaceRallyExchangeRates
+// @Accept json
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.ReplaceExchangeRatesRequest true "Exchange rate table"
+// @Success 200 {object} model.ExchangeRateListResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Rally not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived"
+// @Router /rallies/{id}/exchange-rates [put]
+func (h *ExchangeRateHandler) ReplaceRallyRates(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	user := c.Locals("user").(*model.User)
+
+	var req model.ReplaceExchangeRatesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.exchangeRateService.ReplaceRallyRates(ctx, user, rallyID, &req)
+	if err != nil {
+		return exchangeRateErrorResponse(c, err, "Failed to save exchange rates")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// SetRallyRate godoc
+// @Summary Set an exchange rate of a rally
+// @Description Add or edit the rally's rate of a currency pair, replacing a rate stored for the inverse pair. Requires owner or editor role.
+// @Tags Exchange Rate
+// @ID setRallyExchangeRate
+// @Accept json
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param from path string true "Currency converted from" example(THB)
+// @Param to path string true "Currency converted to" example(VND)
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.SetExchangeRateRequest true "Exchange rate"
+// @Success 200 {object} model.ExchangeRateResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Rally not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived"
+// @Router /rallies/{id}/exchange-rates/{from}/{to} [put]
+func (h *ExchangeRateHandler) SetRallyRate(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	user := c.Locals("user").(*model.User)
+
+	var req model.SetExchangeRateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.exchangeRateService.SetRallyRate(ctx, user, rallyID, c.Params("from"), c.Params("to"), &req)
+	if err != nil {
+		return exchangeRateErrorResponse(c, err, "Failed to save exchange rate")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// DeleteRallyRate godoc
+// @Summary Delete an exchange rate of a rally
+// @Description Remove the rally's rate of a currency pair, whichever direction it was stored in. Balances then fall back to the global rate, if any. Requires owner or editor role.
+// @Tags Exchange Rate
+// @ID deleteRallyExchangeRate
+// @Param id path string true "Rally ID"
+// @Param from path string true "Currency converted from" example(THB)
+// @Param to path string true "Currency converted to" example(VND)
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 204 "No Content"
+// @Failure 400 {object} model.ErrorResponse "Invalid currency"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Rally or exchange rate not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived"
+// @Router /rallies/{id}/exchange-rates/{from}/{to} [delete]
+func (h *ExchangeRateHandler) DeleteRallyRate(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	user := c.Locals("user").(*model.User)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.exchangeRateService.DeleteRallyRate(ctx, user, rallyID, c.Params("from"), c.Params("to")); err != nil {
+		return exchangeRateErrorResponse(c, err, "Failed to delete exchange rate")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// exchangeRateErrorResponse maps the errors shared by the exchange rate write endpoints to HTTP responses
+func exchangeRateErrorResponse(c *fiber.Ctx, err error, fallback string) error {
+	switch err.Error() {
+	case "invalid currency", "invalid exchange rate", "exchange rate must be between two different currencies",
+		"duplicate exchange rate for a currency pair", "too many exchange rates":
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	case "rally not found", "exchange rate not found":
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	case "rally is archived and its exchange rates cannot be changed":
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Message: fallback,
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/service"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ExpenseHandler struct {
+	expenseService *service.ExpenseService
+}
+
+func NewExpenseHandler(expenseService *service.ExpenseService) *ExpenseHandler {
+	return &ExpenseHandler{
+		expenseService: expenseService,
+	}
+}
+
+// CreateExpense godoc
+// @Summary Record an expense in a rally
+// @Description Record money a participant paid on behalf of others. Amounts are integers in the minor unit of the currency. The payer defaults to the caller and, without splits, the expense is shared equally by every joined participant. Only joined participants can pay or share an expense. Requires user to be a joined participant.
+// @Tags Expense
+// @ID createExpense
+// @Accept json
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.CreateExpenseRequest true "Expense creation payload"
+// @Success 201 {object} model.ExpenseResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Rally, event or activity not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived"
+// @Router /rallies/{id}/expenses [post]
+func (h *ExpenseHandler) CreateExpense(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	user := c.Locals("user").(*model.User)
+
+	var req model.CreateExpenseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.expenseService.CreateExpense(ctx, user, rallyID, &req)
+	if err != nil {
+		return expenseErrorResponse(c, err, "Failed to create expense")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// GetExpensesList godoc
+// @Summary Get expenses of a rally
+// @Description Get a paginated list of a rally's expenses, most recently spent first. Requires user to be a joined participant.
+// @Tags Expense
+// @ID getExpensesList
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param page query int false "Page number (starts from 1)" default(1)
+// @Param pageSize query int false "Number of items per page" default(20)
+// @Success 200 {object} model.ExpenseListResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid rally ID"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Router /rallies/{id}/expenses [get]
+func (h *ExpenseHandler) GetExpensesList(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+
+	page, pageSize := utils.ClampPagination(c.QueryInt("page", 1), c.QueryInt("pageSize", 20), 50)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.expenseService.GetExpensesList(ctx, rallyID, page, pageSize)
+	if err != nil {
+		switch err.Error() {
+		case "invalid rally ID":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to get expenses",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetExpense godoc
+// @Summary Get an expense
+// @Description Get a single expense of a rally with its resolved splits. Requires user to be a joined participant.
+// @Tags Expense
+// @ID getExpense
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param expenseId path string true "Expense ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 200 {object} model.ExpenseResponse
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Expense not found"
+// @Router /rallies/{id}/expenses/{expenseId} [get]
+func (h *ExpenseHandler) GetExpense(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	expenseID := c.Params("expenseId")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.expenseService.GetExpense(ctx, rallyID, expenseID)
+	if err != nil {
+		switch err.Error() {
+		case "expense not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to get expense",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// UpdateExpense godoc
+// @Summary Update an expense
+// @Description Update an expense. Splits are recomputed from the new amount, split mode or splits. Requires user to be the participant who recorded or paid the expense, or an owner or editor.
+// @Tags Expense
+// @ID updateExpense
+// @Accept json
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param expenseId path string true "Expense ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.UpdateExpenseRequest true "Expense update payload"
+// @Success 200 {object} model.ExpenseResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Expense, event or activity not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived"
+// @Router /rallies/{id}/expenses/{expenseId} [put]
+func (h *ExpenseHandler) UpdateExpense(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	expenseID := c.Params("expenseId")
+	user := c.Locals("user").(*model.User)
+	callerParticipant := c.Locals("rallyParticipant").(*model.RallyParticipant)
+
+	var req model.UpdateExpenseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.expenseService.UpdateExpense(ctx, user, callerParticipant, rallyID, expenseID, &req)
+	if err != nil {
+		return expenseErrorResponse(c, err, "Failed to update expense")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// DeleteExpense godoc
+// @Summary Delete an expense
+// @Description Permanently delete an expense. Requires user to be the participant who recorded or paid the expense, or an owner or editor.
+// @Tags Expense
+// @ID deleteExpense
+// @Param id path string true "Rally ID"
+// @Param expenseId path string true "Expense ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 204 "No Content"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Expense not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived"
+// @Router /rallies/{id}/expenses/{expenseId} [delete]
+func (h *ExpenseHandler) DeleteExpense(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	expenseID := c.Params("expenseId")
+	user := c.Locals("user").(*model.User)
+	callerParticipant := c.Locals("rallyParticipant").(*model.RallyParticipant)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.expenseService.DeleteExpense(ctx, user, callerParticipant, rallyID, expenseID); err != nil {
+		return expenseErrorResponse(c, err, "Failed to delete expense")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetBalances godoc
+// @Summary Get the balances of a rally
+// @Description Get what every participant paid, owes and is owed across the rally's expenses, together with a minimal list of transfers that settles everyone up. When the rally has a base currency, amounts are converted into it with the rally's exchange rates, falling back to the global table, and rounded to its minor unit; currencies without a rate are balanced separately and listed in missingRates. Without a base currency, every currency is balanced separately. Requires user to be a joined participant.
+// @Tags Expense
+// @ID getRallyBalances
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 200 {object} model.RallyBalancesResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid rally ID"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Rally not found"
+// @Router /rallies/{id}/balances [get]
+func (h *ExpenseHandler) GetBalances(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.expenseService.GetBalances(ctx, rallyID)
+	if err != nil {
+		switch err.Error() {
+		case "invalid rally ID":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "rally not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to get balances",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// expenseErrorResponse maps the errors shared by the expense write endpoints to HTTP responses
+func expenseErrorResponse(c *fiber.Ctx, err error, fallback string) error {
+	switch err.Error() {
+	case "expense description is required", "expense description is too long", "expense amount must be positive",
+		"invalid currency", "invalid expense category", "invalid split mode", "at least one participant must share the expense",
+		"shares must be between 1 and 1000", "split amounts cannot be negative", "split amounts must add up to the expense amount",
+		"only joined participants can be part of an expense", "a participant can only appear once in the splits",
+		"activity does not belong to the event":
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	case "unauthorized: insufficient permissions":
+		return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	case "rally not found", "expense not found", "event not found", "activity not found":
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	case "rally is archived and its expenses cannot be changed":
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Message: fallback,
+		})
+	}
+}
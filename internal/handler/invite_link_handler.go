@@ -3,10 +3,12 @@ package handler
 import (
 	"context"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/service"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/utils"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -109,9 +111,77 @@ func (h *InviteLinkHandler) GetActiveInviteLinks(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
+// UpdateInviteLink godoc
+// @Summary Edit an invite link
+// @Description Extends the expiry, raises the max uses or downgrades the granted role of an existing invite link while keeping its token. A link that never expires cannot be given an expiry. A link that was automatically deactivated because it expired or was used up becomes active again once the new limits allow it. Only owners can edit owner/editor links; revoked links cannot be edited.
+// @Tags Invite Links
+// @ID updateInviteLink
+// @Accept json
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param token path string true "Invite Link Token"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.UpdateInviteLinkRequest true "Invite Link Changes"
+// @Success 200 {object} model.InviteLinkResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request body or limits would be tightened"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Link not found"
+// @Failure 409 {object} model.ErrorResponse "Link has been revoked"
+// @Router /rallies/{id}/invite-links/{token} [patch]
+func (h *InviteLinkHandler) UpdateInviteLink(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	token := c.Params("token")
+
+	user := c.Locals("user").(*model.User)
+	callerParticipant := c.Locals("rallyParticipant").(*model.RallyParticipant)
+
+	var req model.UpdateInviteLinkRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.inviteLinkService.UpdateInviteLink(ctx, user, callerParticipant, rallyID, token, &req)
+	if err != nil {
+		switch err.Error() {
+		case "link not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "only owners can edit links for owner/editor roles":
+			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "revoked links cannot be edited":
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "invalid role",
+			"invite link role can only be downgraded",
+			"expiresInDays must be between 1 and 365",
+			"invite link expiry can only be extended",
+			"invite link max uses can only be raised":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to update invite link",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
 // DeactivateInviteLink godoc
 // @Summary Revoke an invite link
-// @Description Deactivates an invite link token so it can no longer be used.
+// @Description Deactivates an invite link token so it can no longer be used. Links that were automatically deactivated because they expired or were used up can still be revoked, which stops an edit from reactivating them.
 // @Tags Invite Links
 // @ID deactivateInviteLink
 // @Produce json
@@ -132,13 +202,15 @@ func (h *InviteLinkHandler) DeactivateInviteLink(c *fiber.Ctx) error {
 		})
 	}
 
+	user := c.Locals("user").(*model.User)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	err := h.inviteLinkService.DeactivateInviteLink(ctx, rallyID, token)
+	err := h.inviteLinkService.DeactivateInviteLink(ctx, user, rallyID, token)
 	if err != nil {
 		switch err.Error() {
-		case "link not found or already inactive", "link does not belong to this rally":
+		case "link not found or already revoked", "link does not belong to this rally":
 			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
 				Message: err.Error(),
 			})
@@ -152,9 +224,114 @@ func (h *InviteLinkHandler) DeactivateInviteLink(c *fiber.Ctx) error {
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
+// GetInviteLinkQRCode godoc
+// @Summary Get a QR code image for an invite link
+// @Description Renders a QR code pointing at the invite link's deep link (the configured base URL followed by the token) as a PNG or SVG image, so every client shows the same code. Requires owner or editor role.
+// @Tags Invite Links
+// @ID getInviteLinkQRCode
+// @Produce png
+// @Produce svg
+// @Param id path string true "Rally ID"
+// @Param token path string true "Invite Link Token"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param format query string false "Image format" Enums(png, svg) default(png)
+// @Param size query int false "Image width and height in pixels (64-1024)" default(256)
+// @Param ec query string false "Error correction level" Enums(L, M, Q, H) default(M)
+// @Success 200 {file} binary "QR code image"
+// @Failure 400 {object} model.ErrorResponse "Invalid format, size or error correction level"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Link not found"
+// @Router /rallies/{id}/invite-links/{token}/qr [get]
+func (h *InviteLinkHandler) GetInviteLinkQRCode(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	token := c.Params("token")
+
+	format := strings.ToLower(c.Query("format", utils.QRCodeFormatPNG))
+	level := strings.ToUpper(c.Query("ec", "M"))
+
+	size := c.QueryInt("size", 256)
+	if size < 64 || size > 1024 {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Size must be between 64 and 1024 pixels",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	image, err := h.inviteLinkService.GetInviteLinkQRCode(ctx, rallyID, token, format, size, level)
+	if err != nil {
+		switch err.Error() {
+		case "link not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "invalid QR code format", "invalid QR code error correction level":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to generate invite link QR code",
+			})
+		}
+	}
+
+	if format == utils.QRCodeFormatSVG {
+		c.Set(fiber.HeaderContentType, "image/svg+xml")
+	} else {
+		c.Set(fiber.HeaderContentType, "image/png")
+	}
+
+	return c.Status(fiber.StatusOK).Send(image)
+}
+
+// GetLinkRedemptions godoc
+// @Summary Get the redemption history of an invite link
+// @Description Get a paginated list of who joined (or asked to join) through an invite link and when, newest first, together with the link's preview and join statistics. Requires owner or editor role.
+// @Tags Invite Links
+// @ID getInviteLinkRedemptions
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param token path string true "Invite Link Token"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param page query int false "Page number (starts from 1)" default(1)
+// @Param pageSize query int false "Number of items per page" default(20)
+// @Success 200 {object} model.InviteLinkRedemptionListResponse
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Link not found"
+// @Router /rallies/{id}/invite-links/{token}/redemptions [get]
+func (h *InviteLinkHandler) GetLinkRedemptions(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	token := c.Params("token")
+
+	page, pageSize := utils.ClampPagination(c.QueryInt("page", 1), c.QueryInt("pageSize", 20), 50)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.inviteLinkService.GetLinkRedemptions(ctx, rallyID, token, page, pageSize)
+	if err != nil {
+		switch err.Error() {
+		case "link not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to get invite link redemptions",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
 // JoinViaLink godoc
 // @Summary Accept an invite link and join a rally
-// @Description Accepts a QR code / invite link token and immediately joins the rally with "joined" status. Takes the higher role when an existing in-app invitation exists.
+// @Description Accepts a QR code / invite link token and immediately joins the rally with "joined" status. Takes the higher role when an existing in-app invitation exists. Links that require approval instead file a "pending" join request carrying the optional message.
 // @Tags Invite Links
 // @ID joinViaLink
 // @Accept json
@@ -164,6 +341,8 @@ func (h *InviteLinkHandler) DeactivateInviteLink(c *fiber.Ctx) error {
 // @Success 200 {object} model.JoinViaLinkResponse
 // @Failure 400 {object} model.ErrorResponse "Invalid request or link expired/used up"
 // @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "User is banned from the rally"
+// @Failure 409 {object} model.ErrorResponse "Another join for the same user is in progress, or the link changed while joining (retry)"
 // @Router /rallies/join-via-link [post]
 func (h *InviteLinkHandler) JoinViaLink(c *fiber.Ctx) error {
 	idToken := c.Locals("idToken").(string)
@@ -181,10 +360,16 @@ func (h *InviteLinkHandler) JoinViaLink(c *fiber.Ctx) error {
 		})
 	}
 
+	if len(req.Message) > 500 {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Message must be at most 500 characters",
+		})
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	response, err := h.inviteLinkService.JoinViaLink(ctx, idToken, req.Token)
+	response, err := h.inviteLinkService.JoinViaLink(ctx, idToken, req.Token, req.Message)
 	if err != nil {
 		switch err.Error() {
 		case "invalid or expired token", "user not found":
@@ -195,6 +380,14 @@ func (h *InviteLinkHandler) JoinViaLink(c *fiber.Ctx) error {
 			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
 				Message: err.Error(),
 			})
+		case "you are banned from this rally":
+			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "a join for this rally is already in progress", "invite link changed while joining, please try again":
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
 				Message: "Failed to join via link",
@@ -216,6 +409,7 @@ func (h *InviteLinkHandler) JoinViaLink(c *fiber.Ctx) error {
 // @Param Authorization header string true "Bearer Firebase ID Token"
 // @Success 200 {object} model.InviteLinkPreviewResponse
 // @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "User is banned from the rally"
 // @Failure 404 {object} model.ErrorResponse "Link or rally not found"
 // @Failure 400 {object} model.ErrorResponse "Link expired or reached limit"
 // @Router /rallies/invite-links/{token}/preview [get]
@@ -249,6 +443,10 @@ func (h *InviteLinkHandler) PreviewInviteLink(c *fiber.Ctx) error {
 			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
 				Message: err.Error(),
 			})
+		case "you are banned from this rally":
+			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
 				Message: "Failed to preview invite link",
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/service"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+func NewNotificationHandler(notificationService *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetNotifications godoc
+// @Summary Get my notifications
+// @Description Get the authenticated user's notifications, newest first, with the unread count. Pass the returned nextCursor as cursor to load the next page.
+// @Tags Notifications
+// @ID getMyNotifications
+// @Produce json
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Number of notifications per page" default(20)
+// @Param unreadOnly query bool false "Only return unread notifications" default(false)
+// @Success 200 {object} model.NotificationListResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Router /user/me/notifications [get]
+func (h *NotificationHandler) GetNotifications(c *fiber.Ctx) error {
+	idToken := c.Locals("idToken").(string)
+
+	_, limit := utils.ClampPagination(1, c.QueryInt("limit", 20), 50)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.notificationService.GetNotifications(ctx, idToken, c.Query("cursor"), limit, c.QueryBool("unreadOnly", false))
+	if err != nil {
+		switch err.Error() {
+		case "invalid or expired token", "user not found":
+			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "invalid cursor":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to get notifications",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetUnreadCount godoc
+// @Summary Get my unread notification count
+// @Description Get the number of unread notifications of the authenticated user, e.g. for a badge.
+// @Tags Notifications
+// @ID getMyUnreadNotificationCount
+// @Produce json
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 200 {object} model.UnreadNotificationCountResponse
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Router /user/me/notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadCount(c *fiber.Ctx) error {
+	idToken := c.Locals("idToken").(string)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.notificationService.GetUnreadCount(ctx, idToken)
+	if err != nil {
+		switch err.Error() {
+		case "invalid or expired token", "user not found":
+			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to count unread notifications",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// MarkRead godoc
+// @Summary Mark a notification as read
+// @Description Marks one of the authenticated user's notifications as read. Marking an already read notification has no effect.
+// @Tags Notifications
+// @ID markNotificationRead
+// @Produce json
+// @Param id path string true "Notification ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 204 "No Content"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 404 {object} model.ErrorResponse "Notification not found"
+// @Router /user/me/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *fiber.Ctx) error {
+	idToken := c.Locals("idToken").(string)
+	notificationID := c.Params("id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.notificationService.MarkRead(ctx, idToken, notificationID); err != nil {
+		switch err.Error() {
+		case "invalid or expired token", "user not found":
+			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "notification not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to mark notification as read",
+			})
+		}
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// MarkAllRead godoc
+// @Summary Mark all notifications as read
+// @Description Marks every unread notification of the authenticated user as read.
+// @Tags Notifications
+// @ID markAllNotificationsRead
+// @Produce json
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 204 "No Content"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Router /user/me/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *fiber.Ctx) error {
+	idToken := c.Locals("idToken").(string)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.notificationService.MarkAllRead(ctx, idToken); err != nil {
+		switch err.Error() {
+		case "invalid or expired token", "user not found":
+			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to mark notifications as read",
+			})
+		}
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,318 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/service"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PollHandler struct {
+	pollService *service.PollService
+}
+
+func NewPollHandler(pollService *service.PollService) *PollHandler {
+	return &PollHandler{
+		pollService: pollService,
+	}
+}
+
+// CreatePoll godoc
+// @Summary Create a poll in a rally
+// @Description Ask the rally a question with 2 to 20 options, optionally for one of its events. Polls are single-choice unless multipleChoice is set; anonymous polls only show vote counts. Voting stops at the deadline, if any, or when the poll is closed. Requires user to be a joined participant.
+// @Tags Poll
+// @ID createPoll
+// @Accept json
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.CreatePollRequest true "Poll creation payload"
+// @Success 201 {object} model.PollResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Rally or event not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived or completed"
+// @Router /rallies/{id}/polls [post]
+func (h *PollHandler) CreatePoll(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	user := c.Locals("user").(*model.User)
+
+	var req model.CreatePollRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.pollService.CreatePoll(ctx, user, rallyID, &req)
+	if err != nil {
+		return pollErrorResponse(c, err, "Failed to create poll")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// GetPollsList godoc
+// @Summary Get polls of a rally
+// @Description Get a paginated list of a rally's polls with their results, newest first. Filter by status to get only open or closed polls; polls past their deadline stay open until they are closed. Requires user to be a joined participant.
+// @Tags Poll
+// @ID getPollsList
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param status query string false "Filter by status" Enums(open, closed)
+// @Param page query int false "Page number (starts from 1)" default(1)
+// @Param pageSize query int false "Number of items per page" default(20)
+// @Success 200 {object} model.PollListResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid rally ID or status"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Router /rallies/{id}/polls [get]
+func (h *PollHandler) GetPollsList(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	user := c.Locals("user").(*model.User)
+	status := model.PollStatus(c.Query("status"))
+
+	page, pageSize := utils.ClampPagination(c.QueryInt("page", 1), c.QueryInt("pageSize", 20), 50)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.pollService.GetPollsList(ctx, user, rallyID, status, page, pageSize)
+	if err != nil {
+		switch err.Error() {
+		case "invalid rally ID", "invalid poll status":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to get polls",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetPoll godoc
+// @Summary Get a poll
+// @Description Get a single poll of a rally with its results and the caller's vote. Voters are only listed for polls that are not anonymous. Requires user to be a joined participant.
+// @Tags Poll
+// @ID getPoll
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param pollId path string true "Poll ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 200 {object} model.PollResponse
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Poll not found"
+// @Router /rallies/{id}/polls/{pollId} [get]
+func (h *PollHandler) GetPoll(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	pollID := c.Params("pollId")
+	user := c.Locals("user").(*model.User)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.pollService.GetPoll(ctx, user, rallyID, pollID)
+	if err != nil {
+		switch err.Error() {
+		case "poll not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to get poll",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// DeletePoll godoc
+// @Summary Delete a poll
+// @Description Permanently delete a poll and its votes. An activity created from it is kept. Requires user to be the participant who created the poll, or an owner or editor.
+// @Tags Poll
+// @ID deletePoll
+// @Param id path string true "Rally ID"
+// @Param pollId path string true "Poll ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 204 "No Content"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Poll not found"
+// @Failure 409 {object} model.ErrorResponse "Rally is archived or completed"
+// @Router /rallies/{id}/polls/{pollId} [delete]
+func (h *PollHandler) DeletePoll(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	pollID := c.Params("pollId")
+	user := c.Locals("user").(*model.User)
+	callerParticipant := c.Locals("rallyParticipant").(*model.RallyParticipant)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.pollService.DeletePoll(ctx, user, callerParticipant, rallyID, pollID); err != nil {
+		return pollErrorResponse(c, err, "Failed to delete poll")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Vote godoc
+// @Summary Vote in a poll
+// @Description Cast the caller's vote, replacing any earlier vote of theirs. Single-choice polls take exactly one option. Only possible while the poll is open and before its deadline. Requires user to be a joined participant.
+// @Tags Poll
+// @ID votePoll
+// @Accept json
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param pollId path string true "Poll ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.VotePollRequest true "Chosen option IDs"
+// @Success 200 {object} model.PollResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Poll not found"
+// @Failure 409 {object} model.ErrorResponse "Poll is closed, or rally is archived or completed"
+// @Router /rallies/{id}/polls/{pollId}/vote [put]
+func (h *PollHandler) Vote(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	pollID := c.Params("pollId")
+	user := c.Locals("user").(*model.User)
+
+	var req model.VotePollRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.pollService.Vote(ctx, user, rallyID, pollID, &req)
+	if err != nil {
+		return pollErrorResponse(c, err, "Failed to vote")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// RetractVote godoc
+// @Summary Retract a vote from a poll
+// @Description Remove the caller's vote. Only possible while the poll is open and before its deadline. Requires user to be a joined participant.
+// @Tags Poll
+// @ID retractPollVote
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param pollId path string true "Poll ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 200 {object} model.PollResponse
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Poll or vote not found"
+// @Failure 409 {object} model.ErrorResponse "Poll is closed, or rally is archived or completed"
+// @Router /rallies/{id}/polls/{pollId}/vote [delete]
+func (h *PollHandler) RetractVote(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	pollID := c.Params("pollId")
+	user := c.Locals("user").(*model.User)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.pollService.RetractVote(ctx, user, rallyID, pollID)
+	if err != nil {
+		return pollErrorResponse(c, err, "Failed to retract vote")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// ClosePoll godoc
+// @Summary Close a poll
+// @Description Stop a poll and record its winner, the option with the most votes. On a tie, optionId picks the winner among the tied options. With createActivity, the winning option becomes a new activity, with its place, in the poll's event or in eventId when the poll is not attached to one; the poll stays open if the activity cannot be created. Requires owner or editor role.
+// @Tags Poll
+// @ID closePoll
+// @Accept json
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param pollId path string true "Poll ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.ClosePollRequest false "Winner and activity options"
+// @Success 200 {object} model.PollResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request, tie or no votes"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Poll or event not found"
+// @Failure 409 {object} model.ErrorResponse "Poll is already closed, or rally is archived or completed"
+// @Router /rallies/{id}/polls/{pollId}/close [post]
+func (h *PollHandler) ClosePoll(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	pollID := c.Params("pollId")
+	user := c.Locals("user").(*model.User)
+
+	var req model.ClosePollRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: "Invalid request payload",
+			})
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.pollService.ClosePoll(ctx, user, rallyID, pollID, &req)
+	if err != nil {
+		return pollErrorResponse(c, err, "Failed to close poll")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// pollErrorResponse maps the errors shared by the poll write endpoints to HTTP responses
+func pollErrorResponse(c *fiber.Ctx, err error, fallback string) error {
+	switch err.Error() {
+	case "invalid rally ID", "poll question is required", "poll question is too long", "a poll must have between 2 and 20 options",
+		"poll option text is required", "poll option text is too long", "poll deadline must be in the future",
+		"at least one option must be chosen", "only one option can be chosen in this poll", "invalid poll option",
+		"an option can only be chosen once", "option is not a winning option", "poll is tied; choose the winning option",
+		"poll has no votes", "an event is required to create the activity":
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	case "unauthorized: insufficient permissions", "unauthorized: not a participant of this rally",
+		"unauthorized: participant status is not active (must be joined)":
+		return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	case "rally not found", "poll not found", "vote not found", "event not found":
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	case "poll is closed", "poll is already closed", "rally is archived or completed and cannot be edited":
+		return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Message: fallback,
+		})
+	}
+}
@@ -54,32 +54,53 @@ func (h *RallyHandler) CreateRally(c *fiber.Ctx) error {
 
 	response, err := h.rallyService.CreateRally(ctx, user, &req)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
-			Message: "Failed to create rally",
-		})
+		switch err.Error() {
+		case "invalid currency":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to create rally",
+			})
+		}
 	}
 
+	setETag(c, response.Version)
 	return c.Status(fiber.StatusCreated).JSON(response)
 }
 
 // UpdateRally godoc
 // @Summary Update a rally
-// @Description Update rally details. Requires owner or editor role.
+// @Description Update rally details. Requires owner or editor role. Status changes must follow the rally lifecycle: only owners can move a rally out of archived, and a rally can only be completed once its end date has passed.
 // @Tags Rally
 // @ID updateRally
 // @Accept json
 // @Produce json
 // @Param id path string true "Rally ID"
 // @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param If-Match header string false "Version (ETag) the update is based on"
 // @Param request body model.UpdateRallyRequest true "Rally update payload"
 // @Success 200 {object} model.RallyResponse
+// @Header 200 {string} ETag "Current version of the rally"
 // @Failure 400 {object} model.ErrorResponse "Invalid request"
 // @Failure 401 {object} model.ErrorResponse "Unauthorized"
 // @Failure 403 {object} model.ErrorResponse "Forbidden"
 // @Failure 404 {object} model.ErrorResponse "Rally not found"
+// @Failure 409 {object} model.ErrorResponse "Status transition not allowed"
+// @Failure 412 {object} model.RallyResponse "Rally was modified since the given version; body contains the current rally"
 // @Router /rallies/{id} [put]
 func (h *RallyHandler) UpdateRally(c *fiber.Ctx) error {
 	rallyID := c.Params("id")
+	user := c.Locals("user").(*model.User)
+	participant := c.Locals("rallyParticipant").(*model.RallyParticipant)
+
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: err.Error(),
+		})
+	}
 
 	var req model.UpdateRallyRequest
 	if err := c.BodyParser(&req); err != nil {
@@ -91,13 +112,28 @@ func (h *RallyHandler) UpdateRally(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	response, err := h.rallyService.UpdateRally(ctx, rallyID, &req)
+	response, err := h.rallyService.UpdateRally(ctx, user, participant, rallyID, &req, expectedVersion)
 	if err != nil {
 		switch err.Error() {
+		case "precondition failed: resource has been modified":
+			setETag(c, response.Version)
+			return c.Status(fiber.StatusPreconditionFailed).JSON(response)
+		case "invalid rally status", "invalid currency":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "unauthorized: only owners can restore archived rallies":
+			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
 		case "rally not found":
 			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
 				Message: err.Error(),
 			})
+		case "invalid rally status transition", "cannot complete rally before its end date":
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
 				Message: "Failed to update rally",
@@ -105,6 +141,7 @@ func (h *RallyHandler) UpdateRally(c *fiber.Ctx) error {
 		}
 	}
 
+	setETag(c, response.Version)
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
@@ -194,6 +231,7 @@ func (h *RallyHandler) GetRalliesList(c *fiber.Ctx) error {
 // @Param id path string true "Rally ID"
 // @Param Authorization header string true "Bearer Firebase ID Token"
 // @Success 200 {object} model.RallyJoinResponse
+// @Header 200 {string} ETag "Current version of the rally"
 // @Failure 401 {object} model.ErrorResponse "Unauthorized"
 // @Failure 403 {object} model.ErrorResponse "Forbidden"
 // @Failure 404 {object} model.ErrorResponse "Rally not found"
@@ -223,5 +261,79 @@ func (h *RallyHandler) GetRally(c *fiber.Ctx) error {
 		}
 	}
 
+	setETag(c, response.Version)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// DeleteRally godoc
+// @Summary Delete a rally
+// @Description Move a rally to the trash together with its events and activities. Trashed content can be restored until it is purged after the retention window. Requires owner role.
+// @Tags Rally
+// @ID deleteRally
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 204 "No Content"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Rally not found"
+// @Router /rallies/{id} [delete]
+func (h *RallyHandler) DeleteRally(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	user := c.Locals("user").(*model.User)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.rallyService.DeleteRally(ctx, user, rallyID); err != nil {
+		switch err.Error() {
+		case "rally not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to delete rally",
+			})
+		}
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RestoreRally godoc
+// @Summary Restore a rally from the trash
+// @Description Restore a trashed rally together with the events and activities that were deleted along with it. Requires owner role.
+// @Tags Rally
+// @ID restoreRally
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 200 {object} model.RallyResponse
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Rally not found in trash"
+// @Router /rallies/{id}/restore [post]
+func (h *RallyHandler) RestoreRally(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	user := c.Locals("user").(*model.User)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.rallyService.RestoreRally(ctx, user, rallyID)
+	if err != nil {
+		switch err.Error() {
+		case "rally not found in trash", "rally not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to restore rally",
+			})
+		}
+	}
+
 	return c.Status(fiber.StatusOK).JSON(response)
 }
@@ -22,7 +22,7 @@ func NewRallyParticipantHandler(participantService *service.RallyParticipantServ
 
 // InviteParticipant godoc
 // @Summary Invite a user to a rally
-// @Description Invite a user to join a rally. Requires owner or editor role.
+// @Description Invite a user to join a rally, either by user ID or by email address. An email address that belongs to a registered user invites that user directly (201). Any other address gets an invitation email and a pending email invitation (202) that turns into a regular invitation once someone registers and verifies that address. Requires owner or editor role.
 // @Tags Rally Participants
 // @ID inviteParticipant
 // @Accept json
@@ -31,10 +31,12 @@ func NewRallyParticipantHandler(participantService *service.RallyParticipantServ
 // @Param Authorization header string true "Bearer Firebase ID Token"
 // @Param request body model.InviteParticipantRequest true "Invite payload"
 // @Success 201 {object} model.RallyParticipantResponse
+// @Success 202 {object} model.EmailInvitationResponse "Invitation emailed to an unregistered address"
 // @Failure 400 {object} model.ErrorResponse "Invalid request or user already a participant"
 // @Failure 401 {object} model.ErrorResponse "Unauthorized"
 // @Failure 403 {object} model.ErrorResponse "Forbidden"
 // @Failure 404 {object} model.ErrorResponse "Rally or user not found"
+// @Failure 409 {object} model.ErrorResponse "User is banned from this rally or email already invited"
 // @Router /rallies/{id}/participants [post]
 func (h *RallyParticipantHandler) InviteParticipant(c *fiber.Ctx) error {
 	rallyID := c.Params("id")
@@ -47,26 +49,39 @@ func (h *RallyParticipantHandler) InviteParticipant(c *fiber.Ctx) error {
 		})
 	}
 
-	if req.UserID == "" {
+	if req.UserID == "" && req.Email == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
-			Message: "User ID is required",
+			Message: "User ID or email is required",
 		})
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	response, err := h.participantService.InviteParticipant(ctx, user, rallyID, &req)
+	var (
+		response    *model.RallyParticipantResponse
+		emailInvite *model.EmailInvitationResponse
+		err         error
+	)
+	if req.UserID != "" {
+		response, err = h.participantService.InviteParticipant(ctx, user, rallyID, &req)
+	} else {
+		response, emailInvite, err = h.participantService.InviteByEmail(ctx, user, rallyID, &req)
+	}
 	if err != nil {
 		switch err.Error() {
 		case "rally not found", "target user not found":
 			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
 				Message: err.Error(),
 			})
-		case "user is already a participant":
+		case "user is already a participant", "invalid email address":
 			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
 				Message: err.Error(),
 			})
+		case "user is banned from this rally", "email is already invited":
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
 				Message: "Failed to invite participant",
@@ -74,12 +89,82 @@ func (h *RallyParticipantHandler) InviteParticipant(c *fiber.Ctx) error {
 		}
 	}
 
+	if emailInvite != nil {
+		return c.Status(fiber.StatusAccepted).JSON(emailInvite)
+	}
+
 	return c.Status(fiber.StatusCreated).JSON(response)
 }
 
+// GetEmailInvitations godoc
+// @Summary List a rally's email invitations
+// @Description Lists the outstanding invitations sent to email addresses that have no account yet, newest first. Requires owner or editor role.
+// @Tags Rally Participants
+// @ID getEmailInvitations
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 200 {array} model.EmailInvitationResponse
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Router /rallies/{id}/email-invitations [get]
+func (h *RallyParticipantHandler) GetEmailInvitations(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.participantService.GetEmailInvitations(ctx, rallyID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Message: "Failed to list email invitations",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// CancelEmailInvitation godoc
+// @Summary Cancel an email invitation
+// @Description Withdraws an invitation sent to an email address that has no account yet. Requires owner or editor role.
+// @Tags Rally Participants
+// @ID cancelEmailInvitation
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param invitationId path string true "Email Invitation ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 204 "No Content"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Email invitation not found"
+// @Router /rallies/{id}/email-invitations/{invitationId} [delete]
+func (h *RallyParticipantHandler) CancelEmailInvitation(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	invitationID := c.Params("invitationId")
+	user := c.Locals("user").(*model.User)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.participantService.CancelEmailInvitation(ctx, user, rallyID, invitationID); err != nil {
+		switch err.Error() {
+		case "email invitation not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to cancel email invitation",
+			})
+		}
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
 // UpdateParticipant godoc
 // @Summary Update a participant's role or status
-// @Description Update participant details. Role changes require owner. Status changes allowed for the participant themselves.
+// @Description Update participant details. Role changes require owner. Status changes allowed for the participant themselves. A rally must always keep at least one joined owner, so the last owner cannot demote themselves, leave or decline.
 // @Tags Rally Participants
 // @ID updateParticipant
 // @Accept json
@@ -93,6 +178,7 @@ func (h *RallyParticipantHandler) InviteParticipant(c *fiber.Ctx) error {
 // @Failure 401 {object} model.ErrorResponse "Unauthorized"
 // @Failure 403 {object} model.ErrorResponse "Forbidden"
 // @Failure 404 {object} model.ErrorResponse "Participant not found"
+// @Failure 409 {object} model.ErrorResponse "Rally would be left without a joined owner"
 // @Router /rallies/{id}/participants/{participantId} [put]
 func (h *RallyParticipantHandler) UpdateParticipant(c *fiber.Ctx) error {
 	rallyID := c.Params("id")
@@ -119,6 +205,10 @@ func (h *RallyParticipantHandler) UpdateParticipant(c *fiber.Ctx) error {
 	response, err := h.participantService.UpdateParticipant(ctx, user, callerParticipant, rallyID, participantID, &req)
 	if err != nil {
 		switch err.Error() {
+		case "banned status can only be changed by removing the participant", "join requests can only be approved or rejected":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
 		case "unauthorized: only owners can change roles", "unauthorized: insufficient permissions", "unauthorized: participant status is not active":
 			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
 				Message: err.Error(),
@@ -127,6 +217,10 @@ func (h *RallyParticipantHandler) UpdateParticipant(c *fiber.Ctx) error {
 			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
 				Message: err.Error(),
 			})
+		case "rally must have at least one joined owner":
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
 				Message: "Failed to update participant",
@@ -137,9 +231,259 @@ func (h *RallyParticipantHandler) UpdateParticipant(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
+// RemoveParticipant godoc
+// @Summary Remove or ban a participant from a rally
+// @Description Kick a participant out of a rally. They may rejoin later unless ban is set, in which case they are kept with "banned" status and can no longer be invited or join via invite links. Removing a banned participant lifts the ban. Requires owner or editor role; editors can only remove plain participants.
+// @Tags Rally Participants
+// @ID removeParticipant
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param participantId path string true "Participant ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param ban query bool false "Ban the participant instead of only removing them" default(false)
+// @Success 204 "No Content"
+// @Failure 400 {object} model.ErrorResponse "Invalid request"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Participant not found"
+// @Failure 409 {object} model.ErrorResponse "Rally would be left without a joined owner"
+// @Router /rallies/{id}/participants/{participantId} [delete]
+func (h *RallyParticipantHandler) RemoveParticipant(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	participantID := c.Params("participantId")
+	if participantID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Participant ID is required",
+		})
+	}
+
+	user := c.Locals("user").(*model.User)
+	callerParticipant := c.Locals("rallyParticipant").(*model.RallyParticipant)
+	ban := c.QueryBool("ban", false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.participantService.RemoveParticipant(ctx, user, callerParticipant, rallyID, participantID, ban); err != nil {
+		switch err.Error() {
+		case "cannot remove yourself from a rally":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "unauthorized: insufficient permissions":
+			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "participant not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "rally must have at least one joined owner":
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to remove participant",
+			})
+		}
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// TransferOwnership godoc
+// @Summary Transfer rally ownership
+// @Description Atomically promote a joined participant to owner, make them the rally's owner and demote the caller to editor. Requires owner role.
+// @Tags Rally Participants
+// @ID transferRallyOwnership
+// @Accept json
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param request body model.TransferOwnershipRequest true "Transfer payload"
+// @Success 200 {object} model.TransferOwnershipResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid request"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Rally or participant not found"
+// @Failure 409 {object} model.ErrorResponse "Target participant has not joined the rally"
+// @Router /rallies/{id}/transfer-ownership [post]
+func (h *RallyParticipantHandler) TransferOwnership(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	user := c.Locals("user").(*model.User)
+	callerParticipant := c.Locals("rallyParticipant").(*model.RallyParticipant)
+
+	var req model.TransferOwnershipRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+	}
+
+	if req.ParticipantID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Message: "Participant ID is required",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.participantService.TransferOwnership(ctx, user, callerParticipant, rallyID, &req)
+	if err != nil {
+		switch err.Error() {
+		case "cannot transfer ownership to yourself":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "rally not found", "participant not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "unauthorized: insufficient permissions":
+			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "target participant has not joined the rally":
+			return c.Status(fiber.StatusConflict).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to transfer ownership",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetJoinRequests godoc
+// @Summary Get pending join requests of a rally
+// @Description Get a paginated list of users who asked to join through an invite link that requires approval, newest first, including their optional message. Requires owner or editor role.
+// @Tags Rally Participants
+// @ID getRallyJoinRequests
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param page query int false "Page number (starts from 1)" default(1)
+// @Param pageSize query int false "Number of items per page" default(20)
+// @Success 200 {object} model.ParticipantListResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid rally ID"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Router /rallies/{id}/join-requests [get]
+func (h *RallyParticipantHandler) GetJoinRequests(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+
+	page, pageSize := utils.ClampPagination(c.QueryInt("page", 1), c.QueryInt("pageSize", 20), 50)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.participantService.GetJoinRequests(ctx, rallyID, page, pageSize)
+	if err != nil {
+		switch err.Error() {
+		case "invalid rally ID":
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to get join requests",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// ApproveJoinRequest godoc
+// @Summary Approve a join request
+// @Description Let a pending requester into the rally with the role offered by the invite link they used. Requires owner or editor role; only owners can approve requests for the owner or editor role.
+// @Tags Rally Participants
+// @ID approveJoinRequest
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param participantId path string true "Participant ID of the join request"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 200 {object} model.RallyParticipantResponse
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Join request not found"
+// @Router /rallies/{id}/join-requests/{participantId}/approve [post]
+func (h *RallyParticipantHandler) ApproveJoinRequest(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	participantID := c.Params("participantId")
+	user := c.Locals("user").(*model.User)
+	callerParticipant := c.Locals("rallyParticipant").(*model.RallyParticipant)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.participantService.ApproveJoinRequest(ctx, user, callerParticipant, rallyID, participantID)
+	if err != nil {
+		switch err.Error() {
+		case "join request not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		case "only owners can approve join requests for owner/editor roles":
+			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to approve join request",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// RejectJoinRequest godoc
+// @Summary Reject a join request
+// @Description Discard a pending join request. The requester may ask again through an active invite link. Requires owner or editor role.
+// @Tags Rally Participants
+// @ID rejectJoinRequest
+// @Produce json
+// @Param id path string true "Rally ID"
+// @Param participantId path string true "Participant ID of the join request"
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Success 204 "No Content"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Forbidden"
+// @Failure 404 {object} model.ErrorResponse "Join request not found"
+// @Router /rallies/{id}/join-requests/{participantId}/reject [post]
+func (h *RallyParticipantHandler) RejectJoinRequest(c *fiber.Ctx) error {
+	rallyID := c.Params("id")
+	participantID := c.Params("participantId")
+	user := c.Locals("user").(*model.User)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.participantService.RejectJoinRequest(ctx, user, rallyID, participantID); err != nil {
+		switch err.Error() {
+		case "join request not found":
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Message: "Failed to reject join request",
+			})
+		}
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
 // GetPendingInvitations godoc
 // @Summary Get pending rally invitations for the current user
-// @Description Retrieves all rally invitations with "invited" status for the authenticated user, enriched with rally and inviter info. Temporary endpoint until realtime notifications are implemented.
+// @Description Retrieves all rally invitations with "invited" status for the authenticated user, enriched with rally and inviter info. Unlike the invitation notifications in GET /user/me/notifications, only invitations that can still be accepted or declined are listed.
 // @Tags Rally Participants
 // @ID getPendingInvitations
 // @Produce json
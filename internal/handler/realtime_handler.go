@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/service"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+// realtimeHeartbeat is how often idle streams are pinged, which keeps proxies from closing them and
+// detects clients that went away without closing the connection
+const realtimeHeartbeat = 30 * time.Second
+
+type RealtimeHandler struct {
+	realtimeService *service.RealtimeService
+}
+
+func NewRealtimeHandler(realtimeService *service.RealtimeService) *RealtimeHandler {
+	return &RealtimeHandler{
+		realtimeService: realtimeService,
+	}
+}
+
+// StreamWebSocket godoc
+// @Summary Stream rally changes over WebSocket
+// @Description Upgrade to a WebSocket that receives a JSON RealtimeMessage for every change to the rally: rally, event, activity and participant updates. Client messages are ignored. The server closes the connection when the rally is deleted or the caller is removed from it.
+// @Tags Realtime
+// @ID streamRallyWebSocket
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param id path string true "Rally ID"
+// @Success 101 {object} model.RealtimeMessage "Switching protocols; each frame is a RealtimeMessage"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Not a joined participant"
+// @Failure 426 {object} model.ErrorResponse "WebSocket upgrade required"
+// @Router /rallies/{id}/realtime/ws [get]
+func (h *RealtimeHandler) StreamWebSocket(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return c.Status(fiber.StatusUpgradeRequired).JSON(model.ErrorResponse{
+			Message: "WebSocket upgrade required",
+		})
+	}
+
+	return websocket.New(h.serveWebSocket)(c)
+}
+
+func (h *RealtimeHandler) serveWebSocket(conn *websocket.Conn) {
+	participant := conn.Locals("rallyParticipant").(*model.RallyParticipant)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sub, err := h.realtimeService.Subscribe(ctx, participant)
+	if err != nil {
+		log.Printf("[realtime] %v", err)
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "failed to subscribe"))
+		return
+	}
+	defer sub.Close()
+
+	// The stream is one-way, but reading is what surfaces the client's close frame and answers pings
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(realtimeHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second)); err != nil {
+				return
+			}
+		case msg, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+			if h.realtimeService.EndsSubscription(msg, participant) {
+				_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "no longer a participant"))
+				return
+			}
+		}
+	}
+}
+
+// StreamEvents godoc
+// @Summary Stream rally changes over Server-Sent Events
+// @Description Fallback for clients that cannot use WebSockets. Each change to the rally is sent as an SSE event named after the message type, with the JSON RealtimeMessage as data. A comment line is sent periodically as a heartbeat. The stream ends when the rally is deleted or the caller is removed from it.
+// @Tags Realtime
+// @ID streamRallyEvents
+// @Produce text/event-stream
+// @Param Authorization header string true "Bearer Firebase ID Token"
+// @Param id path string true "Rally ID"
+// @Success 200 {object} model.RealtimeMessage "Event stream; each event's data is a RealtimeMessage"
+// @Failure 401 {object} model.ErrorResponse "Unauthorized"
+// @Failure 403 {object} model.ErrorResponse "Not a joined participant"
+// @Failure 500 {object} model.ErrorResponse "Internal server error"
+// @Router /rallies/{id}/realtime/sse [get]
+func (h *RealtimeHandler) StreamEvents(c *fiber.Ctx) error {
+	participant := c.Locals("rallyParticipant").(*model.RallyParticipant)
+
+	// The stream outlives the handler, so it gets its own context that is cancelled when writing fails
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sub, err := h.realtimeService.Subscribe(ctx, participant)
+	if err != nil {
+		cancel()
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Message: "Failed to subscribe to rally",
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+		defer sub.Close()
+
+		ticker := time.NewTicker(realtimeHeartbeat)
+		defer ticker.Stop()
+
+		// Flush the headers right away so the client knows the stream is open
+		if _, err := w.WriteString(": connected\n\n"); err != nil || w.Flush() != nil {
+			return
+		}
+
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := w.WriteString(": ping\n\n"); err != nil || w.Flush() != nil {
+					return
+				}
+			case msg, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(msg)
+				if err != nil {
+					log.Printf("[realtime] failed to encode %s: %v", msg.Type, err)
+					continue
+				}
+				if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", msg.Type, data); err != nil || w.Flush() != nil {
+					return
+				}
+				if h.realtimeService.EndsSubscription(msg, participant) {
+					return
+				}
+			}
+		}
+	})
+
+	return nil
+}
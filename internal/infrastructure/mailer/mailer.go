@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/config"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers outgoing email
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// New builds the mailer selected by cfg.Driver: "smtp" delivers through an SMTP server, "file" appends
+// messages to cfg.OutboxPath and "console" (the default) prints them to stdout for local development.
+func New(cfg config.MailerConfig) (Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return NewSMTPMailer(cfg)
+	case "file":
+		f, err := os.OpenFile(cfg.OutboxPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open mail outbox: %w", err)
+		}
+		return NewWriterMailer(f, cfg.From), nil
+	case "", "console":
+		return NewWriterMailer(os.Stdout, cfg.From), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer driver %q", cfg.Driver)
+	}
+}
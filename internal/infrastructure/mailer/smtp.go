@@ -0,0 +1,71 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/config"
+)
+
+// SMTPMailer delivers email through an SMTP server using PLAIN auth when credentials are configured
+type SMTPMailer struct {
+	addr     string
+	auth     smtp.Auth
+	from     string // Display form for the From header, e.g. "Rally <no-reply@rally.app>"
+	envelope string // Bare address used as the envelope sender (MAIL FROM)
+}
+
+// NewSMTPMailer builds an SMTP mailer. cfg.From may include a display name; it must parse as a
+// single RFC 5322 address.
+func NewSMTPMailer(cfg config.MailerConfig) (*SMTPMailer, error) {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mail from address %q: %w", cfg.From, err)
+	}
+
+	var auth smtp.Auth
+	if cfg.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+
+	return &SMTPMailer{
+		addr:     net.JoinHostPort(cfg.SMTPHost, cfg.SMTPPort),
+		auth:     auth,
+		from:     cfg.From,
+		envelope: from.Address,
+	}, nil
+}
+
+// Send delivers msg. net/smtp has no context support, so ctx is only checked before dialing.
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := smtp.SendMail(m.addr, m.auth, m.envelope, []string{msg.To}, formatMessage(m.from, msg)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// headerSanitizer strips line breaks so user-controlled values cannot inject extra headers
+var headerSanitizer = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// formatMessage renders msg as an RFC 5322 message with CRLF line endings. Header values are
+// stripped of line breaks and the subject is Q-encoded so non-ASCII text survives transport.
+func formatMessage(from string, msg *Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", headerSanitizer.Replace(from))
+	fmt.Fprintf(&b, "To: %s\r\n", headerSanitizer.Replace(msg.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", headerSanitizer.Replace(msg.Subject)))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,33 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// WriterMailer writes every message to an io.Writer instead of delivering it. It backs the
+// "console" and "file" drivers used in development and tests.
+type WriterMailer struct {
+	mu   sync.Mutex
+	w    io.Writer
+	from string
+}
+
+func NewWriterMailer(w io.Writer, from string) *WriterMailer {
+	return &WriterMailer{
+		w:    w,
+		from: from,
+	}
+}
+
+func (m *WriterMailer) Send(ctx context.Context, msg *Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := fmt.Fprintf(m.w, "----- email -----\n%s\n", formatMessage(m.from, msg)); err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	fb "firebase.google.com/go/v4"
+	"firebase.google.com/go/v4/messaging"
+)
+
+// fcmBatchSize is the most messages FCM accepts in one SendEach call
+const fcmBatchSize = 500
+
+// FCMSender delivers push notifications through Firebase Cloud Messaging
+type FCMSender struct {
+	client *messaging.Client
+}
+
+func NewFCMSender(ctx context.Context, app *fb.App) (*FCMSender, error) {
+	client, err := app.Messaging(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Firebase Messaging: %w", err)
+	}
+
+	return &FCMSender{client: client}, nil
+}
+
+func (s *FCMSender) Send(ctx context.Context, messages []*Message) ([]string, error) {
+	var invalidTokens []string
+
+	for start := 0; start < len(messages); start += fcmBatchSize {
+		batch := messages[start:min(start+fcmBatchSize, len(messages))]
+
+		fcmMessages := make([]*messaging.Message, len(batch))
+		for i, msg := range batch {
+			fcmMessages[i] = &messaging.Message{
+				Token: msg.Token,
+				Notification: &messaging.Notification{
+					Title: msg.Title,
+					Body:  msg.Body,
+				},
+				Data: msg.Data,
+			}
+		}
+
+		response, err := s.client.SendEach(ctx, fcmMessages)
+		if err != nil {
+			return invalidTokens, fmt.Errorf("failed to send push notifications: %w", err)
+		}
+
+		for i, result := range response.Responses {
+			if result.Success {
+				continue
+			}
+			if isInvalidToken(result.Error) {
+				invalidTokens = append(invalidTokens, batch[i].Token)
+				continue
+			}
+			log.Printf("[push] failed to deliver %s notification: %v", batch[i].Data["type"], result.Error)
+		}
+	}
+
+	return invalidTokens, nil
+}
+
+// isInvalidToken reports whether FCM rejected a message because its token can never be delivered to
+// again: the app was uninstalled or the token belongs to another Firebase project. Malformed-request
+// errors are not included, since they say nothing certain about the token.
+func isInvalidToken(err error) bool {
+	return messaging.IsUnregistered(err) || messaging.IsSenderIDMismatch(err)
+}
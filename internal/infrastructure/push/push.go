@@ -0,0 +1,37 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	fb "firebase.google.com/go/v4"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/config"
+)
+
+// Message is a push notification addressed to a single device token
+type Message struct {
+	Token string
+	Title string
+	Body  string
+	Data  map[string]string // Delivered to the app alongside the notification, e.g. for deep links
+}
+
+// Sender delivers push notifications. Send reports the tokens the push service rejected as no longer
+// valid, so the caller can stop targeting them; other per-message failures are only logged.
+type Sender interface {
+	Send(ctx context.Context, messages []*Message) (invalidTokens []string, err error)
+}
+
+// New builds the sender selected by cfg.Driver: "fcm" delivers through Firebase Cloud Messaging using
+// the app's credentials and "log" (the default) prints messages to stdout for local development.
+func New(ctx context.Context, cfg config.PushConfig, app *fb.App) (Sender, error) {
+	switch cfg.Driver {
+	case "fcm":
+		return NewFCMSender(ctx, app)
+	case "", "log":
+		return NewWriterSender(os.Stdout), nil
+	default:
+		return nil, fmt.Errorf("unknown push driver %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,31 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// WriterSender writes every message to an io.Writer instead of delivering it. It backs the "log"
+// driver used in development and tests, and never reports a token as invalid.
+type WriterSender struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterSender(w io.Writer) *WriterSender {
+	return &WriterSender{w: w}
+}
+
+func (s *WriterSender) Send(ctx context.Context, messages []*Message) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, msg := range messages {
+		if _, err := fmt.Fprintf(s.w, "----- push -----\nTo: %s\nTitle: %s\nBody: %s\nData: %v\n", msg.Token, msg.Title, msg.Body, msg.Data); err != nil {
+			return nil, fmt.Errorf("failed to write push notification: %w", err)
+		}
+	}
+	return nil, nil
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"strings"
 
+	"firebase.google.com/go/v4/auth"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
 	"github.com/gofiber/fiber/v2"
 )
@@ -29,3 +30,24 @@ func AuthRequired() fiber.Handler {
 		return c.Next()
 	}
 }
+
+// RequireAdmin only lets through users whose Firebase account carries the
+// "admin" custom claim. Must be used after ResolveFirebaseUser.
+func RequireAdmin() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := c.Locals("firebaseToken").(*auth.Token)
+		if !ok || token == nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(model.ErrorResponse{
+				Message: "User not resolved",
+			})
+		}
+
+		if isAdmin, _ := token.Claims["admin"].(bool); !isAdmin {
+			return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+				Message: "Admin access required",
+			})
+		}
+
+		return c.Next()
+	}
+}
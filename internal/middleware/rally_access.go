@@ -13,7 +13,8 @@ import (
 
 // ResolveFirebaseUser verifies the Firebase ID token from c.Locals("idToken")
 // and loads the corresponding user from the database.
-// On success, stores the *model.User in c.Locals("user").
+// On success, stores the *model.User in c.Locals("user") and the verified
+// *auth.Token in c.Locals("firebaseToken").
 func ResolveFirebaseUser(firebaseAuth *auth.Client, userRepo repository.UserRepository) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		idToken, ok := c.Locals("idToken").(string)
@@ -41,6 +42,7 @@ func ResolveFirebaseUser(firebaseAuth *auth.Client, userRepo repository.UserRepo
 		}
 
 		c.Locals("user", user)
+		c.Locals("firebaseToken", token)
 		return c.Next()
 	}
 }
@@ -22,6 +22,8 @@ type Activity struct {
 	ActivityOrder int                `json:"activityOrder" bson:"activity_order"`
 	CreatedAt     time.Time          `json:"createdAt" bson:"created_at"`
 	UpdatedAt     time.Time          `json:"updatedAt" bson:"updated_at"`
+	DeletedAt     *time.Time         `json:"deletedAt,omitempty" bson:"deleted_at,omitempty"` // Set when moved to trash
+	Version       int64              `json:"version" bson:"version"`                          // Incremented on every update, exposed as ETag
 }
 
 // CreateActivityRequest represents the request payload for creating an activity
@@ -49,8 +51,14 @@ type UpdateActivityRequest struct {
 	EndTime       *time.Time `json:"endTime,omitempty"`
 	Notes         *string    `json:"notes,omitempty"`
 	ActivityOrder *int       `json:"activityOrder,omitempty"`
+	Unset         []string   `json:"-"` // Stored fields to remove; only set internally when reverting history
 } //@name UpdateActivityRequest
 
+// ReorderActivitiesRequest represents the request payload for reordering all activities of an event
+type ReorderActivitiesRequest struct {
+	ActivityIDs []string `json:"activityIds"` // Every live activity of the event, in the desired activity order
+} //@name ReorderActivitiesRequest
+
 // ActivityResponse represents the API response for an activity
 type ActivityResponse struct {
 	ID            string     `json:"id" example:"507f1f77bcf86cd799439011"`
@@ -67,4 +75,12 @@ type ActivityResponse struct {
 	ActivityOrder int        `json:"activityOrder" example:"1"`
 	CreatedAt     time.Time  `json:"createdAt" example:"2025-01-15T10:30:00Z"`
 	UpdatedAt     time.Time  `json:"updatedAt" example:"2025-01-15T10:30:00Z"`
+	DeletedAt     *time.Time `json:"deletedAt,omitempty" example:"2025-01-20T10:30:00Z"`
+	Version       int64      `json:"version" example:"3"`
 } //@name ActivityResponse
+
+// ActivityListResponse represents the API response for an event's activities list
+type ActivityListResponse struct {
+	Activities []ActivityResponse `json:"activities"`
+	Total      int                `json:"total" example:"3"`
+} //@name ActivityListResponse
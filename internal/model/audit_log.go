@@ -0,0 +1,86 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditAction represents the kind of mutation recorded in an audit entry
+type AuditAction string
+
+const (
+	AuditActionCreate  AuditAction = "create"
+	AuditActionUpdate  AuditAction = "update"
+	AuditActionDelete  AuditAction = "delete"
+	AuditActionRestore AuditAction = "restore"
+	AuditActionReorder AuditAction = "reorder"
+	AuditActionRevert  AuditAction = "revert"
+)
+
+// AuditTargetType represents the kind of document an audit entry refers to
+type AuditTargetType string
+
+const (
+	AuditTargetRally       AuditTargetType = "rally"
+	AuditTargetEvent       AuditTargetType = "event"
+	AuditTargetActivity    AuditTargetType = "activity"
+	AuditTargetParticipant AuditTargetType = "participant"
+	AuditTargetInviteLink  AuditTargetType = "invite_link"
+
+	AuditTargetEmailInvitation AuditTargetType = "email_invitation"
+	AuditTargetExpense         AuditTargetType = "expense"
+	AuditTargetExchangeRate    AuditTargetType = "exchange_rate"
+	AuditTargetBudget          AuditTargetType = "budget"
+	AuditTargetPoll            AuditTargetType = "poll"
+	AuditTargetComment         AuditTargetType = "comment"
+)
+
+// FieldChange represents the value of a single stored field before and after a mutation.
+// Before is null for created fields, After is null for removed fields.
+type FieldChange struct {
+	Field  string      `json:"field" bson:"field" example:"start_time"`
+	Before interface{} `json:"before" bson:"before" swaggertype:"string" example:"2025-07-01T09:00:00Z"`
+	After  interface{} `json:"after" bson:"after" swaggertype:"string" example:"2025-07-01T10:00:00Z"`
+} //@name FieldChange
+
+// AuditLog represents an append-only record of a mutation made to a rally or its content
+type AuditLog struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id"`
+	RallyID    primitive.ObjectID `json:"rallyId" bson:"rally_id"`
+	ActorID    primitive.ObjectID `json:"actorId" bson:"actor_id"`
+	Action     AuditAction        `json:"action" bson:"action"`
+	TargetType AuditTargetType    `json:"targetType" bson:"target_type"`
+	TargetID   primitive.ObjectID `json:"targetId" bson:"target_id"`
+	Changes    []FieldChange      `json:"changes" bson:"changes"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"created_at"`
+}
+
+// AuditLogResponse represents a single entry of a rally's change history
+type AuditLogResponse struct {
+	ID         string               `json:"id" example:"507f1f77bcf86cd799439011"`
+	RallyID    string               `json:"rallyId" example:"507f1f77bcf86cd799439012"`
+	Actor      *ParticipantUserInfo `json:"actor,omitempty"`
+	Action     AuditAction          `json:"action" example:"update"`
+	TargetType AuditTargetType      `json:"targetType" example:"event"`
+	TargetID   string               `json:"targetId" example:"507f1f77bcf86cd799439013"`
+	Changes    []FieldChange        `json:"changes"`
+	CreatedAt  time.Time            `json:"createdAt" example:"2025-01-15T10:30:00Z"`
+} //@name AuditLogResponse
+
+// AuditLogListResponse represents the API response for a rally's change history
+type AuditLogListResponse struct {
+	Entries    []AuditLogResponse `json:"entries"`
+	Total      int                `json:"total" example:"100"`
+	Page       int                `json:"page" example:"1"`
+	PageSize   int                `json:"pageSize" example:"20"`
+	TotalPages int                `json:"totalPages" example:"5"`
+	Pagination PaginationMetadata `json:"pagination"`
+} //@name AuditLogListResponse
+
+// RevertResponse represents the result of reverting a history entry: the reverted event or activity
+type RevertResponse struct {
+	TargetType AuditTargetType   `json:"targetType" example:"event"`
+	Event      *EventResponse    `json:"event,omitempty"`
+	Activity   *ActivityResponse `json:"activity,omitempty"`
+} //@name RevertResponse
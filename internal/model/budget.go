@@ -0,0 +1,107 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Budget is the amount planned for one expense category, either for a whole rally or for one of its
+// events. A rally holds at most one budget per category at each level.
+type Budget struct {
+	ID        primitive.ObjectID  `json:"id" bson:"_id"`
+	RallyID   primitive.ObjectID  `json:"rallyId" bson:"rally_id"`
+	EventID   *primitive.ObjectID `json:"eventId,omitempty" bson:"event_id"` // Nil for a rally-wide budget
+	Category  ExpenseCategory     `json:"category" bson:"category"`
+	Amount    int64               `json:"amount" bson:"amount"`     // In minor units of the currency
+	Currency  string              `json:"currency" bson:"currency"` // ISO 4217 code
+	Notes     string              `json:"notes,omitempty" bson:"notes,omitempty"`
+	CreatedBy primitive.ObjectID  `json:"createdBy" bson:"created_by"`
+	CreatedAt time.Time           `json:"createdAt" bson:"created_at"`
+	UpdatedAt time.Time           `json:"updatedAt" bson:"updated_at"`
+}
+
+// CreateBudgetRequest represents the request payload for creating a budget
+type CreateBudgetRequest struct {
+	EventID  string          `json:"eventId,omitempty" example:"507f1f77bcf86cd799439015"` // Omit for a rally-wide budget
+	Category ExpenseCategory `json:"category" example:"lodging"`
+	Amount   int64           `json:"amount" example:"6000000"`
+	Currency string          `json:"currency,omitempty" example:"VND"` // Defaults to the rally's base currency
+	Notes    string          `json:"notes,omitempty" example:"Two nights in Da Lat"`
+} //@name CreateBudgetRequest
+
+// UpdateBudgetRequest represents the request payload for updating a budget
+type UpdateBudgetRequest struct {
+	Category *ExpenseCategory `json:"category,omitempty"`
+	Amount   *int64           `json:"amount,omitempty"`
+	Currency *string          `json:"currency,omitempty"`
+	Notes    *string          `json:"notes,omitempty"`
+} //@name UpdateBudgetRequest
+
+// BudgetResponse represents the API response for a budget
+type BudgetResponse struct {
+	ID        string          `json:"id" example:"507f1f77bcf86cd799439011"`
+	RallyID   string          `json:"rallyId" example:"507f1f77bcf86cd799439012"`
+	EventID   string          `json:"eventId,omitempty" example:"507f1f77bcf86cd799439015"`
+	Category  ExpenseCategory `json:"category" example:"lodging"`
+	Amount    int64           `json:"amount" example:"6000000"`
+	Currency  string          `json:"currency" example:"VND"`
+	Notes     string          `json:"notes,omitempty" example:"Two nights in Da Lat"`
+	CreatedBy string          `json:"createdBy" example:"507f1f77bcf86cd799439014"`
+	CreatedAt time.Time       `json:"createdAt" example:"2025-06-20T09:00:00Z"`
+	UpdatedAt time.Time       `json:"updatedAt" example:"2025-06-20T09:00:00Z"`
+} //@name BudgetResponse
+
+// BudgetListResponse represents every budget of a rally, rally-wide budgets first
+type BudgetListResponse struct {
+	Budgets []BudgetResponse `json:"budgets"`
+} //@name BudgetListResponse
+
+// CategorySpend compares planned and actual spend for one category. OverBudget is only set when
+// something was planned for the category.
+type CategorySpend struct {
+	Category   ExpenseCategory `json:"category" example:"food"`
+	Planned    int64           `json:"planned" example:"3000000"`
+	Actual     int64           `json:"actual" example:"3450000"`
+	Remaining  int64           `json:"remaining" example:"-450000"`
+	OverBudget bool            `json:"overBudget" example:"true"`
+} //@name CategorySpend
+
+// EventBudgetSummary compares planned and actual spend for one event of the rally
+type EventBudgetSummary struct {
+	EventID    string          `json:"eventId" example:"507f1f77bcf86cd799439015"`
+	EventName  string          `json:"eventName" example:"Da Lat"`
+	Planned    int64           `json:"planned" example:"4000000"`
+	Actual     int64           `json:"actual" example:"3200000"`    // Spend in categories with a budget
+	Unbudgeted int64           `json:"unbudgeted" example:"250000"` // Spend in categories without a budget
+	Remaining  int64           `json:"remaining" example:"800000"`
+	OverBudget bool            `json:"overBudget" example:"false"`
+	Categories []CategorySpend `json:"categories"`
+} //@name EventBudgetSummary
+
+// ParticipantProjection is a participant's share of the rally's spend: what they owe for expenses
+// so far, and what they are projected to owe once the rest of the budget is spent
+type ParticipantProjection struct {
+	UserID    string `json:"userId" example:"507f1f77bcf86cd799439014"`
+	Spent     int64  `json:"spent" example:"1150000"`
+	Projected int64  `json:"projected" example:"2500000"`
+} //@name ParticipantProjection
+
+// BudgetSummaryResponse represents planned vs. actual spend of a rally in its base currency. Rally
+// totals use the rally-wide budget of a category, or the sum of its event budgets when there is none.
+// Actual, Remaining and OverBudget only cover categories with a budget, so unplanned spend never makes
+// a rally or event over budget; it is reported as Unbudgeted instead.
+type BudgetSummaryResponse struct {
+	RallyID      string                  `json:"rallyId" example:"507f1f77bcf86cd799439012"`
+	Currency     string                  `json:"currency" example:"VND"`
+	Planned      int64                   `json:"planned" example:"12000000"`
+	Actual       int64                   `json:"actual" example:"9800000"`    // Spend in categories with a budget
+	Unbudgeted   int64                   `json:"unbudgeted" example:"450000"` // Spend in categories without a budget
+	Remaining    int64                   `json:"remaining" example:"2200000"`
+	OverBudget   bool                    `json:"overBudget" example:"false"`
+	Categories   []CategorySpend         `json:"categories"`
+	Events       []EventBudgetSummary    `json:"events"`
+	Participants []ParticipantProjection `json:"participants"`
+	Rates        []ExchangeRateResponse  `json:"rates"`        // Exchange rates used for the conversions
+	MissingRates []string                `json:"missingRates"` // Currencies left out for lack of a rate to the base currency
+} //@name BudgetSummaryResponse
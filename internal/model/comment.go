@@ -0,0 +1,82 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CommentTargetType represents what a comment thread is about
+type CommentTargetType string
+
+const (
+	CommentTargetRally    CommentTargetType = "rally"
+	CommentTargetEvent    CommentTargetType = "event"
+	CommentTargetActivity CommentTargetType = "activity"
+)
+
+// Comment is a message in a discussion about a rally, event or activity. Top-level comments start a
+// thread; replies point at the top-level comment through ParentID, so threads are one level deep.
+type Comment struct {
+	ID         primitive.ObjectID   `json:"id" bson:"_id"`
+	RallyID    primitive.ObjectID   `json:"rallyId" bson:"rally_id"`
+	TargetType CommentTargetType    `json:"targetType" bson:"target_type"`
+	TargetID   primitive.ObjectID   `json:"targetId" bson:"target_id"`
+	ParentID   *primitive.ObjectID  `json:"parentId,omitempty" bson:"parent_id"` // Nil for top-level comments
+	Body       string               `json:"body" bson:"body"`
+	Mentions   []primitive.ObjectID `json:"mentions,omitempty" bson:"mentions,omitempty"` // Joined participants @mentioned in the body
+	ReplyCount int                  `json:"replyCount" bson:"reply_count"`
+	AuthorID   primitive.ObjectID   `json:"authorId" bson:"author_id"`
+	EditedAt   *time.Time           `json:"editedAt,omitempty" bson:"edited_at,omitempty"`
+	DeletedAt  *time.Time           `json:"deletedAt,omitempty" bson:"deleted_at,omitempty"` // Set on deleted comments kept for their replies
+	DeletedBy  *primitive.ObjectID  `json:"deletedBy,omitempty" bson:"deleted_by,omitempty"`
+	CreatedAt  time.Time            `json:"createdAt" bson:"created_at"`
+	UpdatedAt  time.Time            `json:"updatedAt" bson:"updated_at"`
+}
+
+// CommentWithAuthor is a comment joined with its author's profile
+type CommentWithAuthor struct {
+	Comment `bson:",inline"`
+	Author  *ParticipantUserInfo `bson:"author_info"`
+}
+
+// CreateCommentRequest represents the request payload for posting a comment or a reply. Participants
+// are mentioned by writing @username in the body.
+type CreateCommentRequest struct {
+	Body string `json:"body" example:"Should we book a table? @johndoe"`
+} //@name CreateCommentRequest
+
+// UpdateCommentRequest represents the request payload for editing a comment
+type UpdateCommentRequest struct {
+	Body string `json:"body" example:"Table booked for 7pm @johndoe"`
+} //@name UpdateCommentRequest
+
+// CommentResponse represents the API response for a comment. Deleted comments that still have replies
+// are returned with an empty body and isDeleted set.
+type CommentResponse struct {
+	ID         string               `json:"id" example:"507f1f77bcf86cd799439011"`
+	RallyID    string               `json:"rallyId" example:"507f1f77bcf86cd799439012"`
+	TargetType CommentTargetType    `json:"targetType" example:"event"`
+	TargetID   string               `json:"targetId" example:"507f1f77bcf86cd799439015"`
+	ParentID   string               `json:"parentId,omitempty" example:"507f1f77bcf86cd799439016"`
+	Body       string               `json:"body" example:"Should we book a table? @johndoe"`
+	Mentions   []string             `json:"mentions,omitempty"` // IDs of the mentioned users
+	Author     *ParticipantUserInfo `json:"author,omitempty"`
+	ReplyCount int                  `json:"replyCount" example:"2"`
+	IsEdited   bool                 `json:"isEdited" example:"false"`
+	EditedAt   *time.Time           `json:"editedAt,omitempty" example:"2025-07-01T10:05:00Z"`
+	IsDeleted  bool                 `json:"isDeleted" example:"false"`
+	CreatedAt  time.Time            `json:"createdAt" example:"2025-07-01T10:00:00Z"`
+	UpdatedAt  time.Time            `json:"updatedAt" example:"2025-07-01T10:00:00Z"`
+} //@name CommentResponse
+
+// CommentListResponse represents a paginated list of comments: the threads of a rally, event or
+// activity, newest first, or the replies of a thread, oldest first
+type CommentListResponse struct {
+	Comments   []CommentResponse  `json:"comments"`
+	Total      int                `json:"total" example:"12"`
+	Page       int                `json:"page" example:"1"`
+	PageSize   int                `json:"pageSize" example:"20"`
+	TotalPages int                `json:"totalPages" example:"1"`
+	Pagination PaginationMetadata `json:"pagination"`
+} //@name CommentListResponse
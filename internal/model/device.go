@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DevicePlatform identifies the kind of client a push token belongs to
+type DevicePlatform string
+
+const (
+	DevicePlatformIOS     DevicePlatform = "ios"
+	DevicePlatformAndroid DevicePlatform = "android"
+	DevicePlatformWeb     DevicePlatform = "web"
+)
+
+// Device is a push notification token registered by one of a user's app installations.
+// A token is unique across users: registering it again moves it to the registering user.
+type Device struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	UserID    primitive.ObjectID `json:"userId" bson:"user_id"`
+	Token     string             `json:"token" bson:"token"` // FCM registration token
+	Platform  DevicePlatform     `json:"platform" bson:"platform"`
+	Locale    string             `json:"locale" bson:"locale"` // BCP 47 tag used to localize push messages, e.g. "en-US"
+	CreatedAt time.Time          `json:"createdAt" bson:"created_at"`
+	UpdatedAt time.Time          `json:"updatedAt" bson:"updated_at"`
+}
+
+// RegisterDeviceRequest represents the request body for registering a push notification token
+type RegisterDeviceRequest struct {
+	Token    string         `json:"token" example:"fcm-registration-token"`
+	Platform DevicePlatform `json:"platform" example:"ios"`
+	Locale   string         `json:"locale,omitempty" example:"en-US"`
+} //@name RegisterDeviceRequest
+
+// UnregisterDeviceRequest represents the request body for removing a push notification token
+type UnregisterDeviceRequest struct {
+	Token string `json:"token" example:"fcm-registration-token"`
+} //@name UnregisterDeviceRequest
+
+// DeviceResponse represents a registered push notification token
+type DeviceResponse struct {
+	ID        string         `json:"id" example:"507f1f77bcf86cd799439011"`
+	Platform  DevicePlatform `json:"platform" example:"ios"`
+	Locale    string         `json:"locale" example:"en-US"`
+	CreatedAt time.Time      `json:"createdAt" example:"2025-01-15T10:30:00Z"`
+	UpdatedAt time.Time      `json:"updatedAt" example:"2025-01-15T10:30:00Z"`
+} //@name DeviceResponse
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EmailInvitation is a rally invitation sent to an email address that has no account yet.
+// It is turned into a regular "invited" participant when a user with that email registers.
+type EmailInvitation struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	RallyID   primitive.ObjectID `json:"rallyId" bson:"rally_id"`
+	Email     string             `json:"email" bson:"email"` // Normalized: trimmed and lower-cased
+	Role      ParticipantRole    `json:"role" bson:"role"`
+	InvitedBy primitive.ObjectID `json:"invitedBy" bson:"invited_by"`
+	InvitedAt time.Time          `json:"invitedAt" bson:"invited_at"`
+}
+
+// EmailInvitationResponse represents the API response for an invitation sent to an unregistered email
+type EmailInvitationResponse struct {
+	ID        string          `json:"id" example:"507f1f77bcf86cd799439011"`
+	RallyID   string          `json:"rallyId" example:"507f1f77bcf86cd799439012"`
+	Email     string          `json:"email" example:"anna@example.com"`
+	Role      ParticipantRole `json:"role" example:"participant"`
+	InvitedBy string          `json:"invitedBy" example:"507f1f77bcf86cd799439014"`
+	InvitedAt time.Time       `json:"invitedAt" example:"2025-01-15T10:30:00Z"`
+} //@name EmailInvitationResponse
@@ -20,6 +20,14 @@ type Event struct {
 	VisitOrder    int                `json:"visitOrder" bson:"visit_order"`
 	CreatedAt     time.Time          `json:"createdAt" bson:"created_at"`
 	UpdatedAt     time.Time          `json:"updatedAt" bson:"updated_at"`
+	DeletedAt     *time.Time         `json:"deletedAt,omitempty" bson:"deleted_at,omitempty"` // Set when moved to trash
+	Version       int64              `json:"version" bson:"version"`                          // Incremented on every update, exposed as ETag
+}
+
+// EventWithActivities represents an event joined with its ordered activities (itinerary aggregation result)
+type EventWithActivities struct {
+	Event      `bson:",inline"`
+	Activities []Activity `bson:"activities"`
 }
 
 // CreateEventRequest represents the request payload for creating an event
@@ -44,8 +52,14 @@ type UpdateEventRequest struct {
 	EndTime       *time.Time `json:"endTime,omitempty"`
 	Notes         *string    `json:"notes,omitempty"`
 	VisitOrder    *int       `json:"visitOrder,omitempty"`
+	Unset         []string   `json:"-"` // Stored fields to remove; only set internally when reverting history
 } //@name UpdateEventRequest
 
+// ReorderEventsRequest represents the request payload for reordering all events of a rally
+type ReorderEventsRequest struct {
+	EventIDs []string `json:"eventIds"` // Every live event of the rally, in the desired visit order
+} //@name ReorderEventsRequest
+
 // EventResponse represents the API response for an event
 type EventResponse struct {
 	ID            string     `json:"id" example:"507f1f77bcf86cd799439011"`
@@ -60,4 +74,31 @@ type EventResponse struct {
 	VisitOrder    int        `json:"visitOrder" example:"1"`
 	CreatedAt     time.Time  `json:"createdAt" example:"2025-01-15T10:30:00Z"`
 	UpdatedAt     time.Time  `json:"updatedAt" example:"2025-01-15T10:30:00Z"`
+	DeletedAt     *time.Time `json:"deletedAt,omitempty" example:"2025-01-20T10:30:00Z"`
+	Version       int64      `json:"version" example:"3"`
 } //@name EventResponse
+
+// EventListResponse represents the API response for a rally's events list
+type EventListResponse struct {
+	Events []EventResponse `json:"events"`
+	Total  int             `json:"total" example:"5"`
+} //@name EventListResponse
+
+// ItineraryEventResponse represents an event with its nested activities in a rally itinerary
+type ItineraryEventResponse struct {
+	*EventResponse
+	Activities []ActivityResponse `json:"activities"`
+} //@name ItineraryEventResponse
+
+// ItineraryResponse represents the full itinerary of a rally: events ordered by visit order,
+// each with its activities ordered by activity order
+type ItineraryResponse struct {
+	RallyID string                   `json:"rallyId" example:"507f1f77bcf86cd799439012"`
+	Events  []ItineraryEventResponse `json:"events"`
+} //@name ItineraryResponse
+
+// TrashResponse represents the soft-deleted content of a rally that can still be restored
+type TrashResponse struct {
+	Events     []EventResponse    `json:"events"`
+	Activities []ActivityResponse `json:"activities"`
+} //@name TrashResponse
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExchangeRateScope tells whether a rate belongs to a single rally's table or the global one
+type ExchangeRateScope string
+
+const (
+	ExchangeRateScopeRally  ExchangeRateScope = "rally"  // Maintained by the rally's owners and editors, takes precedence
+	ExchangeRateScopeGlobal ExchangeRateScope = "global" // Maintained by admins, used when a rally has no rate of its own
+)
+
+// ExchangeRate is one row of an offline exchange rate table: one unit of From is worth Rate units of To.
+// A rate also converts To back into From at its inverse, so a table holds at most one rate per pair.
+type ExchangeRate struct {
+	ID        primitive.ObjectID  `json:"id" bson:"_id"`
+	RallyID   *primitive.ObjectID `json:"rallyId,omitempty" bson:"rally_id"` // Nil for the global table
+	From      string              `json:"from" bson:"from"`
+	To        string              `json:"to" bson:"to"`
+	Rate      string              `json:"rate" bson:"rate"` // Decimal string, kept exact for conversions
+	UpdatedBy primitive.ObjectID  `json:"updatedBy" bson:"updated_by"`
+	UpdatedAt time.Time           `json:"updatedAt" bson:"updated_at"`
+}
+
+// ExchangeRateInput represents one rate of an uploaded or edited table
+type ExchangeRateInput struct {
+	From string `json:"from" example:"USD"`
+	To   string `json:"to" example:"VND"`
+	Rate string `json:"rate" example:"25400"` // Major units of To per major unit of From, as a decimal string
+} //@name ExchangeRateInput
+
+// ReplaceExchangeRatesRequest represents the request payload for uploading a whole exchange rate table
+type ReplaceExchangeRatesRequest struct {
+	Rates []ExchangeRateInput `json:"rates"`
+} //@name ReplaceExchangeRatesRequest
+
+// SetExchangeRateRequest represents the request payload for adding or editing a single rate
+type SetExchangeRateRequest struct {
+	Rate string `json:"rate" example:"25400"`
+} //@name SetExchangeRateRequest
+
+// ExchangeRateResponse represents the API response for an exchange rate
+type ExchangeRateResponse struct {
+	From      string            `json:"from" example:"USD"`
+	To        string            `json:"to" example:"VND"`
+	Rate      string            `json:"rate" example:"25400"`
+	Scope     ExchangeRateScope `json:"scope" example:"rally"`
+	UpdatedBy string            `json:"updatedBy" example:"507f1f77bcf86cd799439014"`
+	UpdatedAt time.Time         `json:"updatedAt" example:"2025-06-30T08:00:00Z"`
+} //@name ExchangeRateResponse
+
+// ExchangeRateListResponse represents an exchange rate table
+type ExchangeRateListResponse struct {
+	Rates []ExchangeRateResponse `json:"rates"`
+} //@name ExchangeRateListResponse
@@ -0,0 +1,171 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExpenseSplitMode determines how an expense is divided between the participants involved
+type ExpenseSplitMode string
+
+const (
+	ExpenseSplitEqual  ExpenseSplitMode = "equal"  // Everyone involved owes the same amount
+	ExpenseSplitShares ExpenseSplitMode = "shares" // Amounts are proportional to each participant's shares
+	ExpenseSplitExact  ExpenseSplitMode = "exact"  // Each participant's amount is given explicitly
+)
+
+// ExpenseCategory groups expenses for budgeting
+type ExpenseCategory string
+
+const (
+	ExpenseCategoryLodging   ExpenseCategory = "lodging"
+	ExpenseCategoryFood      ExpenseCategory = "food"
+	ExpenseCategoryTransport ExpenseCategory = "transport"
+	ExpenseCategoryTickets   ExpenseCategory = "tickets"
+	ExpenseCategoryOther     ExpenseCategory = "other"
+)
+
+// ExpenseCategories lists every expense category in display order
+var ExpenseCategories = []ExpenseCategory{
+	ExpenseCategoryLodging,
+	ExpenseCategoryFood,
+	ExpenseCategoryTransport,
+	ExpenseCategoryTickets,
+	ExpenseCategoryOther,
+}
+
+// Expense is money one participant paid on behalf of others during a rally. Amounts are integers
+// in the minor unit of the currency (e.g. cents for USD, dong for VND) so splits never lose precision.
+type Expense struct {
+	ID          primitive.ObjectID  `json:"id" bson:"_id"`
+	RallyID     primitive.ObjectID  `json:"rallyId" bson:"rally_id"`
+	Description string              `json:"description" bson:"description"`
+	Amount      int64               `json:"amount" bson:"amount"`
+	Currency    string              `json:"currency" bson:"currency"` // ISO 4217 code
+	Category    ExpenseCategory     `json:"category" bson:"category"`
+	PaidBy      primitive.ObjectID  `json:"paidBy" bson:"paid_by"` // User ID of the payer
+	SplitMode   ExpenseSplitMode    `json:"splitMode" bson:"split_mode"`
+	Splits      []ExpenseSplit      `json:"splits" bson:"splits"`
+	EventID     *primitive.ObjectID `json:"eventId,omitempty" bson:"event_id,omitempty"`
+	ActivityID  *primitive.ObjectID `json:"activityId,omitempty" bson:"activity_id,omitempty"`
+	SpentAt     time.Time           `json:"spentAt" bson:"spent_at"`
+	CreatedBy   primitive.ObjectID  `json:"createdBy" bson:"created_by"`
+	CreatedAt   time.Time           `json:"createdAt" bson:"created_at"`
+	UpdatedAt   time.Time           `json:"updatedAt" bson:"updated_at"`
+}
+
+// ExpenseSplit is one participant's part of an expense. Amount is always resolved, whatever the split mode.
+type ExpenseSplit struct {
+	UserID primitive.ObjectID `json:"userId" bson:"user_id"`
+	Shares int64              `json:"shares,omitempty" bson:"shares,omitempty"` // Only used by the shares mode
+	Amount int64              `json:"amount" bson:"amount"`
+}
+
+// ExpenseSplitRequest names a participant involved in an expense. Shares is required by the shares
+// mode and Amount by the exact mode; both are ignored by the equal mode.
+type ExpenseSplitRequest struct {
+	UserID string `json:"userId" example:"507f1f77bcf86cd799439014"`
+	Shares int64  `json:"shares,omitempty" example:"2"`
+	Amount int64  `json:"amount,omitempty" example:"150000"`
+} //@name ExpenseSplitRequest
+
+// CreateExpenseRequest represents the request payload for recording an expense
+type CreateExpenseRequest struct {
+	Description string                `json:"description" example:"Dinner at the night market"`
+	Amount      int64                 `json:"amount" example:"450000"` // In minor units of the currency
+	Currency    string                `json:"currency" example:"VND"`
+	Category    ExpenseCategory       `json:"category,omitempty" example:"food"`                   // Defaults to other
+	PaidBy      string                `json:"paidBy,omitempty" example:"507f1f77bcf86cd799439014"` // Defaults to the caller
+	SplitMode   ExpenseSplitMode      `json:"splitMode" example:"equal"`
+	Splits      []ExpenseSplitRequest `json:"splits,omitempty"` // Defaults to every joined participant, split equally
+	EventID     string                `json:"eventId,omitempty" example:"507f1f77bcf86cd799439012"`
+	ActivityID  string                `json:"activityId,omitempty" example:"507f1f77bcf86cd799439013"`
+	SpentAt     *time.Time            `json:"spentAt,omitempty" example:"2025-07-01T19:30:00Z"` // Defaults to now
+} //@name CreateExpenseRequest
+
+// UpdateExpenseRequest represents the request payload for updating an expense. Splits are recomputed
+// when the amount, split mode or splits change; an empty eventId or activityId removes the link.
+// Linking an activity also links the expense to the activity's event.
+type UpdateExpenseRequest struct {
+	Description *string                `json:"description,omitempty"`
+	Amount      *int64                 `json:"amount,omitempty"`
+	Currency    *string                `json:"currency,omitempty"`
+	Category    *ExpenseCategory       `json:"category,omitempty"`
+	PaidBy      *string                `json:"paidBy,omitempty"`
+	SplitMode   *ExpenseSplitMode      `json:"splitMode,omitempty"`
+	Splits      *[]ExpenseSplitRequest `json:"splits,omitempty"`
+	EventID     *string                `json:"eventId,omitempty"`
+	ActivityID  *string                `json:"activityId,omitempty"`
+	SpentAt     *time.Time             `json:"spentAt,omitempty"`
+} //@name UpdateExpenseRequest
+
+// ExpenseSplitResponse represents one participant's part of an expense
+type ExpenseSplitResponse struct {
+	UserID string `json:"userId" example:"507f1f77bcf86cd799439014"`
+	Shares int64  `json:"shares,omitempty" example:"2"`
+	Amount int64  `json:"amount" example:"150000"`
+} //@name ExpenseSplitResponse
+
+// ExpenseResponse represents the API response for an expense
+type ExpenseResponse struct {
+	ID          string                 `json:"id" example:"507f1f77bcf86cd799439011"`
+	RallyID     string                 `json:"rallyId" example:"507f1f77bcf86cd799439012"`
+	Description string                 `json:"description" example:"Dinner at the night market"`
+	Amount      int64                  `json:"amount" example:"450000"`
+	Currency    string                 `json:"currency" example:"VND"`
+	Category    ExpenseCategory        `json:"category" example:"food"`
+	PaidBy      string                 `json:"paidBy" example:"507f1f77bcf86cd799439014"`
+	SplitMode   ExpenseSplitMode       `json:"splitMode" example:"equal"`
+	Splits      []ExpenseSplitResponse `json:"splits"`
+	EventID     string                 `json:"eventId,omitempty" example:"507f1f77bcf86cd799439015"`
+	ActivityID  string                 `json:"activityId,omitempty" example:"507f1f77bcf86cd799439016"`
+	SpentAt     time.Time              `json:"spentAt" example:"2025-07-01T19:30:00Z"`
+	CreatedBy   string                 `json:"createdBy" example:"507f1f77bcf86cd799439014"`
+	CreatedAt   time.Time              `json:"createdAt" example:"2025-07-01T20:00:00Z"`
+	UpdatedAt   time.Time              `json:"updatedAt" example:"2025-07-01T20:00:00Z"`
+} //@name ExpenseResponse
+
+// ExpenseListResponse represents a paginated list of a rally's expenses, most recent first
+type ExpenseListResponse struct {
+	Expenses   []ExpenseResponse  `json:"expenses"`
+	Total      int                `json:"total" example:"42"`
+	Page       int                `json:"page" example:"1"`
+	PageSize   int                `json:"pageSize" example:"20"`
+	TotalPages int                `json:"totalPages" example:"3"`
+	Pagination PaginationMetadata `json:"pagination"`
+} //@name ExpenseListResponse
+
+// ParticipantBalance is where a participant stands in one currency. A positive net means the
+// participant is owed money, a negative net means they owe money.
+type ParticipantBalance struct {
+	UserID string `json:"userId" example:"507f1f77bcf86cd799439014"`
+	Paid   int64  `json:"paid" example:"450000"`
+	Owed   int64  `json:"owed" example:"150000"`
+	Net    int64  `json:"net" example:"300000"`
+} //@name ParticipantBalance
+
+// SettlementTransfer is one payment of the settle-up plan
+type SettlementTransfer struct {
+	FromUserID string `json:"fromUserId" example:"507f1f77bcf86cd799439017"`
+	ToUserID   string `json:"toUserId" example:"507f1f77bcf86cd799439014"`
+	Amount     int64  `json:"amount" example:"150000"`
+} //@name SettlementTransfer
+
+// CurrencyBalances holds the balances and settle-up plan of the expenses paid in one currency
+type CurrencyBalances struct {
+	Currency  string               `json:"currency" example:"VND"`
+	Balances  []ParticipantBalance `json:"balances"`
+	Transfers []SettlementTransfer `json:"transfers"`
+} //@name CurrencyBalances
+
+// RallyBalancesResponse represents the net balances of a rally's participants and the transfers
+// that settle them. With a base currency, Currencies holds the base currency first, followed by any
+// currency listed in MissingRates that could not be converted; otherwise it holds one entry per currency.
+type RallyBalancesResponse struct {
+	RallyID      string                 `json:"rallyId" example:"507f1f77bcf86cd799439012"`
+	BaseCurrency string                 `json:"baseCurrency,omitempty" example:"VND"`
+	Currencies   []CurrencyBalances     `json:"currencies"`
+	Rates        []ExchangeRateResponse `json:"rates"`        // Exchange rates used for the conversions
+	MissingRates []string               `json:"missingRates"` // Currencies with no rate to the base currency
+} //@name RallyBalancesResponse
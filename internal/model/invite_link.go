@@ -6,44 +6,71 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// InviteLinkDeactivationReason records why an invite link stopped being usable
+type InviteLinkDeactivationReason string
+
+const (
+	InviteLinkDeactivatedRevoked InviteLinkDeactivationReason = "revoked"  // Revoked by an owner/editor
+	InviteLinkDeactivatedExpired InviteLinkDeactivationReason = "expired"  // Auto-deactivated after ExpiresAt passed
+	InviteLinkDeactivatedMaxUses InviteLinkDeactivationReason = "max_uses" // Auto-deactivated after reaching MaxUses
+)
+
 // InviteLink represents a stateful link/QR token that can be used to join a rally
 type InviteLink struct {
-	ID          primitive.ObjectID `json:"id" bson:"_id"`
-	RallyID     primitive.ObjectID `json:"rallyId" bson:"rally_id"`
-	CreatedBy   primitive.ObjectID `json:"createdBy" bson:"created_by"`
-	Token       string             `json:"token" bson:"token"` // Unique UUID or secure random string
-	RoleToGrant ParticipantRole    `json:"roleToGrant" bson:"role_to_grant"`
-	ExpiresAt   *time.Time         `json:"expiresAt,omitempty" bson:"expires_at,omitempty"`
-	MaxUses     int                `json:"maxUses" bson:"max_uses"`         // 0 means unlimited
-	CurrentUses int                `json:"currentUses" bson:"current_uses"` // How many times it has been used
-	IsActive    bool               `json:"isActive" bson:"is_active"`
-	CreatedAt   time.Time          `json:"createdAt" bson:"created_at"`
+	ID               primitive.ObjectID `json:"id" bson:"_id"`
+	RallyID          primitive.ObjectID `json:"rallyId" bson:"rally_id"`
+	CreatedBy        primitive.ObjectID `json:"createdBy" bson:"created_by"`
+	Token            string             `json:"token" bson:"token"` // Unique UUID or secure random string
+	RoleToGrant      ParticipantRole    `json:"roleToGrant" bson:"role_to_grant"`
+	ExpiresAt        *time.Time         `json:"expiresAt,omitempty" bson:"expires_at,omitempty"`
+	MaxUses          int                `json:"maxUses" bson:"max_uses"`         // 0 means unlimited
+	CurrentUses      int                `json:"currentUses" bson:"current_uses"` // How many times it has been used
+	IsActive         bool               `json:"isActive" bson:"is_active"`
+	CreatedAt        time.Time          `json:"createdAt" bson:"created_at"`
+	RequiresApproval bool               `json:"requiresApproval" bson:"requires_approval"` // Redemptions create a pending join request instead of joining
+	PreviewCount     int64              `json:"previewCount" bson:"preview_count"`         // How many times the preview card was loaded
+
+	DeactivatedReason InviteLinkDeactivationReason `json:"deactivatedReason,omitempty" bson:"deactivated_reason,omitempty"`
 }
 
 // CreateInviteLinkRequest represents the request to create a new invite link/QR
 type CreateInviteLinkRequest struct {
-	Role          ParticipantRole `json:"role" validate:"omitempty,oneof=owner editor participant" example:"participant"`
-	ExpiresInDays *int            `json:"expiresInDays,omitempty" validate:"omitempty,min=1,max=365" example:"7"`
-	MaxUses       int             `json:"maxUses" validate:"omitempty,min=0" example:"10"` // 0 for unlimited
+	Role             ParticipantRole `json:"role" validate:"omitempty,oneof=owner editor participant" example:"participant"`
+	ExpiresInDays    *int            `json:"expiresInDays,omitempty" validate:"omitempty,min=1,max=365" example:"7"`
+	MaxUses          int             `json:"maxUses" validate:"omitempty,min=0" example:"10"` // 0 for unlimited
+	RequiresApproval bool            `json:"requiresApproval,omitempty" example:"false"`      // Joins must be approved by an owner/editor
 } //@name CreateInviteLinkRequest
 
+// UpdateInviteLinkRequest represents the request to edit an existing invite link without reissuing its token.
+// Limits can only be loosened and the role can only be downgraded.
+type UpdateInviteLinkRequest struct {
+	Role          *ParticipantRole `json:"role,omitempty" validate:"omitempty,oneof=owner editor participant" example:"participant"`
+	ExpiresInDays *int             `json:"expiresInDays,omitempty" validate:"omitempty,min=1,max=365" example:"14"` // New expiry counted from now, links without one cannot be given one
+	MaxUses       *int             `json:"maxUses,omitempty" validate:"omitempty,min=0" example:"20"`               // 0 for unlimited
+} //@name UpdateInviteLinkRequest
+
 // InviteLinkResponse represents the API response for an invite link
 type InviteLinkResponse struct {
-	ID          string          `json:"id" example:"507f1f77bcf86cd799439011"`
-	RallyID     string          `json:"rallyId" example:"507f1f77bcf86cd799439012"`
-	CreatedBy   string          `json:"createdBy" example:"507f1f77bcf86cd799439013"`
-	Token       string          `json:"token" example:"e7b8e9d0-f1a2-4b3c-9d8e-f7a6b5c4d3e2"`
-	RoleToGrant ParticipantRole `json:"roleToGrant" example:"participant"`
-	ExpiresAt   *time.Time      `json:"expiresAt,omitempty" example:"2025-01-15T10:30:00Z"`
-	MaxUses     int             `json:"maxUses" example:"10"`
-	CurrentUses int             `json:"currentUses" example:"2"`
-	IsActive    bool            `json:"isActive" example:"true"`
-	CreatedAt   time.Time       `json:"createdAt" example:"2025-01-01T10:30:00Z"`
+	ID               string          `json:"id" example:"507f1f77bcf86cd799439011"`
+	RallyID          string          `json:"rallyId" example:"507f1f77bcf86cd799439012"`
+	CreatedBy        string          `json:"createdBy" example:"507f1f77bcf86cd799439013"`
+	Token            string          `json:"token" example:"e7b8e9d0-f1a2-4b3c-9d8e-f7a6b5c4d3e2"`
+	RoleToGrant      ParticipantRole `json:"roleToGrant" example:"participant"`
+	ExpiresAt        *time.Time      `json:"expiresAt,omitempty" example:"2025-01-15T10:30:00Z"`
+	MaxUses          int             `json:"maxUses" example:"10"`
+	CurrentUses      int             `json:"currentUses" example:"2"`
+	IsActive         bool            `json:"isActive" example:"true"`
+	CreatedAt        time.Time       `json:"createdAt" example:"2025-01-01T10:30:00Z"`
+	RequiresApproval bool            `json:"requiresApproval" example:"false"`
+	PreviewCount     int64           `json:"previewCount" example:"40"`
+
+	DeactivatedReason InviteLinkDeactivationReason `json:"deactivatedReason,omitempty" example:"expired"`
 } //@name InviteLinkResponse
 
 // JoinViaLinkRequest represents the request to join a rally via an invite link/QR
 type JoinViaLinkRequest struct {
-	Token string `json:"token" validate:"required" example:"e7b8e9d0-f1a2-4b3c-9d8e-f7a6b5c4d3e2"`
+	Token   string `json:"token" validate:"required" example:"e7b8e9d0-f1a2-4b3c-9d8e-f7a6b5c4d3e2"`
+	Message string `json:"message,omitempty" validate:"omitempty,max=500" example:"Friend of Anna, would love to come along"` // Shown to owners/editors when the link requires approval
 } //@name JoinViaLinkRequest
 
 // JoinViaLinkResponse represents the API response after successfully joining via a link
@@ -74,6 +101,7 @@ type InviteLinkPreviewResponse struct {
 	EndDate           *time.Time             `json:"endDate,omitempty" example:"2025-07-15T00:00:00Z"`
 	Owner             InviteLinkPreviewOwner `json:"owner"`
 	RoleOffered       ParticipantRole        `json:"roleOffered" example:"participant"`
+	RequiresApproval  bool                   `json:"requiresApproval" example:"false"`
 	MemberCount       int64                  `json:"memberCount" example:"5"`
 	EventCount        int64                  `json:"eventCount" example:"3"`
 	ParticipantID     string                 `json:"participantId" example:"507f1f77bcf86cd799439015"`
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InviteLinkRedemption records one successful use of an invite link
+type InviteLinkRedemption struct {
+	ID         primitive.ObjectID  `json:"id" bson:"_id"`
+	LinkID     primitive.ObjectID  `json:"linkId" bson:"link_id"`
+	RallyID    primitive.ObjectID  `json:"rallyId" bson:"rally_id"`
+	UserID     primitive.ObjectID  `json:"userId" bson:"user_id"`
+	Role       ParticipantRole     `json:"role" bson:"role"`     // Role the redemption resulted in
+	Status     ParticipationStatus `json:"status" bson:"status"` // joined, or pending for links that require approval
+	RedeemedAt time.Time           `json:"redeemedAt" bson:"redeemed_at"`
+}
+
+// InviteLinkRedemptionResponse represents a single redemption of an invite link, with the redeeming user
+type InviteLinkRedemptionResponse struct {
+	ID         string               `json:"id" example:"507f1f77bcf86cd799439011"`
+	User       *ParticipantUserInfo `json:"user,omitempty"`
+	Role       ParticipantRole      `json:"role" example:"participant"`
+	Status     ParticipationStatus  `json:"status" example:"joined"`
+	RedeemedAt time.Time            `json:"redeemedAt" example:"2025-01-15T10:30:00Z"`
+} //@name InviteLinkRedemptionResponse
+
+// InviteLinkStats aggregates how an invite link has performed
+type InviteLinkStats struct {
+	Previews     int64   `json:"previews" example:"40"`    // Successful preview card loads
+	Redemptions  int64   `json:"redemptions" example:"12"` // Joins plus join requests
+	Joins        int64   `json:"joins" example:"10"`       // Redemptions that joined the rally directly
+	JoinRequests int64   `json:"joinRequests" example:"2"` // Redemptions that filed a join request
+	Conversion   float64 `json:"conversion" example:"0.3"` // Redemptions per preview (0 when never previewed)
+} //@name InviteLinkStats
+
+// InviteLinkRedemptionListResponse represents the API response for an invite link's redemption history
+type InviteLinkRedemptionListResponse struct {
+	Token       string                         `json:"token" example:"e7b8e9d0-f1a2-4b3c-9d8e-f7a6b5c4d3e2"`
+	Stats       InviteLinkStats                `json:"stats"`
+	Redemptions []InviteLinkRedemptionResponse `json:"redemptions"`
+	Total       int                            `json:"total" example:"12"`
+	Page        int                            `json:"page" example:"1"`
+	PageSize    int                            `json:"pageSize" example:"20"`
+	TotalPages  int                            `json:"totalPages" example:"1"`
+	Pagination  PaginationMetadata             `json:"pagination"`
+} //@name InviteLinkRedemptionListResponse
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NotificationType represents what a notification is about
+type NotificationType string
+
+const (
+	NotificationTypeFollow           NotificationType = "follow"             // Someone followed the user
+	NotificationTypeRallyInvitation  NotificationType = "rally_invitation"   // The user was invited to a rally
+	NotificationTypeRoleChanged      NotificationType = "role_changed"       // The user's role in a rally changed
+	NotificationTypeJoinedViaLink    NotificationType = "joined_via_link"    // Someone joined through a link the user created
+	NotificationTypeJoinRequest      NotificationType = "join_request"       // Someone asked to join through a link the user created
+	NotificationTypeEventTimeChanged NotificationType = "event_time_changed" // An event in one of the user's rallies was rescheduled
+	NotificationTypeCommentMention   NotificationType = "comment_mention"    // Someone mentioned the user in a comment
+)
+
+// Notification is an entry in a user's in-app notification center
+type Notification struct {
+	ID        primitive.ObjectID     `json:"id" bson:"_id"`
+	UserID    primitive.ObjectID     `json:"userId" bson:"user_id"` // Recipient
+	Type      NotificationType       `json:"type" bson:"type"`
+	ActorID   *primitive.ObjectID    `json:"actorId,omitempty" bson:"actor_id,omitempty"`
+	RallyID   *primitive.ObjectID    `json:"rallyId,omitempty" bson:"rally_id,omitempty"`
+	EventID   *primitive.ObjectID    `json:"eventId,omitempty" bson:"event_id,omitempty"`
+	Data      map[string]interface{} `json:"data,omitempty" bson:"data,omitempty"` // Type-specific details, e.g. the new role
+	ReadAt    *time.Time             `json:"readAt,omitempty" bson:"read_at"`
+	CreatedAt time.Time              `json:"createdAt" bson:"created_at"`
+}
+
+// NotificationResponse represents a notification with its actor and rally resolved for display
+type NotificationResponse struct {
+	ID        string                 `json:"id" example:"507f1f77bcf86cd799439011"`
+	Type      NotificationType       `json:"type" example:"rally_invitation"`
+	Actor     *ParticipantUserInfo   `json:"actor,omitempty"`
+	RallyID   string                 `json:"rallyId,omitempty" example:"507f1f77bcf86cd799439012"`
+	RallyName string                 `json:"rallyName,omitempty" example:"Summer Road Trip"`
+	EventID   string                 `json:"eventId,omitempty" example:"507f1f77bcf86cd799439013"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	IsRead    bool                   `json:"isRead" example:"false"`
+	ReadAt    *time.Time             `json:"readAt,omitempty" example:"2025-01-15T11:00:00Z"`
+	CreatedAt time.Time              `json:"createdAt" example:"2025-01-15T10:30:00Z"`
+} //@name NotificationResponse
+
+// NotificationListResponse represents a page of the user's notifications, newest first
+type NotificationListResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	UnreadCount   int64                  `json:"unreadCount" example:"3"`
+	NextCursor    string                 `json:"nextCursor,omitempty" example:"507f1f77bcf86cd799439011"` // Pass as cursor to load the next page
+	HasMore       bool                   `json:"hasMore" example:"true"`
+} //@name NotificationListResponse
+
+// UnreadNotificationCountResponse represents the number of unread notifications
+type UnreadNotificationCountResponse struct {
+	UnreadCount int64 `json:"unreadCount" example:"3"`
+} //@name UnreadNotificationCountResponse
@@ -0,0 +1,125 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PollStatus represents whether a poll still accepts votes
+type PollStatus string
+
+const (
+	PollStatusOpen   PollStatus = "open"
+	PollStatusClosed PollStatus = "closed" // Closed by an owner or editor; a passed deadline only stops voting
+)
+
+// Poll lets the participants of a rally vote between options, e.g. restaurants or routes, optionally
+// for a specific event
+type Poll struct {
+	ID              primitive.ObjectID  `json:"id" bson:"_id"`
+	RallyID         primitive.ObjectID  `json:"rallyId" bson:"rally_id"`
+	EventID         *primitive.ObjectID `json:"eventId,omitempty" bson:"event_id,omitempty"`
+	Question        string              `json:"question" bson:"question"`
+	Options         []PollOption        `json:"options" bson:"options"`
+	MultipleChoice  bool                `json:"multipleChoice" bson:"multiple_choice"`
+	Anonymous       bool                `json:"anonymous" bson:"anonymous"` // Voters are never exposed, only counts
+	Deadline        *time.Time          `json:"deadline,omitempty" bson:"deadline,omitempty"`
+	Status          PollStatus          `json:"status" bson:"status"`
+	WinningOptionID *primitive.ObjectID `json:"winningOptionId,omitempty" bson:"winning_option_id,omitempty"`
+	ActivityID      *primitive.ObjectID `json:"activityId,omitempty" bson:"activity_id,omitempty"` // Activity created from the winning option
+	ClosedBy        *primitive.ObjectID `json:"closedBy,omitempty" bson:"closed_by,omitempty"`
+	ClosedAt        *time.Time          `json:"closedAt,omitempty" bson:"closed_at,omitempty"`
+	CreatedBy       primitive.ObjectID  `json:"createdBy" bson:"created_by"`
+	CreatedAt       time.Time           `json:"createdAt" bson:"created_at"`
+	UpdatedAt       time.Time           `json:"updatedAt" bson:"updated_at"`
+}
+
+// PollOption is one choice of a poll. The place fields are copied to the activity created from it.
+type PollOption struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id"`
+	Text          string             `json:"text" bson:"text"`
+	GooglePlaceID string             `json:"googlePlaceId,omitempty" bson:"google_place_id,omitempty"`
+	Lat           float64            `json:"lat,omitempty" bson:"lat,omitempty"`
+	Lng           float64            `json:"lng,omitempty" bson:"lng,omitempty"`
+}
+
+// PollOptionRequest represents one option of a new poll
+type PollOptionRequest struct {
+	Text          string  `json:"text" example:"Phở Thìn"`
+	GooglePlaceID string  `json:"googlePlaceId,omitempty" example:"ChIJN1t_tDeuEmsRUsoyG83frY4"`
+	Lat           float64 `json:"lat,omitempty" example:"21.0245"`
+	Lng           float64 `json:"lng,omitempty" example:"105.8412"`
+} //@name PollOptionRequest
+
+// CreatePollRequest represents the request payload for creating a poll
+type CreatePollRequest struct {
+	EventID        string              `json:"eventId,omitempty" example:"507f1f77bcf86cd799439015"`
+	Question       string              `json:"question" example:"Where do we eat tonight?"`
+	Options        []PollOptionRequest `json:"options"`
+	MultipleChoice bool                `json:"multipleChoice,omitempty" example:"false"`
+	Anonymous      bool                `json:"anonymous,omitempty" example:"false"`
+	Deadline       *time.Time          `json:"deadline,omitempty" example:"2025-07-01T17:00:00Z"`
+} //@name CreatePollRequest
+
+// VotePollRequest represents a participant's vote; it replaces any previous vote of theirs
+type VotePollRequest struct {
+	OptionIDs []string `json:"optionIds"`
+} //@name VotePollRequest
+
+// ClosePollRequest represents the request payload for closing a poll. OptionID picks the winner among
+// options tied for the most votes. With CreateActivity, the winning option becomes an activity of the
+// poll's event, or of EventID when the poll is not attached to one.
+type ClosePollRequest struct {
+	OptionID       string     `json:"optionId,omitempty" example:"507f1f77bcf86cd799439016"`
+	CreateActivity bool       `json:"createActivity,omitempty" example:"true"`
+	EventID        string     `json:"eventId,omitempty" example:"507f1f77bcf86cd799439015"`
+	StartTime      *time.Time `json:"startTime,omitempty" example:"2025-07-01T19:00:00Z"`
+	EndTime        *time.Time `json:"endTime,omitempty" example:"2025-07-01T21:00:00Z"`
+	ActivityOrder  int        `json:"activityOrder,omitempty" example:"3"`
+} //@name ClosePollRequest
+
+// PollOptionResponse represents an option of a poll with its results. Voters is left out for
+// anonymous polls.
+type PollOptionResponse struct {
+	ID            string   `json:"id" example:"507f1f77bcf86cd799439016"`
+	Text          string   `json:"text" example:"Phở Thìn"`
+	GooglePlaceID string   `json:"googlePlaceId,omitempty" example:"ChIJN1t_tDeuEmsRUsoyG83frY4"`
+	Lat           float64  `json:"lat,omitempty" example:"21.0245"`
+	Lng           float64  `json:"lng,omitempty" example:"105.8412"`
+	VoteCount     int      `json:"voteCount" example:"4"`
+	Voters        []string `json:"voters,omitempty"`
+} //@name PollOptionResponse
+
+// PollResponse represents the API response for a poll with its current results
+type PollResponse struct {
+	ID              string               `json:"id" example:"507f1f77bcf86cd799439011"`
+	RallyID         string               `json:"rallyId" example:"507f1f77bcf86cd799439012"`
+	EventID         string               `json:"eventId,omitempty" example:"507f1f77bcf86cd799439015"`
+	Question        string               `json:"question" example:"Where do we eat tonight?"`
+	Options         []PollOptionResponse `json:"options"`
+	MultipleChoice  bool                 `json:"multipleChoice" example:"false"`
+	Anonymous       bool                 `json:"anonymous" example:"false"`
+	Deadline        *time.Time           `json:"deadline,omitempty" example:"2025-07-01T17:00:00Z"`
+	Status          PollStatus           `json:"status" example:"open"`
+	VotingOpen      bool                 `json:"votingOpen" example:"true"` // False once closed or past the deadline
+	TotalVoters     int                  `json:"totalVoters" example:"6"`
+	MyVote          []string             `json:"myVote,omitempty"` // Option IDs the caller voted for
+	WinningOptionID string               `json:"winningOptionId,omitempty" example:"507f1f77bcf86cd799439016"`
+	ActivityID      string               `json:"activityId,omitempty" example:"507f1f77bcf86cd799439017"`
+	ClosedBy        string               `json:"closedBy,omitempty" example:"507f1f77bcf86cd799439014"`
+	ClosedAt        *time.Time           `json:"closedAt,omitempty" example:"2025-07-01T17:05:00Z"`
+	CreatedBy       string               `json:"createdBy" example:"507f1f77bcf86cd799439014"`
+	CreatedAt       time.Time            `json:"createdAt" example:"2025-07-01T10:00:00Z"`
+	UpdatedAt       time.Time            `json:"updatedAt" example:"2025-07-01T10:00:00Z"`
+} //@name PollResponse
+
+// PollListResponse represents a paginated list of a rally's polls, newest first
+type PollListResponse struct {
+	Polls      []PollResponse     `json:"polls"`
+	Total      int                `json:"total" example:"8"`
+	Page       int                `json:"page" example:"1"`
+	PageSize   int                `json:"pageSize" example:"20"`
+	TotalPages int                `json:"totalPages" example:"1"`
+	Pagination PaginationMetadata `json:"pagination"`
+} //@name PollListResponse
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PollVote is one participant's ballot in a poll. Voters of anonymous polls are stored too, so a
+// participant can change their vote, but they are never exposed.
+type PollVote struct {
+	ID        primitive.ObjectID   `json:"id" bson:"_id"`
+	PollID    primitive.ObjectID   `json:"pollId" bson:"poll_id"`
+	RallyID   primitive.ObjectID   `json:"rallyId" bson:"rally_id"`
+	UserID    primitive.ObjectID   `json:"userId" bson:"user_id"`
+	OptionIDs []primitive.ObjectID `json:"optionIds" bson:"option_ids"`
+	CreatedAt time.Time            `json:"createdAt" bson:"created_at"`
+	UpdatedAt time.Time            `json:"updatedAt" bson:"updated_at"`
+}
@@ -27,8 +27,11 @@ type Rally struct {
 	Status        RallyStatus        `json:"status" bson:"status"`
 	StartDate     *time.Time         `json:"startDate" bson:"start_date"`
 	EndDate       *time.Time         `json:"endDate" bson:"end_date"`
+	BaseCurrency  string             `json:"baseCurrency,omitempty" bson:"base_currency,omitempty"` // ISO 4217 code balances are settled in
 	CreatedAt     time.Time          `json:"createdAt" bson:"created_at"`
 	UpdatedAt     time.Time          `json:"updatedAt" bson:"updated_at"`
+	DeletedAt     *time.Time         `json:"deletedAt,omitempty" bson:"deleted_at,omitempty"` // Set when moved to trash
+	Version       int64              `json:"version" bson:"version"`                          // Incremented on every update, exposed as ETag
 }
 
 // CreateRallyRequest represents the request payload for creating a rally
@@ -38,6 +41,7 @@ type CreateRallyRequest struct {
 	CoverImageUrl string                     `json:"coverImageUrl,omitempty"`
 	StartDate     *time.Time                 `json:"startDate,omitempty"`
 	EndDate       *time.Time                 `json:"endDate,omitempty"`
+	BaseCurrency  string                     `json:"baseCurrency,omitempty"`
 	Participants  []InviteParticipantRequest `json:"participants,omitempty"`
 } //@name CreateRallyRequest
 
@@ -49,6 +53,7 @@ type UpdateRallyRequest struct {
 	Status        *RallyStatus `json:"status,omitempty"`
 	StartDate     *time.Time   `json:"startDate,omitempty"`
 	EndDate       *time.Time   `json:"endDate,omitempty"`
+	BaseCurrency  *string      `json:"baseCurrency,omitempty"`
 } //@name UpdateRallyRequest
 
 // RallyResponse represents the API response for a rally
@@ -61,8 +66,11 @@ type RallyResponse struct {
 	Status        RallyStatus `json:"status" example:"draft"`
 	StartDate     *time.Time  `json:"startDate,omitempty" example:"2025-07-01T00:00:00Z"`
 	EndDate       *time.Time  `json:"endDate,omitempty" example:"2025-07-15T00:00:00Z"`
+	BaseCurrency  string      `json:"baseCurrency,omitempty" example:"VND"`
 	CreatedAt     time.Time   `json:"createdAt" example:"2025-01-15T10:30:00Z"`
 	UpdatedAt     time.Time   `json:"updatedAt" example:"2025-01-15T10:30:00Z"`
+	DeletedAt     *time.Time  `json:"deletedAt,omitempty" example:"2025-01-20T10:30:00Z"`
+	Version       int64       `json:"version" example:"3"`
 } //@name RallyResponse
 
 // RallyJoinResponse represents the API response for GetRally, including user's role and status
@@ -23,23 +23,27 @@ const (
 	ParticipationStatusJoined   ParticipationStatus = "joined"
 	ParticipationStatusDeclined ParticipationStatus = "declined"
 	ParticipationStatusLeft     ParticipationStatus = "left"
+	ParticipationStatusBanned   ParticipationStatus = "banned"  // Removed by an owner/editor and barred from rejoining
+	ParticipationStatusPending  ParticipationStatus = "pending" // Asked to join through an approval-only invite link
 )
 
 // RallyParticipant represents a participant entry in a rally
 type RallyParticipant struct {
-	ID        primitive.ObjectID  `json:"id" bson:"_id"`
-	RallyID   primitive.ObjectID  `json:"rallyId" bson:"rally_id"`
-	UserID    primitive.ObjectID  `json:"userId" bson:"user_id"`
-	Role      ParticipantRole     `json:"role" bson:"role"`
-	Status    ParticipationStatus `json:"status" bson:"status"`
-	InvitedBy *primitive.ObjectID `json:"invitedBy" bson:"invited_by"`
-	JoinedAt  *time.Time          `json:"joinedAt" bson:"joined_at"`
-	InvitedAt time.Time           `json:"invitedAt" bson:"invited_at"`
+	ID             primitive.ObjectID  `json:"id" bson:"_id"`
+	RallyID        primitive.ObjectID  `json:"rallyId" bson:"rally_id"`
+	UserID         primitive.ObjectID  `json:"userId" bson:"user_id"`
+	Role           ParticipantRole     `json:"role" bson:"role"`
+	Status         ParticipationStatus `json:"status" bson:"status"`
+	InvitedBy      *primitive.ObjectID `json:"invitedBy" bson:"invited_by"`
+	JoinedAt       *time.Time          `json:"joinedAt" bson:"joined_at"`
+	InvitedAt      time.Time           `json:"invitedAt" bson:"invited_at"`
+	RequestMessage string              `json:"requestMessage,omitempty" bson:"request_message,omitempty"` // Optional note left with a pending join request
 }
 
 // InviteParticipantRequest represents the request to invite a user to a rally
 type InviteParticipantRequest struct {
-	UserID string           `json:"userId"`
+	UserID string           `json:"userId,omitempty"`
+	Email  string           `json:"email,omitempty" example:"anna@example.com"` // Invite by email instead; works for people without an account
 	Role   *ParticipantRole `json:"role,omitempty"`
 } //@name InviteParticipantRequest
 
@@ -49,6 +53,11 @@ type UpdateParticipantRequest struct {
 	Status *ParticipationStatus `json:"status,omitempty"`
 } //@name UpdateParticipantRequest
 
+// TransferOwnershipRequest represents the request to hand a rally over to another participant
+type TransferOwnershipRequest struct {
+	ParticipantID string `json:"participantId"`
+} //@name TransferOwnershipRequest
+
 // RallyParticipantResponse represents the API response for a rally participant
 type RallyParticipantResponse struct {
 	ID        string              `json:"id" example:"507f1f77bcf86cd799439011"`
@@ -72,14 +81,15 @@ type ParticipantUserInfo struct {
 
 // RallyParticipantDetailResponse represents a detailed participant entry including user info
 type RallyParticipantDetailResponse struct {
-	ID        string               `json:"id" example:"507f1f77bcf86cd799439011"`
-	RallyID   string               `json:"rallyId" example:"507f1f77bcf86cd799439012"`
-	Role      ParticipantRole      `json:"role" example:"participant"`
-	Status    ParticipationStatus  `json:"status" example:"joined"`
-	JoinedAt  *time.Time           `json:"joinedAt,omitempty" example:"2025-01-15T10:30:00Z"`
-	InvitedAt time.Time            `json:"invitedAt" example:"2025-01-15T10:30:00Z"`
-	User      ParticipantUserInfo  `json:"user"`
-	InvitedBy *ParticipantUserInfo `json:"invitedBy,omitempty"`
+	ID             string               `json:"id" example:"507f1f77bcf86cd799439011"`
+	RallyID        string               `json:"rallyId" example:"507f1f77bcf86cd799439012"`
+	Role           ParticipantRole      `json:"role" example:"participant"`
+	Status         ParticipationStatus  `json:"status" example:"joined"`
+	JoinedAt       *time.Time           `json:"joinedAt,omitempty" example:"2025-01-15T10:30:00Z"`
+	InvitedAt      time.Time            `json:"invitedAt" example:"2025-01-15T10:30:00Z"`
+	User           ParticipantUserInfo  `json:"user"`
+	InvitedBy      *ParticipantUserInfo `json:"invitedBy,omitempty"`
+	RequestMessage string               `json:"requestMessage,omitempty" example:"Friend of Anna, would love to come along"`
 } //@name RallyParticipantDetailResponse
 
 // ParticipantListResponse represents the API response for participants list
@@ -112,3 +122,11 @@ type PendingInvitationsResponse struct {
 	Invitations []PendingInvitationItem `json:"invitations"`
 	Total       int                     `json:"total" example:"3"`
 } //@name PendingInvitationsResponse
+
+// TransferOwnershipResponse represents the API response after transferring rally ownership
+type TransferOwnershipResponse struct {
+	RallyID       string                   `json:"rallyId" example:"507f1f77bcf86cd799439012"`
+	OwnerID       string                   `json:"ownerId" example:"507f1f77bcf86cd799439013"`
+	NewOwner      RallyParticipantResponse `json:"newOwner"`
+	PreviousOwner RallyParticipantResponse `json:"previousOwner"`
+} //@name TransferOwnershipResponse
@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// RealtimeMessageType identifies what changed in a rally; the format is "<resource>.<change>"
+type RealtimeMessageType string
+
+const (
+	RealtimeRallyUpdated RealtimeMessageType = "rally.updated"
+	RealtimeRallyDeleted RealtimeMessageType = "rally.deleted"
+
+	RealtimeEventCreated    RealtimeMessageType = "event.created"
+	RealtimeEventUpdated    RealtimeMessageType = "event.updated"
+	RealtimeEventDeleted    RealtimeMessageType = "event.deleted"
+	RealtimeEventRestored   RealtimeMessageType = "event.restored"
+	RealtimeEventsReordered RealtimeMessageType = "events.reordered"
+
+	RealtimeActivityCreated     RealtimeMessageType = "activity.created"
+	RealtimeActivityUpdated     RealtimeMessageType = "activity.updated"
+	RealtimeActivityDeleted     RealtimeMessageType = "activity.deleted"
+	RealtimeActivityRestored    RealtimeMessageType = "activity.restored"
+	RealtimeActivitiesReordered RealtimeMessageType = "activities.reordered"
+
+	RealtimeParticipantAdded   RealtimeMessageType = "participant.added"
+	RealtimeParticipantUpdated RealtimeMessageType = "participant.updated"
+	RealtimeParticipantRemoved RealtimeMessageType = "participant.removed"
+
+	RealtimeExpenseCreated RealtimeMessageType = "expense.created"
+	RealtimeExpenseUpdated RealtimeMessageType = "expense.updated"
+	RealtimeExpenseDeleted RealtimeMessageType = "expense.deleted"
+
+	RealtimeBudgetCreated RealtimeMessageType = "budget.created"
+	RealtimeBudgetUpdated RealtimeMessageType = "budget.updated"
+	RealtimeBudgetDeleted RealtimeMessageType = "budget.deleted"
+
+	RealtimePollCreated RealtimeMessageType = "poll.created"
+	RealtimePollUpdated RealtimeMessageType = "poll.updated" // A vote changed the results
+	RealtimePollClosed  RealtimeMessageType = "poll.closed"
+	RealtimePollDeleted RealtimeMessageType = "poll.deleted"
+
+	RealtimeCommentCreated RealtimeMessageType = "comment.created"
+	RealtimeCommentUpdated RealtimeMessageType = "comment.updated"
+	RealtimeCommentDeleted RealtimeMessageType = "comment.deleted"
+
+	RealtimeExchangeRatesUpdated RealtimeMessageType = "exchange_rates.updated" // Data is the rally's whole exchange rate table
+)
+
+// RealtimeMessage is pushed to every client subscribed to a rally when something in it changes.
+// Data holds the changed resource in the same shape the REST API returns it, or a
+// RealtimeDeletedData for deletions. Participant messages also carry the affected user and their
+// status in typed fields, so subscribers can be cut off without decoding Data, which arrives as
+// plain JSON through a cross-instance broker.
+type RealtimeMessage struct {
+	Type              RealtimeMessageType `json:"type" example:"event.updated"`
+	RallyID           string              `json:"rallyId" example:"507f1f77bcf86cd799439012"`
+	ActorID           string              `json:"actorId,omitempty" example:"507f1f77bcf86cd799439013"`
+	UserID            string              `json:"userId,omitempty" example:"507f1f77bcf86cd799439014"` // Set on participant messages
+	ParticipantStatus ParticipationStatus `json:"participantStatus,omitempty" example:"left"`          // Status after the change; empty once removed
+	Data              interface{}         `json:"data,omitempty"`
+	SentAt            time.Time           `json:"sentAt" example:"2025-01-15T10:30:00Z"`
+} //@name RealtimeMessage
+
+// RealtimeDeletedData identifies a resource that was deleted (or removed from the rally)
+type RealtimeDeletedData struct {
+	ID     string `json:"id" example:"507f1f77bcf86cd799439011"`
+	UserID string `json:"userId,omitempty" example:"507f1f77bcf86cd799439014"` // Set for removed participants
+} //@name RealtimeDeletedData
@@ -0,0 +1,67 @@
+package realtime
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+)
+
+// subscriberBuffer is how many messages a subscriber may fall behind before messages to it are dropped
+const subscriberBuffer = 32
+
+// MemoryPubSub is an in-process PubSub for single-instance deployments and tests
+type MemoryPubSub struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan *model.RealtimeMessage]struct{}
+}
+
+// NewMemoryPubSub creates an in-process PubSub with no subscribers
+func NewMemoryPubSub() *MemoryPubSub {
+	return &MemoryPubSub{
+		subscribers: make(map[string]map[chan *model.RealtimeMessage]struct{}),
+	}
+}
+
+// Publish delivers msg to every current subscriber of its rally without blocking. A subscriber whose
+// buffer is full misses the message rather than stalling the publisher.
+func (p *MemoryPubSub) Publish(ctx context.Context, msg *model.RealtimeMessage) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for ch := range p.subscribers[msg.RallyID] {
+		select {
+		case ch <- msg:
+		default:
+			log.Printf("[realtime] dropped %s for a slow subscriber of rally %s", msg.Type, msg.RallyID)
+		}
+	}
+	return nil
+}
+
+// Subscribe registers a new subscriber for a rally
+func (p *MemoryPubSub) Subscribe(ctx context.Context, rallyID string) (*Subscription, error) {
+	ch := make(chan *model.RealtimeMessage, subscriberBuffer)
+
+	p.mu.Lock()
+	if p.subscribers[rallyID] == nil {
+		p.subscribers[rallyID] = make(map[chan *model.RealtimeMessage]struct{})
+	}
+	p.subscribers[rallyID][ch] = struct{}{}
+	p.mu.Unlock()
+
+	var once sync.Once
+	return NewSubscription(ch, func() {
+		once.Do(func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+
+			delete(p.subscribers[rallyID], ch)
+			if len(p.subscribers[rallyID]) == 0 {
+				delete(p.subscribers, rallyID)
+			}
+			close(ch)
+		})
+	}), nil
+}
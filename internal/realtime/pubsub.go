@@ -0,0 +1,31 @@
+package realtime
+
+import (
+	"context"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+)
+
+// PubSub fans rally updates out to every subscriber of a rally. Implementations backed by a shared
+// broker (e.g. Redis) deliver across server instances; MemoryPubSub only reaches subscribers of the
+// instance that published.
+type PubSub interface {
+	Publish(ctx context.Context, msg *model.RealtimeMessage) error
+	Subscribe(ctx context.Context, rallyID string) (*Subscription, error)
+}
+
+// Subscription receives the messages published to one rally until it is closed
+type Subscription struct {
+	C     <-chan *model.RealtimeMessage
+	close func()
+}
+
+// NewSubscription wraps a message channel and the function that releases it, for PubSub implementations
+func NewSubscription(c <-chan *model.RealtimeMessage, close func()) *Subscription {
+	return &Subscription{C: c, close: close}
+}
+
+// Close stops delivery to the subscription. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.close()
+}
@@ -9,12 +9,24 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type ActivityRepository interface {
 	CreateActivity(ctx context.Context, activity *model.Activity) error
 	GetActivityByID(ctx context.Context, activityID string) (*model.Activity, error)
-	UpdateActivity(ctx context.Context, activityID string, updates *model.UpdateActivityRequest) (*model.Activity, error)
+	UpdateActivity(ctx context.Context, activityID string, updates *model.UpdateActivityRequest, expectedVersion *int64) (*model.Activity, error)
+	GetActivitiesByEvent(ctx context.Context, eventID primitive.ObjectID) ([]model.Activity, error)
+	DeleteActivity(ctx context.Context, activityID string) error
+	DeleteActivitiesByEvents(ctx context.Context, eventIDs []primitive.ObjectID) error
+	SoftDeleteActivity(ctx context.Context, activityID string, deletedAt time.Time) error
+	SoftDeleteActivitiesByEvents(ctx context.Context, eventIDs []primitive.ObjectID, deletedAt time.Time) error
+	RestoreActivity(ctx context.Context, activityID string) error
+	RestoreActivitiesByEvents(ctx context.Context, eventIDs []primitive.ObjectID, deletedAt time.Time) error
+	GetDeletedActivityByID(ctx context.Context, activityID string) (*model.Activity, error)
+	GetDeletedActivitiesByEvents(ctx context.Context, eventIDs []primitive.ObjectID) ([]model.Activity, error)
+	PurgeDeletedActivities(ctx context.Context, deletedBefore time.Time) error
+	SetActivitiesOrder(ctx context.Context, eventID primitive.ObjectID, orderedIDs []primitive.ObjectID) (int64, error)
 }
 
 type activityRepository struct {
@@ -41,6 +53,9 @@ func (r *activityRepository) CreateActivity(ctx context.Context, activity *model
 	if activity.UpdatedAt.IsZero() {
 		activity.UpdatedAt = now
 	}
+	if activity.Version == 0 {
+		activity.Version = 1
+	}
 
 	_, err := r.collection.InsertOne(ctx, activity)
 	return err
@@ -53,7 +68,7 @@ func (r *activityRepository) GetActivityByID(ctx context.Context, activityID str
 	}
 
 	var activity model.Activity
-	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&activity)
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID, "deleted_at": nil}).Decode(&activity)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, nil
@@ -63,7 +78,7 @@ func (r *activityRepository) GetActivityByID(ctx context.Context, activityID str
 	return &activity, nil
 }
 
-func (r *activityRepository) UpdateActivity(ctx context.Context, activityID string, updates *model.UpdateActivityRequest) (*model.Activity, error) {
+func (r *activityRepository) UpdateActivity(ctx context.Context, activityID string, updates *model.UpdateActivityRequest, expectedVersion *int64) (*model.Activity, error) {
 	objectID, err := primitive.ObjectIDFromHex(activityID)
 	if err != nil {
 		return nil, err
@@ -104,14 +119,238 @@ func (r *activityRepository) UpdateActivity(ctx context.Context, activityID stri
 		updateDoc["activity_order"] = *updates.ActivityOrder
 	}
 
-	_, err = r.collection.UpdateOne(
+	update := bson.M{"$set": updateDoc, "$inc": bson.M{"version": 1}}
+	if len(updates.Unset) > 0 {
+		unsetDoc := bson.M{}
+		for _, field := range updates.Unset {
+			unsetDoc[field] = ""
+		}
+		update["$unset"] = unsetDoc
+	}
+
+	filter := withExpectedVersion(bson.M{"_id": objectID, "deleted_at": nil}, expectedVersion)
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated model.Activity
+	err = r.collection.FindOneAndUpdate(
+		ctx,
+		filter,
+		update,
+		opts,
+	).Decode(&updated)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// Not found, or the expected version is stale
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// GetActivitiesByEvent retrieves all activities of an event ordered by activity order
+func (r *activityRepository) GetActivitiesByEvent(ctx context.Context, eventID primitive.ObjectID) ([]model.Activity, error) {
+	opts := options.Find().SetSort(bson.D{
+		{Key: "activity_order", Value: 1},
+		{Key: "created_at", Value: 1},
+	})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"event_id": eventID, "deleted_at": nil}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var activities []model.Activity
+	if err := cursor.All(ctx, &activities); err != nil {
+		return nil, err
+	}
+	if activities == nil {
+		activities = []model.Activity{}
+	}
+
+	return activities, nil
+}
+
+// DeleteActivity permanently removes an activity document
+func (r *activityRepository) DeleteActivity(ctx context.Context, activityID string) error {
+	objectID, err := primitive.ObjectIDFromHex(activityID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("activity not found")
+	}
+
+	return nil
+}
+
+// DeleteActivitiesByEvents permanently removes all activities belonging to any of the given events
+func (r *activityRepository) DeleteActivitiesByEvents(ctx context.Context, eventIDs []primitive.ObjectID) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+
+	_, err := r.collection.DeleteMany(ctx, bson.M{"event_id": bson.M{"$in": eventIDs}})
+	return err
+}
+
+// SoftDeleteActivity moves an activity to the trash by setting its deleted_at marker
+func (r *activityRepository) SoftDeleteActivity(ctx context.Context, activityID string, deletedAt time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(activityID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(
 		ctx,
-		bson.M{"_id": objectID},
-		bson.M{"$set": updateDoc},
+		bson.M{"_id": objectID, "deleted_at": nil},
+		bson.M{"$set": bson.M{"deleted_at": deletedAt}},
 	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("activity not found")
+	}
+
+	return nil
+}
+
+// SoftDeleteActivitiesByEvents moves all live activities of the given events to the trash with the same deleted_at marker
+func (r *activityRepository) SoftDeleteActivitiesByEvents(ctx context.Context, eventIDs []primitive.ObjectID, deletedAt time.Time) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"event_id": bson.M{"$in": eventIDs}, "deleted_at": nil},
+		bson.M{"$set": bson.M{"deleted_at": deletedAt}},
+	)
+	return err
+}
+
+// RestoreActivity moves an activity out of the trash by removing its deleted_at marker
+func (r *activityRepository) RestoreActivity(ctx context.Context, activityID string) error {
+	objectID, err := primitive.ObjectIDFromHex(activityID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objectID, "deleted_at": bson.M{"$ne": nil}},
+		bson.M{
+			"$unset": bson.M{"deleted_at": ""},
+			"$set":   bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("activity not found in trash")
+	}
+
+	return nil
+}
+
+// RestoreActivitiesByEvents restores the activities of the given events that were trashed together with
+// their parent, identified by sharing the parent's deleted_at marker
+func (r *activityRepository) RestoreActivitiesByEvents(ctx context.Context, eventIDs []primitive.ObjectID, deletedAt time.Time) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"event_id": bson.M{"$in": eventIDs}, "deleted_at": deletedAt},
+		bson.M{
+			"$unset": bson.M{"deleted_at": ""},
+			"$set":   bson.M{"updated_at": time.Now()},
+		},
+	)
+	return err
+}
+
+// GetDeletedActivityByID finds an activity that is currently in the trash
+func (r *activityRepository) GetDeletedActivityByID(ctx context.Context, activityID string) (*model.Activity, error) {
+	objectID, err := primitive.ObjectIDFromHex(activityID)
 	if err != nil {
 		return nil, err
 	}
 
-	return r.GetActivityByID(ctx, activityID)
+	var activity model.Activity
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID, "deleted_at": bson.M{"$ne": nil}}).Decode(&activity)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &activity, nil
+}
+
+// GetDeletedActivitiesByEvents retrieves the trashed activities of the given events, most recently deleted first
+func (r *activityRepository) GetDeletedActivitiesByEvents(ctx context.Context, eventIDs []primitive.ObjectID) ([]model.Activity, error) {
+	if len(eventIDs) == 0 {
+		return []model.Activity{}, nil
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"event_id": bson.M{"$in": eventIDs}, "deleted_at": bson.M{"$ne": nil}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var activities []model.Activity
+	if err := cursor.All(ctx, &activities); err != nil {
+		return nil, err
+	}
+	if activities == nil {
+		activities = []model.Activity{}
+	}
+
+	return activities, nil
+}
+
+// PurgeDeletedActivities permanently removes activities moved to the trash before the given time
+func (r *activityRepository) PurgeDeletedActivities(ctx context.Context, deletedBefore time.Time) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"deleted_at": bson.M{"$lt": deletedBefore}})
+	return err
+}
+
+// SetActivitiesOrder rewrites the activity order of the given live activities of an event to match their
+// position in the slice (1-based) in a single bulk write. Returns the number of activities matched.
+func (r *activityRepository) SetActivitiesOrder(ctx context.Context, eventID primitive.ObjectID, orderedIDs []primitive.ObjectID) (int64, error) {
+	if len(orderedIDs) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	models := make([]mongo.WriteModel, len(orderedIDs))
+	for i, id := range orderedIDs {
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id, "event_id": eventID, "deleted_at": nil}).
+			SetUpdate(bson.M{"$set": bson.M{"activity_order": i + 1, "updated_at": now}, "$inc": bson.M{"version": 1}})
+	}
+
+	result, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(true))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.MatchedCount, nil
 }
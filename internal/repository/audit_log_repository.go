@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type AuditLogRepository interface {
+	CreateAuditLog(ctx context.Context, entry *model.AuditLog) error
+	GetAuditLogsByRally(ctx context.Context, rallyID primitive.ObjectID, page, pageSize int) ([]model.AuditLogResponse, int64, error)
+	GetAuditLogByID(ctx context.Context, entryID string) (*model.AuditLog, error)
+	HasLaterFieldChanges(ctx context.Context, entry *model.AuditLog, targetID primitive.ObjectID, fields []string) (bool, error)
+}
+
+type auditLogRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewAuditLogRepository initializes a MongoDB-backed AuditLogRepository
+func NewAuditLogRepository(db *mongo.Database) AuditLogRepository {
+	return &auditLogRepository{
+		db:         db,
+		collection: db.Collection("audit_logs"),
+	}
+}
+
+// CreateAuditLog appends a new entry to the audit log. Entries are never updated or deleted.
+func (r *auditLogRepository) CreateAuditLog(ctx context.Context, entry *model.AuditLog) error {
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	if entry.Changes == nil {
+		entry.Changes = []model.FieldChange{}
+	}
+
+	_, err := r.collection.InsertOne(ctx, entry)
+	return err
+}
+
+// GetAuditLogsByRally retrieves a page of a rally's audit log, newest first, with actor info
+func (r *auditLogRepository) GetAuditLogsByRally(ctx context.Context, rallyID primitive.ObjectID, page, pageSize int) ([]model.AuditLogResponse, int64, error) {
+	skip := (page - 1) * pageSize
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"rally_id": rallyID}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "created_at", Value: -1},
+			{Key: "_id", Value: -1},
+		}}},
+		{{Key: "$facet", Value: bson.M{
+			"metadata": []bson.M{{"$count": "total"}},
+			"data": []bson.M{
+				{"$skip": skip},
+				{"$limit": pageSize},
+				{"$lookup": bson.M{
+					"from":         "users",
+					"localField":   "actor_id",
+					"foreignField": "_id",
+					"as":           "actor_info",
+				}},
+				{"$unwind": bson.M{
+					"path":                       "$actor_info",
+					"preserveNullAndEmptyArrays": true,
+				}},
+			},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	type rawAuditLog struct {
+		model.AuditLog `bson:",inline"`
+		ActorInfo      *struct {
+			ID        primitive.ObjectID `bson:"_id"`
+			Username  string             `bson:"username"`
+			FirstName string             `bson:"first_name"`
+			LastName  string             `bson:"last_name"`
+			AvatarUrl string             `bson:"avatar_url"`
+		} `bson:"actor_info"`
+	}
+
+	type FacetResult struct {
+		Metadata []struct {
+			Total int64 `bson:"total"`
+		} `bson:"metadata"`
+		Data []rawAuditLog `bson:"data"`
+	}
+
+	var results []FacetResult
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, 0, err
+	}
+
+	if len(results) == 0 {
+		return []model.AuditLogResponse{}, 0, nil
+	}
+
+	total := int64(0)
+	if len(results[0].Metadata) > 0 {
+		total = results[0].Metadata[0].Total
+	}
+
+	data := results[0].Data
+	responses := make([]model.AuditLogResponse, len(data))
+	for i, raw := range data {
+		responses[i] = model.AuditLogResponse{
+			ID:         raw.ID.Hex(),
+			RallyID:    raw.RallyID.Hex(),
+			Action:     raw.Action,
+			TargetType: raw.TargetType,
+			TargetID:   raw.TargetID.Hex(),
+			Changes:    raw.Changes,
+			CreatedAt:  raw.CreatedAt,
+		}
+		if responses[i].Changes == nil {
+			responses[i].Changes = []model.FieldChange{}
+		}
+		if raw.ActorInfo != nil {
+			responses[i].Actor = &model.ParticipantUserInfo{
+				ID:        raw.ActorInfo.ID.Hex(),
+				Username:  raw.ActorInfo.Username,
+				FirstName: raw.ActorInfo.FirstName,
+				LastName:  raw.ActorInfo.LastName,
+				AvatarUrl: raw.ActorInfo.AvatarUrl,
+			}
+		}
+	}
+
+	return responses, total, nil
+}
+
+// GetAuditLogByID finds a single audit entry
+func (r *auditLogRepository) GetAuditLogByID(ctx context.Context, entryID string) (*model.AuditLog, error) {
+	objectID, err := primitive.ObjectIDFromHex(entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry model.AuditLog
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&entry)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// HasLaterFieldChanges reports whether any entry recorded after the given one changed one of the
+// given fields on targetID
+func (r *auditLogRepository) HasLaterFieldChanges(ctx context.Context, entry *model.AuditLog, targetID primitive.ObjectID, fields []string) (bool, error) {
+	filter := bson.M{
+		"target_id":     targetID,
+		"changes.field": bson.M{"$in": fields},
+		"$or": []bson.M{
+			{"created_at": bson.M{"$gt": entry.CreatedAt}},
+			{"created_at": entry.CreatedAt, "_id": bson.M{"$gt": entry.ID}},
+		},
+	}
+
+	count, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
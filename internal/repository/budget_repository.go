@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type BudgetRepository interface {
+	CreateBudget(ctx context.Context, budget *model.Budget) error
+	GetBudgetByID(ctx context.Context, budgetID string) (*model.Budget, error)
+	GetBudget(ctx context.Context, rallyID primitive.ObjectID, eventID *primitive.ObjectID, category model.ExpenseCategory) (*model.Budget, error)
+	GetBudgetsByRally(ctx context.Context, rallyID primitive.ObjectID) ([]model.Budget, error)
+	UpdateBudget(ctx context.Context, budget *model.Budget) (*model.Budget, error)
+	DeleteBudget(ctx context.Context, budgetID primitive.ObjectID) error
+	DeleteBudgetsByRally(ctx context.Context, rallyID primitive.ObjectID) error
+}
+
+type budgetRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewBudgetRepository initializes a MongoDB-backed BudgetRepository
+func NewBudgetRepository(db *mongo.Database) BudgetRepository {
+	return &budgetRepository{
+		db:         db,
+		collection: db.Collection("budgets"),
+	}
+}
+
+// CreateBudget inserts a new budget
+func (r *budgetRepository) CreateBudget(ctx context.Context, budget *model.Budget) error {
+	if budget.ID.IsZero() {
+		budget.ID = primitive.NewObjectID()
+	}
+
+	now := time.Now()
+	if budget.CreatedAt.IsZero() {
+		budget.CreatedAt = now
+	}
+	if budget.UpdatedAt.IsZero() {
+		budget.UpdatedAt = now
+	}
+
+	_, err := r.collection.InsertOne(ctx, budget)
+	return err
+}
+
+// GetBudgetByID finds a budget by its ID
+func (r *budgetRepository) GetBudgetByID(ctx context.Context, budgetID string) (*model.Budget, error) {
+	objectID, err := primitive.ObjectIDFromHex(budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.findOne(ctx, bson.M{"_id": objectID})
+}
+
+// GetBudget finds the budget of a category for a rally (nil eventID) or one of its events
+func (r *budgetRepository) GetBudget(ctx context.Context, rallyID primitive.ObjectID, eventID *primitive.ObjectID, category model.ExpenseCategory) (*model.Budget, error) {
+	return r.findOne(ctx, bson.M{"rally_id": rallyID, "event_id": eventID, "category": category})
+}
+
+// GetBudgetsByRally retrieves every budget of a rally, rally-wide budgets first
+func (r *budgetRepository) GetBudgetsByRally(ctx context.Context, rallyID primitive.ObjectID) ([]model.Budget, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "event_id", Value: 1}, {Key: "category", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"rally_id": rallyID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var budgets []model.Budget
+	if err := cursor.All(ctx, &budgets); err != nil {
+		return nil, err
+	}
+
+	return budgets, nil
+}
+
+// UpdateBudget replaces the editable fields of a budget and returns the updated document, or nil if
+// it no longer exists
+func (r *budgetRepository) UpdateBudget(ctx context.Context, budget *model.Budget) (*model.Budget, error) {
+	update := bson.M{"$set": bson.M{
+		"category":   budget.Category,
+		"amount":     budget.Amount,
+		"currency":   budget.Currency,
+		"notes":      budget.Notes,
+		"updated_at": time.Now(),
+	}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated model.Budget
+	if err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": budget.ID}, update, opts).Decode(&updated); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeleteBudget permanently removes a budget
+func (r *budgetRepository) DeleteBudget(ctx context.Context, budgetID primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": budgetID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("budget not found")
+	}
+
+	return nil
+}
+
+// DeleteBudgetsByRally removes every budget of a rally, used when the rally is purged
+func (r *budgetRepository) DeleteBudgetsByRally(ctx context.Context, rallyID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"rally_id": rallyID})
+	return err
+}
+
+// findOne decodes the first budget matching filter, or returns nil if there is none
+func (r *budgetRepository) findOne(ctx context.Context, filter bson.M) (*model.Budget, error) {
+	var budget model.Budget
+	if err := r.collection.FindOne(ctx, filter).Decode(&budget); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &budget, nil
+}
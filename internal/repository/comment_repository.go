@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CommentRepository interface {
+	CreateComment(ctx context.Context, comment *model.Comment) error
+	GetCommentByID(ctx context.Context, commentID string) (*model.Comment, error)
+	GetThreads(ctx context.Context, targetType model.CommentTargetType, targetID primitive.ObjectID, page, pageSize int) ([]model.CommentWithAuthor, int64, error)
+	GetReplies(ctx context.Context, parentID primitive.ObjectID, page, pageSize int) ([]model.CommentWithAuthor, int64, error)
+	UpdateComment(ctx context.Context, commentID primitive.ObjectID, body string, mentions []primitive.ObjectID) (*model.Comment, error)
+	IncrementReplyCount(ctx context.Context, commentID primitive.ObjectID, delta int) (*model.Comment, error)
+	SoftDeleteComment(ctx context.Context, commentID primitive.ObjectID, deletedBy primitive.ObjectID) (*model.Comment, error)
+	DeleteComment(ctx context.Context, commentID primitive.ObjectID) error
+	DeleteCommentsByRally(ctx context.Context, rallyID primitive.ObjectID) error
+}
+
+type commentRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewCommentRepository initializes a MongoDB-backed CommentRepository
+func NewCommentRepository(db *mongo.Database) CommentRepository {
+	return &commentRepository{
+		db:         db,
+		collection: db.Collection("comments"),
+	}
+}
+
+// CreateComment inserts a new comment
+func (r *commentRepository) CreateComment(ctx context.Context, comment *model.Comment) error {
+	if comment.ID.IsZero() {
+		comment.ID = primitive.NewObjectID()
+	}
+
+	now := time.Now()
+	if comment.CreatedAt.IsZero() {
+		comment.CreatedAt = now
+	}
+	if comment.UpdatedAt.IsZero() {
+		comment.UpdatedAt = now
+	}
+
+	_, err := r.collection.InsertOne(ctx, comment)
+	return err
+}
+
+// GetCommentByID finds a comment by its ID
+func (r *commentRepository) GetCommentByID(ctx context.Context, commentID string) (*model.Comment, error) {
+	objectID, err := primitive.ObjectIDFromHex(commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var comment model.Comment
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&comment); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// GetThreads retrieves a page of the top-level comments on a rally, event or activity with their
+// authors, newest first
+func (r *commentRepository) GetThreads(ctx context.Context, targetType model.CommentTargetType, targetID primitive.ObjectID, page, pageSize int) ([]model.CommentWithAuthor, int64, error) {
+	filter := bson.M{"target_type": targetType, "target_id": targetID, "parent_id": nil}
+	return r.list(ctx, filter, -1, page, pageSize)
+}
+
+// GetReplies retrieves a page of the replies in a thread with their authors, oldest first
+func (r *commentRepository) GetReplies(ctx context.Context, parentID primitive.ObjectID, page, pageSize int) ([]model.CommentWithAuthor, int64, error) {
+	return r.list(ctx, bson.M{"parent_id": parentID}, 1, page, pageSize)
+}
+
+// UpdateComment replaces the body and mentions of a comment that has not been deleted and returns the
+// updated document, or nil if there is none
+func (r *commentRepository) UpdateComment(ctx context.Context, commentID primitive.ObjectID, body string, mentions []primitive.ObjectID) (*model.Comment, error) {
+	now := time.Now()
+	return r.update(ctx, bson.M{"_id": commentID, "deleted_at": nil}, bson.M{"$set": bson.M{
+		"body":       body,
+		"mentions":   mentions,
+		"edited_at":  now,
+		"updated_at": now,
+	}})
+}
+
+// IncrementReplyCount adds delta to the reply count of a thread's top-level comment and returns the
+// updated document, or nil if it no longer exists
+func (r *commentRepository) IncrementReplyCount(ctx context.Context, commentID primitive.ObjectID, delta int) (*model.Comment, error) {
+	return r.update(ctx, bson.M{"_id": commentID}, bson.M{"$inc": bson.M{"reply_count": delta}})
+}
+
+// SoftDeleteComment blanks a comment that still has replies so the thread stays readable, and returns
+// the updated document, or nil if it does not exist or was already deleted
+func (r *commentRepository) SoftDeleteComment(ctx context.Context, commentID primitive.ObjectID, deletedBy primitive.ObjectID) (*model.Comment, error) {
+	now := time.Now()
+	return r.update(ctx, bson.M{"_id": commentID, "deleted_at": nil}, bson.M{
+		"$set": bson.M{
+			"body":       "",
+			"deleted_at": now,
+			"deleted_by": deletedBy,
+			"updated_at": now,
+		},
+		"$unset": bson.M{"mentions": ""},
+	})
+}
+
+// DeleteComment permanently removes a comment
+func (r *commentRepository) DeleteComment(ctx context.Context, commentID primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": commentID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("comment not found")
+	}
+
+	return nil
+}
+
+// DeleteCommentsByRally removes every comment of a rally, used when the rally is purged
+func (r *commentRepository) DeleteCommentsByRally(ctx context.Context, rallyID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"rally_id": rallyID})
+	return err
+}
+
+// list retrieves a page of the comments matching filter joined with their authors, sorted by creation
+// in the given direction
+func (r *commentRepository) list(ctx context.Context, filter bson.M, direction int, page, pageSize int) ([]model.CommentWithAuthor, int64, error) {
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: direction}, {Key: "_id", Value: direction}}}},
+		{{Key: "$skip", Value: int64((page - 1) * pageSize)}},
+		{{Key: "$limit", Value: int64(pageSize)}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "users",
+			"localField":   "author_id",
+			"foreignField": "_id",
+			"as":           "author_info",
+		}}},
+		{{Key: "$unwind", Value: bson.M{
+			"path":                       "$author_info",
+			"preserveNullAndEmptyArrays": true,
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var comments []model.CommentWithAuthor
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
+
+// update applies update to the first comment matching filter and returns the updated document, or nil
+// if there is none
+func (r *commentRepository) update(ctx context.Context, filter bson.M, update bson.M) (*model.Comment, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var comment model.Comment
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&comment); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &comment, nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DeviceRepository interface {
+	UpsertDevice(ctx context.Context, device *model.Device) (*model.Device, error)
+	GetDevicesByUsers(ctx context.Context, userIDs []primitive.ObjectID) ([]*model.Device, error)
+	DeleteDevice(ctx context.Context, userID primitive.ObjectID, token string) (bool, error)
+	DeleteDevicesByTokens(ctx context.Context, tokens []string) (int64, error)
+}
+
+type deviceRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewDeviceRepository initializes a MongoDB-backed DeviceRepository
+func NewDeviceRepository(db *mongo.Database) DeviceRepository {
+	return &deviceRepository{
+		db:         db,
+		collection: db.Collection("devices"),
+	}
+}
+
+// UpsertDevice registers a push token for device.UserID. Re-registering a known token refreshes its
+// platform and locale and moves it to the new user, since a token only ever reaches one installation.
+func (r *deviceRepository) UpsertDevice(ctx context.Context, device *model.Device) (*model.Device, error) {
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"user_id":    device.UserID,
+			"platform":   device.Platform,
+			"locale":     device.Locale,
+			"updated_at": now,
+		},
+		"$setOnInsert": bson.M{
+			"_id":        primitive.NewObjectID(),
+			"created_at": now,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var updated model.Device
+	if err := r.collection.FindOneAndUpdate(ctx, bson.M{"token": device.Token}, update, opts).Decode(&updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// GetDevicesByUsers retrieves every device registered by the given users
+func (r *deviceRepository) GetDevicesByUsers(ctx context.Context, userIDs []primitive.ObjectID) ([]*model.Device, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": bson.M{"$in": userIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var devices []*model.Device
+	if err := cursor.All(ctx, &devices); err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+}
+
+// DeleteDevice removes one of a user's push tokens, reporting whether it was registered to them
+func (r *deviceRepository) DeleteDevice(ctx context.Context, userID primitive.ObjectID, token string) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"user_id": userID, "token": token})
+	if err != nil {
+		return false, err
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+// DeleteDevicesByTokens removes push tokens regardless of owner, returning how many were removed
+func (r *deviceRepository) DeleteDevicesByTokens(ctx context.Context, tokens []string) (int64, error) {
+	if len(tokens) == 0 {
+		return 0, nil
+	}
+
+	result, err := r.collection.DeleteMany(ctx, bson.M{"token": bson.M{"$in": tokens}})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type EmailInvitationRepository interface {
+	EnsureIndexes(ctx context.Context) error
+	CreateEmailInvitation(ctx context.Context, invitation *model.EmailInvitation) error
+	GetEmailInvitation(ctx context.Context, invitationID string) (*model.EmailInvitation, error)
+	GetEmailInvitationByRallyAndEmail(ctx context.Context, rallyID primitive.ObjectID, email string) (*model.EmailInvitation, error)
+	GetEmailInvitationsByRally(ctx context.Context, rallyID primitive.ObjectID) ([]*model.EmailInvitation, error)
+	GetEmailInvitationsByEmail(ctx context.Context, email string) ([]*model.EmailInvitation, error)
+	DeleteEmailInvitation(ctx context.Context, invitationID primitive.ObjectID) error
+	DeleteStaleEmailInvitations(ctx context.Context, invitedBefore time.Time) (int64, error)
+	DeleteEmailInvitationsByRally(ctx context.Context, rallyID primitive.ObjectID) error
+}
+
+type emailInvitationRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewEmailInvitationRepository initializes a MongoDB-backed EmailInvitationRepository
+func NewEmailInvitationRepository(db *mongo.Database) EmailInvitationRepository {
+	return &emailInvitationRepository{
+		db:         db,
+		collection: db.Collection("email_invitations"),
+	}
+}
+
+// EnsureIndexes creates the unique (rally_id, email) index that keeps each email address to a single
+// invitation per rally
+func (r *emailInvitationRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "rally_id", Value: 1}, {Key: "email", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// CreateEmailInvitation inserts a new email invitation
+func (r *emailInvitationRepository) CreateEmailInvitation(ctx context.Context, invitation *model.EmailInvitation) error {
+	if invitation.ID.IsZero() {
+		invitation.ID = primitive.NewObjectID()
+	}
+	if invitation.InvitedAt.IsZero() {
+		invitation.InvitedAt = time.Now()
+	}
+
+	_, err := r.collection.InsertOne(ctx, invitation)
+	return err
+}
+
+// GetEmailInvitation finds an email invitation by its ID
+func (r *emailInvitationRepository) GetEmailInvitation(ctx context.Context, invitationID string) (*model.EmailInvitation, error) {
+	objectID, err := primitive.ObjectIDFromHex(invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.findOne(ctx, bson.M{"_id": objectID})
+}
+
+// GetEmailInvitationByRallyAndEmail finds the invitation of an email address to a rally
+func (r *emailInvitationRepository) GetEmailInvitationByRallyAndEmail(ctx context.Context, rallyID primitive.ObjectID, email string) (*model.EmailInvitation, error) {
+	return r.findOne(ctx, bson.M{"rally_id": rallyID, "email": email})
+}
+
+// GetEmailInvitationsByRally retrieves every outstanding email invitation of a rally, newest first
+func (r *emailInvitationRepository) GetEmailInvitationsByRally(ctx context.Context, rallyID primitive.ObjectID) ([]*model.EmailInvitation, error) {
+	return r.find(ctx, bson.M{"rally_id": rallyID})
+}
+
+// GetEmailInvitationsByEmail retrieves every outstanding invitation sent to an email address
+func (r *emailInvitationRepository) GetEmailInvitationsByEmail(ctx context.Context, email string) ([]*model.EmailInvitation, error) {
+	return r.find(ctx, bson.M{"email": email})
+}
+
+// DeleteEmailInvitation permanently removes an email invitation
+func (r *emailInvitationRepository) DeleteEmailInvitation(ctx context.Context, invitationID primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": invitationID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("email invitation not found")
+	}
+
+	return nil
+}
+
+// DeleteStaleEmailInvitations removes email invitations sent before invitedBefore, returning how many were removed
+func (r *emailInvitationRepository) DeleteStaleEmailInvitations(ctx context.Context, invitedBefore time.Time) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"invited_at": bson.M{"$lt": invitedBefore}})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
+
+// DeleteEmailInvitationsByRally permanently removes all email invitations of a rally
+func (r *emailInvitationRepository) DeleteEmailInvitationsByRally(ctx context.Context, rallyID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"rally_id": rallyID})
+	return err
+}
+
+func (r *emailInvitationRepository) findOne(ctx context.Context, filter bson.M) (*model.EmailInvitation, error) {
+	var invitation model.EmailInvitation
+	err := r.collection.FindOne(ctx, filter).Decode(&invitation)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil // Not found
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *emailInvitationRepository) find(ctx context.Context, filter bson.M) ([]*model.EmailInvitation, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "invited_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var invitations []*model.EmailInvitation
+	if err := cursor.All(ctx, &invitations); err != nil {
+		return nil, err
+	}
+	if invitations == nil {
+		invitations = []*model.EmailInvitation{}
+	}
+
+	return invitations, nil
+}
@@ -9,13 +9,29 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type EventRepository interface {
 	CreateEvent(ctx context.Context, event *model.Event) error
 	GetEventByID(ctx context.Context, eventID string) (*model.Event, error)
-	UpdateEvent(ctx context.Context, eventID string, updates *model.UpdateEventRequest) (*model.Event, error)
+	UpdateEvent(ctx context.Context, eventID string, updates *model.UpdateEventRequest, expectedVersion *int64) (*model.Event, error)
 	CountEventsByRally(ctx context.Context, rallyID primitive.ObjectID) (int64, error)
+	GetEventsByRally(ctx context.Context, rallyID primitive.ObjectID) ([]model.Event, error)
+	GetItineraryByRally(ctx context.Context, rallyID primitive.ObjectID) ([]model.EventWithActivities, error)
+	GetEventIDsByRally(ctx context.Context, rallyID primitive.ObjectID) ([]primitive.ObjectID, error)
+	GetAllEventIDsByRally(ctx context.Context, rallyID primitive.ObjectID) ([]primitive.ObjectID, error)
+	DeleteEvent(ctx context.Context, eventID string) error
+	DeleteEventsByRally(ctx context.Context, rallyID primitive.ObjectID) error
+	DeleteEventsByIDs(ctx context.Context, eventIDs []primitive.ObjectID) error
+	SoftDeleteEvent(ctx context.Context, eventID string, deletedAt time.Time) error
+	SoftDeleteEventsByRally(ctx context.Context, rallyID primitive.ObjectID, deletedAt time.Time) error
+	RestoreEvent(ctx context.Context, eventID string) error
+	RestoreEventsByRally(ctx context.Context, rallyID primitive.ObjectID, deletedAt time.Time) error
+	GetDeletedEventByID(ctx context.Context, eventID string) (*model.Event, error)
+	GetDeletedEventsByRally(ctx context.Context, rallyID primitive.ObjectID) ([]model.Event, error)
+	GetDeletedEventIDs(ctx context.Context, deletedBefore time.Time) ([]primitive.ObjectID, error)
+	SetEventsOrder(ctx context.Context, rallyID primitive.ObjectID, orderedIDs []primitive.ObjectID) (int64, error)
 }
 
 type eventRepository struct {
@@ -42,6 +58,9 @@ func (r *eventRepository) CreateEvent(ctx context.Context, event *model.Event) e
 	if event.UpdatedAt.IsZero() {
 		event.UpdatedAt = now
 	}
+	if event.Version == 0 {
+		event.Version = 1
+	}
 
 	_, err := r.collection.InsertOne(ctx, event)
 	return err
@@ -54,7 +73,7 @@ func (r *eventRepository) GetEventByID(ctx context.Context, eventID string) (*mo
 	}
 
 	var event model.Event
-	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&event)
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID, "deleted_at": nil}).Decode(&event)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, nil
@@ -64,7 +83,7 @@ func (r *eventRepository) GetEventByID(ctx context.Context, eventID string) (*mo
 	return &event, nil
 }
 
-func (r *eventRepository) UpdateEvent(ctx context.Context, eventID string, updates *model.UpdateEventRequest) (*model.Event, error) {
+func (r *eventRepository) UpdateEvent(ctx context.Context, eventID string, updates *model.UpdateEventRequest, expectedVersion *int64) (*model.Event, error) {
 	objectID, err := primitive.ObjectIDFromHex(eventID)
 	if err != nil {
 		return nil, err
@@ -99,18 +118,288 @@ func (r *eventRepository) UpdateEvent(ctx context.Context, eventID string, updat
 		updateDoc["visit_order"] = *updates.VisitOrder
 	}
 
-	_, err = r.collection.UpdateOne(
+	update := bson.M{"$set": updateDoc, "$inc": bson.M{"version": 1}}
+	if len(updates.Unset) > 0 {
+		unsetDoc := bson.M{}
+		for _, field := range updates.Unset {
+			unsetDoc[field] = ""
+		}
+		update["$unset"] = unsetDoc
+	}
+
+	filter := withExpectedVersion(bson.M{"_id": objectID, "deleted_at": nil}, expectedVersion)
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated model.Event
+	err = r.collection.FindOneAndUpdate(
 		ctx,
-		bson.M{"_id": objectID},
-		bson.M{"$set": updateDoc},
-	)
+		filter,
+		update,
+		opts,
+	).Decode(&updated)
 	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// Not found, or the expected version is stale
+			return nil, nil
+		}
 		return nil, err
 	}
 
-	return r.GetEventByID(ctx, eventID)
+	return &updated, nil
 }
 
 func (r *eventRepository) CountEventsByRally(ctx context.Context, rallyID primitive.ObjectID) (int64, error) {
-	return r.collection.CountDocuments(ctx, bson.M{"rally_id": rallyID})
+	return r.collection.CountDocuments(ctx, bson.M{"rally_id": rallyID, "deleted_at": nil})
+}
+
+// GetEventsByRally retrieves all events of a rally ordered by visit order
+func (r *eventRepository) GetEventsByRally(ctx context.Context, rallyID primitive.ObjectID) ([]model.Event, error) {
+	opts := options.Find().SetSort(bson.D{
+		{Key: "visit_order", Value: 1},
+		{Key: "created_at", Value: 1},
+	})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"rally_id": rallyID, "deleted_at": nil}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var events []model.Event
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	if events == nil {
+		events = []model.Event{}
+	}
+
+	return events, nil
+}
+
+// GetItineraryByRally retrieves all events of a rally ordered by visit order, each joined with
+// its activities ordered by activity order, in a single aggregation.
+func (r *eventRepository) GetItineraryByRally(ctx context.Context, rallyID primitive.ObjectID) ([]model.EventWithActivities, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"rally_id": rallyID, "deleted_at": nil}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "visit_order", Value: 1},
+			{Key: "created_at", Value: 1},
+		}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": "activities",
+			"let":  bson.M{"eventId": "$_id"},
+			"pipeline": mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{
+					"$expr":      bson.M{"$eq": bson.A{"$event_id", "$$eventId"}},
+					"deleted_at": nil,
+				}}},
+				{{Key: "$sort", Value: bson.D{
+					{Key: "activity_order", Value: 1},
+					{Key: "created_at", Value: 1},
+				}}},
+			},
+			"as": "activities",
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []model.EventWithActivities
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []model.EventWithActivities{}
+	}
+
+	return results, nil
+}
+
+// GetEventIDsByRally retrieves the IDs of all events of a rally
+func (r *eventRepository) GetEventIDsByRally(ctx context.Context, rallyID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	return findIDs(ctx, r.collection, bson.M{"rally_id": rallyID, "deleted_at": nil})
+}
+
+// GetAllEventIDsByRally retrieves the IDs of all events of a rally, including those in the trash
+func (r *eventRepository) GetAllEventIDsByRally(ctx context.Context, rallyID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	return findIDs(ctx, r.collection, bson.M{"rally_id": rallyID})
+}
+
+// DeleteEvent permanently removes an event document
+func (r *eventRepository) DeleteEvent(ctx context.Context, eventID string) error {
+	objectID, err := primitive.ObjectIDFromHex(eventID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("event not found")
+	}
+
+	return nil
+}
+
+// DeleteEventsByRally permanently removes all events of a rally
+func (r *eventRepository) DeleteEventsByRally(ctx context.Context, rallyID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"rally_id": rallyID})
+	return err
+}
+
+// DeleteEventsByIDs permanently removes the given events
+func (r *eventRepository) DeleteEventsByIDs(ctx context.Context, eventIDs []primitive.ObjectID) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+
+	_, err := r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": eventIDs}})
+	return err
+}
+
+// SoftDeleteEvent moves an event to the trash by setting its deleted_at marker
+func (r *eventRepository) SoftDeleteEvent(ctx context.Context, eventID string, deletedAt time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(eventID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objectID, "deleted_at": nil},
+		bson.M{"$set": bson.M{"deleted_at": deletedAt}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("event not found")
+	}
+
+	return nil
+}
+
+// SoftDeleteEventsByRally moves all live events of a rally to the trash with the same deleted_at marker
+func (r *eventRepository) SoftDeleteEventsByRally(ctx context.Context, rallyID primitive.ObjectID, deletedAt time.Time) error {
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"rally_id": rallyID, "deleted_at": nil},
+		bson.M{"$set": bson.M{"deleted_at": deletedAt}},
+	)
+	return err
+}
+
+// RestoreEvent moves an event out of the trash by removing its deleted_at marker
+func (r *eventRepository) RestoreEvent(ctx context.Context, eventID string) error {
+	objectID, err := primitive.ObjectIDFromHex(eventID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objectID, "deleted_at": bson.M{"$ne": nil}},
+		bson.M{
+			"$unset": bson.M{"deleted_at": ""},
+			"$set":   bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("event not found in trash")
+	}
+
+	return nil
+}
+
+// RestoreEventsByRally restores the events of a rally that were trashed together with it,
+// identified by sharing the rally's deleted_at marker. Events trashed individually stay in the trash.
+func (r *eventRepository) RestoreEventsByRally(ctx context.Context, rallyID primitive.ObjectID, deletedAt time.Time) error {
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"rally_id": rallyID, "deleted_at": deletedAt},
+		bson.M{
+			"$unset": bson.M{"deleted_at": ""},
+			"$set":   bson.M{"updated_at": time.Now()},
+		},
+	)
+	return err
+}
+
+// GetDeletedEventByID finds an event that is currently in the trash
+func (r *eventRepository) GetDeletedEventByID(ctx context.Context, eventID string) (*model.Event, error) {
+	objectID, err := primitive.ObjectIDFromHex(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	var event model.Event
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID, "deleted_at": bson.M{"$ne": nil}}).Decode(&event)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &event, nil
+}
+
+// GetDeletedEventsByRally retrieves the trashed events of a rally, most recently deleted first
+func (r *eventRepository) GetDeletedEventsByRally(ctx context.Context, rallyID primitive.ObjectID) ([]model.Event, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"rally_id": rallyID, "deleted_at": bson.M{"$ne": nil}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var events []model.Event
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	if events == nil {
+		events = []model.Event{}
+	}
+
+	return events, nil
+}
+
+// GetDeletedEventIDs retrieves the IDs of events moved to the trash before the given time
+func (r *eventRepository) GetDeletedEventIDs(ctx context.Context, deletedBefore time.Time) ([]primitive.ObjectID, error) {
+	return findIDs(ctx, r.collection, bson.M{"deleted_at": bson.M{"$lt": deletedBefore}})
+}
+
+// SetEventsOrder rewrites the visit order of the given live events of a rally to match their position
+// in the slice (1-based) in a single bulk write. Returns the number of events matched.
+func (r *eventRepository) SetEventsOrder(ctx context.Context, rallyID primitive.ObjectID, orderedIDs []primitive.ObjectID) (int64, error) {
+	if len(orderedIDs) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	models := make([]mongo.WriteModel, len(orderedIDs))
+	for i, id := range orderedIDs {
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id, "rally_id": rallyID, "deleted_at": nil}).
+			SetUpdate(bson.M{"$set": bson.M{"visit_order": i + 1, "updated_at": now}, "$inc": bson.M{"version": 1}})
+	}
+
+	result, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(true))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.MatchedCount, nil
 }
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ExchangeRateRepository stores exchange rate tables. A nil rally ID addresses the global table.
+type ExchangeRateRepository interface {
+	GetExchangeRates(ctx context.Context, rallyID *primitive.ObjectID) ([]model.ExchangeRate, error)
+	ReplaceExchangeRates(ctx context.Context, rallyID *primitive.ObjectID, rates []model.ExchangeRate) error
+	UpsertExchangeRate(ctx context.Context, rate *model.ExchangeRate) (*model.ExchangeRate, error)
+	DeleteExchangeRate(ctx context.Context, rallyID *primitive.ObjectID, from string, to string) (bool, error)
+	DeleteExchangeRatesByRally(ctx context.Context, rallyID primitive.ObjectID) error
+}
+
+type exchangeRateRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewExchangeRateRepository initializes a MongoDB-backed ExchangeRateRepository
+func NewExchangeRateRepository(db *mongo.Database) ExchangeRateRepository {
+	return &exchangeRateRepository{
+		db:         db,
+		collection: db.Collection("exchange_rates"),
+	}
+}
+
+// GetExchangeRates retrieves every rate of a table, ordered by currency pair
+func (r *exchangeRateRepository) GetExchangeRates(ctx context.Context, rallyID *primitive.ObjectID) ([]model.ExchangeRate, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "from", Value: 1}, {Key: "to", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"rally_id": rallyID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rates []model.ExchangeRate
+	if err := cursor.All(ctx, &rates); err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// ReplaceExchangeRates swaps a whole table for the given rates; callers run it in a transaction so
+// readers never see a half-uploaded table
+func (r *exchangeRateRepository) ReplaceExchangeRates(ctx context.Context, rallyID *primitive.ObjectID, rates []model.ExchangeRate) error {
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"rally_id": rallyID}); err != nil {
+		return err
+	}
+	if len(rates) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(rates))
+	for i := range rates {
+		if rates[i].ID.IsZero() {
+			rates[i].ID = primitive.NewObjectID()
+		}
+		docs[i] = rates[i]
+	}
+
+	_, err := r.collection.InsertMany(ctx, docs)
+	return err
+}
+
+// UpsertExchangeRate sets the rate of a currency pair in a table. A rate stored for the inverse pair
+// is dropped so the table never holds two rates for the same pair.
+func (r *exchangeRateRepository) UpsertExchangeRate(ctx context.Context, rate *model.ExchangeRate) (*model.ExchangeRate, error) {
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"rally_id": rate.RallyID, "from": rate.To, "to": rate.From}); err != nil {
+		return nil, err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"rate":       rate.Rate,
+			"updated_by": rate.UpdatedBy,
+			"updated_at": time.Now(),
+		},
+		"$setOnInsert": bson.M{
+			"_id": primitive.NewObjectID(),
+		},
+	}
+	filter := bson.M{"rally_id": rate.RallyID, "from": rate.From, "to": rate.To}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var updated model.ExchangeRate
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeleteExchangeRate removes the rate of a currency pair from a table, whichever direction it was
+// stored in, reporting whether there was one
+func (r *exchangeRateRepository) DeleteExchangeRate(ctx context.Context, rallyID *primitive.ObjectID, from string, to string) (bool, error) {
+	filter := bson.M{
+		"rally_id": rallyID,
+		"$or": []bson.M{
+			{"from": from, "to": to},
+			{"from": to, "to": from},
+		},
+	}
+
+	result, err := r.collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return false, err
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+// DeleteExchangeRatesByRally removes a rally's table, used when the rally is purged
+func (r *exchangeRateRepository) DeleteExchangeRatesByRally(ctx context.Context, rallyID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"rally_id": rallyID})
+	return err
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ExpenseRepository interface {
+	CreateExpense(ctx context.Context, expense *model.Expense) error
+	GetExpenseByID(ctx context.Context, expenseID string) (*model.Expense, error)
+	GetExpensesByRally(ctx context.Context, rallyID primitive.ObjectID, page, pageSize int) ([]model.Expense, int64, error)
+	GetAllExpensesByRally(ctx context.Context, rallyID primitive.ObjectID) ([]model.Expense, error)
+	UpdateExpense(ctx context.Context, expense *model.Expense) (*model.Expense, error)
+	DeleteExpense(ctx context.Context, expenseID primitive.ObjectID) error
+	DeleteExpensesByRally(ctx context.Context, rallyID primitive.ObjectID) error
+}
+
+type expenseRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewExpenseRepository initializes a MongoDB-backed ExpenseRepository
+func NewExpenseRepository(db *mongo.Database) ExpenseRepository {
+	return &expenseRepository{
+		db:         db,
+		collection: db.Collection("expenses"),
+	}
+}
+
+// CreateExpense inserts a new expense
+func (r *expenseRepository) CreateExpense(ctx context.Context, expense *model.Expense) error {
+	if expense.ID.IsZero() {
+		expense.ID = primitive.NewObjectID()
+	}
+
+	now := time.Now()
+	if expense.CreatedAt.IsZero() {
+		expense.CreatedAt = now
+	}
+	if expense.UpdatedAt.IsZero() {
+		expense.UpdatedAt = now
+	}
+
+	_, err := r.collection.InsertOne(ctx, expense)
+	return err
+}
+
+// GetExpenseByID finds an expense by its ID
+func (r *expenseRepository) GetExpenseByID(ctx context.Context, expenseID string) (*model.Expense, error) {
+	objectID, err := primitive.ObjectIDFromHex(expenseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var expense model.Expense
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&expense); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &expense, nil
+}
+
+// GetExpensesByRally retrieves a page of a rally's expenses, most recently spent first
+func (r *expenseRepository) GetExpensesByRally(ctx context.Context, rallyID primitive.ObjectID, page, pageSize int) ([]model.Expense, int64, error) {
+	filter := bson.M{"rally_id": rallyID}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize)).
+		SetSort(bson.D{{Key: "spent_at", Value: -1}, {Key: "_id", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var expenses []model.Expense
+	if err := cursor.All(ctx, &expenses); err != nil {
+		return nil, 0, err
+	}
+
+	return expenses, total, nil
+}
+
+// GetAllExpensesByRally retrieves every expense of a rally in the order they were spent
+func (r *expenseRepository) GetAllExpensesByRally(ctx context.Context, rallyID primitive.ObjectID) ([]model.Expense, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "spent_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"rally_id": rallyID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var expenses []model.Expense
+	if err := cursor.All(ctx, &expenses); err != nil {
+		return nil, err
+	}
+
+	return expenses, nil
+}
+
+// UpdateExpense replaces the editable fields of an expense and returns the updated document,
+// or nil if it no longer exists
+func (r *expenseRepository) UpdateExpense(ctx context.Context, expense *model.Expense) (*model.Expense, error) {
+	setDoc := bson.M{
+		"description": expense.Description,
+		"amount":      expense.Amount,
+		"currency":    expense.Currency,
+		"category":    expense.Category,
+		"paid_by":     expense.PaidBy,
+		"split_mode":  expense.SplitMode,
+		"splits":      expense.Splits,
+		"spent_at":    expense.SpentAt,
+		"updated_at":  time.Now(),
+	}
+	unsetDoc := bson.M{}
+	if expense.EventID != nil {
+		setDoc["event_id"] = expense.EventID
+	} else {
+		unsetDoc["event_id"] = ""
+	}
+	if expense.ActivityID != nil {
+		setDoc["activity_id"] = expense.ActivityID
+	} else {
+		unsetDoc["activity_id"] = ""
+	}
+
+	update := bson.M{"$set": setDoc}
+	if len(unsetDoc) > 0 {
+		update["$unset"] = unsetDoc
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated model.Expense
+	if err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": expense.ID}, update, opts).Decode(&updated); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeleteExpense permanently removes an expense
+func (r *expenseRepository) DeleteExpense(ctx context.Context, expenseID primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": expenseID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("expense not found")
+	}
+
+	return nil
+}
+
+// DeleteExpensesByRally removes every expense of a rally, used when the rally is purged
+func (r *expenseRepository) DeleteExpensesByRally(ctx context.Context, rallyID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"rally_id": rallyID})
+	return err
+}
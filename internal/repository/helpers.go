@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// findIDs returns the _id of every document in the collection matching the filter
+func findIDs(ctx context.Context, collection *mongo.Collection, filter bson.M) ([]primitive.ObjectID, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+
+	return ids, nil
+}
+
+// withExpectedVersion adds an optimistic concurrency check to an update filter. A nil expected
+// version leaves the filter unchanged. Documents written before versioning was introduced have no
+// version field and are treated as version 0.
+func withExpectedVersion(filter bson.M, expectedVersion *int64) bson.M {
+	if expectedVersion == nil {
+		return filter
+	}
+	if *expectedVersion == 0 {
+		filter["version"] = bson.M{"$in": bson.A{int64(0), nil}}
+	} else {
+		filter["version"] = *expectedVersion
+	}
+	return filter
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type InviteLinkRedemptionRepository interface {
+	CreateRedemption(ctx context.Context, redemption *model.InviteLinkRedemption) error
+	GetRedemptionsByLink(ctx context.Context, linkID primitive.ObjectID, page, pageSize int) ([]model.InviteLinkRedemptionResponse, int64, error)
+	CountRedemptionsByStatus(ctx context.Context, linkID primitive.ObjectID) (map[model.ParticipationStatus]int64, error)
+	DeleteRedemptionsByRally(ctx context.Context, rallyID primitive.ObjectID) error
+}
+
+type inviteLinkRedemptionRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewInviteLinkRedemptionRepository initializes a MongoDB-backed InviteLinkRedemptionRepository
+func NewInviteLinkRedemptionRepository(db *mongo.Database) InviteLinkRedemptionRepository {
+	return &inviteLinkRedemptionRepository{
+		db:         db,
+		collection: db.Collection("invite_link_redemptions"),
+	}
+}
+
+// CreateRedemption records a successful use of an invite link
+func (r *inviteLinkRedemptionRepository) CreateRedemption(ctx context.Context, redemption *model.InviteLinkRedemption) error {
+	if redemption.ID.IsZero() {
+		redemption.ID = primitive.NewObjectID()
+	}
+	if redemption.RedeemedAt.IsZero() {
+		redemption.RedeemedAt = time.Now()
+	}
+
+	_, err := r.collection.InsertOne(ctx, redemption)
+	return err
+}
+
+// GetRedemptionsByLink retrieves a page of an invite link's redemptions, newest first, with user info
+func (r *inviteLinkRedemptionRepository) GetRedemptionsByLink(ctx context.Context, linkID primitive.ObjectID, page, pageSize int) ([]model.InviteLinkRedemptionResponse, int64, error) {
+	skip := (page - 1) * pageSize
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"link_id": linkID}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "redeemed_at", Value: -1},
+			{Key: "_id", Value: -1},
+		}}},
+		{{Key: "$facet", Value: bson.M{
+			"metadata": []bson.M{{"$count": "total"}},
+			"data": []bson.M{
+				{"$skip": skip},
+				{"$limit": pageSize},
+				{"$lookup": bson.M{
+					"from":         "users",
+					"localField":   "user_id",
+					"foreignField": "_id",
+					"as":           "user_info",
+				}},
+				{"$unwind": bson.M{
+					"path":                       "$user_info",
+					"preserveNullAndEmptyArrays": true,
+				}},
+			},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	type rawRedemption struct {
+		model.InviteLinkRedemption `bson:",inline"`
+		UserInfo                   *struct {
+			ID        primitive.ObjectID `bson:"_id"`
+			Username  string             `bson:"username"`
+			FirstName string             `bson:"first_name"`
+			LastName  string             `bson:"last_name"`
+			AvatarUrl string             `bson:"avatar_url"`
+		} `bson:"user_info"`
+	}
+
+	type FacetResult struct {
+		Metadata []struct {
+			Total int64 `bson:"total"`
+		} `bson:"metadata"`
+		Data []rawRedemption `bson:"data"`
+	}
+
+	var results []FacetResult
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, 0, err
+	}
+
+	if len(results) == 0 {
+		return []model.InviteLinkRedemptionResponse{}, 0, nil
+	}
+
+	total := int64(0)
+	if len(results[0].Metadata) > 0 {
+		total = results[0].Metadata[0].Total
+	}
+
+	data := results[0].Data
+	responses := make([]model.InviteLinkRedemptionResponse, len(data))
+	for i, raw := range data {
+		responses[i] = model.InviteLinkRedemptionResponse{
+			ID:         raw.ID.Hex(),
+			Role:       raw.Role,
+			Status:     raw.Status,
+			RedeemedAt: raw.RedeemedAt,
+		}
+		if raw.UserInfo != nil {
+			responses[i].User = &model.ParticipantUserInfo{
+				ID:        raw.UserInfo.ID.Hex(),
+				Username:  raw.UserInfo.Username,
+				FirstName: raw.UserInfo.FirstName,
+				LastName:  raw.UserInfo.LastName,
+				AvatarUrl: raw.UserInfo.AvatarUrl,
+			}
+		}
+	}
+
+	return responses, total, nil
+}
+
+// CountRedemptionsByStatus counts an invite link's redemptions grouped by resulting participation status
+func (r *inviteLinkRedemptionRepository) CountRedemptionsByStatus(ctx context.Context, linkID primitive.ObjectID) (map[model.ParticipationStatus]int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"link_id": linkID}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$status",
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Status model.ParticipationStatus `bson:"_id"`
+		Count  int64                     `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[model.ParticipationStatus]int64, len(groups))
+	for _, g := range groups {
+		counts[g.Status] = g.Count
+	}
+	return counts, nil
+}
+
+// DeleteRedemptionsByRally permanently removes the redemption history of all invite links of a rally
+func (r *inviteLinkRedemptionRepository) DeleteRedemptionsByRally(ctx context.Context, rallyID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"rally_id": rallyID})
+	return err
+}
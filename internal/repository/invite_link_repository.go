@@ -3,19 +3,27 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type InviteLinkRepository interface {
 	CreateInviteLink(ctx context.Context, link *model.InviteLink) error
 	GetInviteLinkByToken(ctx context.Context, token string) (*model.InviteLink, error)
 	GetActiveInviteLinksByRally(ctx context.Context, rallyID primitive.ObjectID) ([]*model.InviteLink, error)
-	DeactivateInviteLink(ctx context.Context, token string) error
-	IncrementLinkUsage(ctx context.Context, token string) error
+	UpdateInviteLink(ctx context.Context, link *model.InviteLink) (*model.InviteLink, error)
+	ReactivateInviteLink(ctx context.Context, token string, now time.Time) (*model.InviteLink, error)
+	DeactivateInviteLink(ctx context.Context, token string, reason model.InviteLinkDeactivationReason) error
+	RevokeInviteLink(ctx context.Context, token string) error
+	RedeemInviteLink(ctx context.Context, token string, now time.Time) (*model.InviteLink, error)
+	IncrementPreviewCount(ctx context.Context, token string) error
+	DeactivateExpiredInviteLinks(ctx context.Context, now time.Time) (int64, error)
+	DeleteInviteLinksByRally(ctx context.Context, rallyID primitive.ObjectID) error
 }
 
 type inviteLinkRepository struct {
@@ -74,10 +82,83 @@ func (r *inviteLinkRepository) GetActiveInviteLinksByRally(ctx context.Context,
 	return links, nil
 }
 
-// DeactivateInviteLink marks an invite link as inactive (revoked)
-func (r *inviteLinkRepository) DeactivateInviteLink(ctx context.Context, token string) error {
-	filter := bson.M{"token": token}
-	update := bson.M{"$set": bson.M{"is_active": false}}
+// notRevoked matches links that are active or were only auto-deactivated for expiry or max uses.
+// Inactive links without a reason predate deactivation reasons and are treated as revoked.
+func notRevoked() bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"is_active": true},
+		bson.M{"deactivated_reason": bson.M{"$in": bson.A{model.InviteLinkDeactivatedExpired, model.InviteLinkDeactivatedMaxUses}}},
+	}}
+}
+
+// withinLimits matches links that are not expired and below their max uses at now
+func withinLimits(now time.Time) bson.A {
+	return bson.A{
+		bson.M{"$or": bson.A{
+			bson.M{"expires_at": nil},
+			bson.M{"expires_at": bson.M{"$gt": now}},
+		}},
+		bson.M{"$or": bson.A{
+			bson.M{"max_uses": bson.M{"$lte": 0}}, // 0 means unlimited
+			bson.M{"$expr": bson.M{"$lt": bson.A{"$current_uses", "$max_uses"}}},
+		}},
+	}
+}
+
+// UpdateInviteLink saves the editable settings of an invite link that has not been revoked, returning
+// nil if it has. Activation state and current_uses are left untouched so concurrent redemptions and
+// deactivations are never overwritten.
+func (r *inviteLinkRepository) UpdateInviteLink(ctx context.Context, link *model.InviteLink) (*model.InviteLink, error) {
+	filter := notRevoked()
+	filter["_id"] = link.ID
+	update := bson.M{"$set": bson.M{
+		"role_to_grant": link.RoleToGrant,
+		"expires_at":    link.ExpiresAt,
+		"max_uses":      link.MaxUses,
+	}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated model.InviteLink
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// ReactivateInviteLink reactivates a link that was auto-deactivated for expiry or max uses, but only
+// while its current limits allow redemption again. Returns nil if the link was left unchanged.
+func (r *inviteLinkRepository) ReactivateInviteLink(ctx context.Context, token string, now time.Time) (*model.InviteLink, error) {
+	filter := bson.M{
+		"token":              token,
+		"is_active":          false,
+		"deactivated_reason": bson.M{"$in": bson.A{model.InviteLinkDeactivatedExpired, model.InviteLinkDeactivatedMaxUses}},
+		"$and":               withinLimits(now),
+	}
+	update := bson.M{
+		"$set":   bson.M{"is_active": true},
+		"$unset": bson.M{"deactivated_reason": ""},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var link model.InviteLink
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&link)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+// DeactivateInviteLink marks an active invite link as inactive, recording why it was deactivated
+func (r *inviteLinkRepository) DeactivateInviteLink(ctx context.Context, token string, reason model.InviteLinkDeactivationReason) error {
+	filter := bson.M{"token": token, "is_active": true}
+	update := bson.M{"$set": bson.M{"is_active": false, "deactivated_reason": reason}}
 
 	result, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
@@ -91,19 +172,73 @@ func (r *inviteLinkRepository) DeactivateInviteLink(ctx context.Context, token s
 	return nil
 }
 
-// IncrementLinkUsage increments the current_uses counter of an invite link
-func (r *inviteLinkRepository) IncrementLinkUsage(ctx context.Context, token string) error {
-	filter := bson.M{"token": token}
-	update := bson.M{"$inc": bson.M{"current_uses": 1}}
+// RevokeInviteLink permanently deactivates an invite link. Links that were auto-deactivated for expiry
+// or max uses can still be revoked, which stops them from being reactivated by an edit.
+func (r *inviteLinkRepository) RevokeInviteLink(ctx context.Context, token string) error {
+	filter := notRevoked()
+	filter["token"] = token
+	update := bson.M{"$set": bson.M{"is_active": false, "deactivated_reason": model.InviteLinkDeactivatedRevoked}}
 
 	result, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
 
-	if result.ModifiedCount == 0 {
-		return errors.New("invite link not found")
+	if result.MatchedCount == 0 {
+		return errors.New("invite link not found or already revoked")
 	}
 
 	return nil
 }
+
+// RedeemInviteLink claims one use of an invite link in a single conditional update. The link is only
+// updated while it is active, not expired and below its max uses; otherwise nil is returned.
+func (r *inviteLinkRepository) RedeemInviteLink(ctx context.Context, token string, now time.Time) (*model.InviteLink, error) {
+	filter := bson.M{
+		"token":     token,
+		"is_active": true,
+		"$and":      withinLimits(now),
+	}
+	update := bson.M{"$inc": bson.M{"current_uses": 1}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var link model.InviteLink
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&link)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil // Link is no longer redeemable
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+// IncrementPreviewCount increments the preview_count counter of an invite link
+func (r *inviteLinkRepository) IncrementPreviewCount(ctx context.Context, token string) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"token": token}, bson.M{"$inc": bson.M{"preview_count": 1}})
+	return err
+}
+
+// DeactivateExpiredInviteLinks marks every active link whose expiry has passed as expired,
+// returning how many links were deactivated
+func (r *inviteLinkRepository) DeactivateExpiredInviteLinks(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{
+			"is_active":  true,
+			"expires_at": bson.M{"$lte": now},
+		},
+		bson.M{"$set": bson.M{"is_active": false, "deactivated_reason": model.InviteLinkDeactivatedExpired}},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// DeleteInviteLinksByRally permanently removes all invite links of a rally
+func (r *inviteLinkRepository) DeleteInviteLinksByRally(ctx context.Context, rallyID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"rally_id": rallyID})
+	return err
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// JobLockRepository hands out time-limited leases on named background jobs so that only one
+// server instance runs a given job at a time
+type JobLockRepository interface {
+	AcquireLock(ctx context.Context, name string, owner string, ttl time.Duration) (bool, error)
+	ReleaseLock(ctx context.Context, name string, owner string) error
+}
+
+type jobLockRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewJobLockRepository initializes a MongoDB-backed JobLockRepository
+func NewJobLockRepository(db *mongo.Database) JobLockRepository {
+	return &jobLockRepository{
+		db:         db,
+		collection: db.Collection("job_locks"),
+	}
+}
+
+// AcquireLock takes (or renews) the lease on a job for ttl. The lock document is keyed by the job
+// name, so the upsert only succeeds when the lock is free, expired or already held by owner; an
+// instance losing the race hits the duplicate _id and gets false.
+func (r *jobLockRepository) AcquireLock(ctx context.Context, name string, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id": name,
+		"$or": bson.A{
+			bson.M{"owner": owner},
+			bson.M{"locked_until": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"owner":        owner,
+		"locked_until": now.Add(ttl),
+		"acquired_at":  now,
+	}}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil // Held by another instance
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ReleaseLock gives up owner's lease on a job so another instance can take it over immediately
+func (r *jobLockRepository) ReleaseLock(ctx context.Context, name string, owner string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": name, "owner": owner})
+	return err
+}
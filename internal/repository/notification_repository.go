@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type NotificationRepository interface {
+	CreateNotifications(ctx context.Context, notifications []*model.Notification) error
+	GetNotifications(ctx context.Context, userID primitive.ObjectID, before *primitive.ObjectID, limit int, unreadOnly bool) ([]model.NotificationResponse, error)
+	CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	MarkRead(ctx context.Context, userID, notificationID primitive.ObjectID) error
+	MarkAllRead(ctx context.Context, userID primitive.ObjectID) (int64, error)
+}
+
+type notificationRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewNotificationRepository initializes a MongoDB-backed NotificationRepository
+func NewNotificationRepository(db *mongo.Database) NotificationRepository {
+	return &notificationRepository{
+		db:         db,
+		collection: db.Collection("notifications"),
+	}
+}
+
+// CreateNotifications inserts a batch of notifications
+func (r *notificationRepository) CreateNotifications(ctx context.Context, notifications []*model.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(notifications))
+	for i, notification := range notifications {
+		if notification.ID.IsZero() {
+			notification.ID = primitive.NewObjectID()
+		}
+		if notification.CreatedAt.IsZero() {
+			notification.CreatedAt = time.Now()
+		}
+		docs[i] = notification
+	}
+
+	_, err := r.collection.InsertMany(ctx, docs)
+	return err
+}
+
+// GetNotifications retrieves up to limit of a user's notifications, newest first, with the actor and
+// rally name resolved. When before is set only notifications older than it are returned, which is how
+// the cursor pagination works: ObjectIDs grow with insertion time.
+func (r *notificationRepository) GetNotifications(ctx context.Context, userID primitive.ObjectID, before *primitive.ObjectID, limit int, unreadOnly bool) ([]model.NotificationResponse, error) {
+	match := bson.M{"user_id": userID}
+	if before != nil {
+		match["_id"] = bson.M{"$lt": *before}
+	}
+	if unreadOnly {
+		match["read_at"] = nil
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: -1}}}},
+		{{Key: "$limit", Value: limit}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "users",
+			"localField":   "actor_id",
+			"foreignField": "_id",
+			"as":           "actor_info",
+		}}},
+		{{Key: "$unwind", Value: bson.M{
+			"path":                       "$actor_info",
+			"preserveNullAndEmptyArrays": true,
+		}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "rallies",
+			"localField":   "rally_id",
+			"foreignField": "_id",
+			"as":           "rally_info",
+		}}},
+		{{Key: "$unwind", Value: bson.M{
+			"path":                       "$rally_info",
+			"preserveNullAndEmptyArrays": true,
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	type rawNotification struct {
+		model.Notification `bson:",inline"`
+		ActorInfo          *struct {
+			ID        primitive.ObjectID `bson:"_id"`
+			Username  string             `bson:"username"`
+			FirstName string             `bson:"first_name"`
+			LastName  string             `bson:"last_name"`
+			AvatarUrl string             `bson:"avatar_url"`
+		} `bson:"actor_info"`
+		RallyInfo *struct {
+			Name string `bson:"name"`
+		} `bson:"rally_info"`
+	}
+
+	var raws []rawNotification
+	if err := cursor.All(ctx, &raws); err != nil {
+		return nil, err
+	}
+
+	responses := make([]model.NotificationResponse, len(raws))
+	for i, raw := range raws {
+		responses[i] = model.NotificationResponse{
+			ID:        raw.ID.Hex(),
+			Type:      raw.Type,
+			Data:      raw.Data,
+			IsRead:    raw.ReadAt != nil,
+			ReadAt:    raw.ReadAt,
+			CreatedAt: raw.CreatedAt,
+		}
+		if raw.RallyID != nil {
+			responses[i].RallyID = raw.RallyID.Hex()
+		}
+		if raw.EventID != nil {
+			responses[i].EventID = raw.EventID.Hex()
+		}
+		if raw.RallyInfo != nil {
+			responses[i].RallyName = raw.RallyInfo.Name
+		}
+		if raw.ActorInfo != nil {
+			responses[i].Actor = &model.ParticipantUserInfo{
+				ID:        raw.ActorInfo.ID.Hex(),
+				Username:  raw.ActorInfo.Username,
+				FirstName: raw.ActorInfo.FirstName,
+				LastName:  raw.ActorInfo.LastName,
+				AvatarUrl: raw.ActorInfo.AvatarUrl,
+			}
+		}
+	}
+
+	return responses, nil
+}
+
+// CountUnread counts a user's unread notifications
+func (r *notificationRepository) CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"user_id": userID, "read_at": nil})
+}
+
+// MarkRead marks one of a user's notifications as read. Marking an already read notification is a no-op.
+func (r *notificationRepository) MarkRead(ctx context.Context, userID, notificationID primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": notificationID, "user_id": userID, "read_at": nil},
+		bson.M{"$set": bson.M{"read_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		count, err := r.collection.CountDocuments(ctx, bson.M{"_id": notificationID, "user_id": userID})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return errors.New("notification not found")
+		}
+	}
+
+	return nil
+}
+
+// MarkAllRead marks every unread notification of a user as read, returning how many were updated
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	result, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"user_id": userID, "read_at": nil},
+		bson.M{"$set": bson.M{"read_at": time.Now()}},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PollRepository interface {
+	CreatePoll(ctx context.Context, poll *model.Poll) error
+	GetPollByID(ctx context.Context, pollID string) (*model.Poll, error)
+	GetPollsByRally(ctx context.Context, rallyID primitive.ObjectID, status model.PollStatus, page, pageSize int) ([]model.Poll, int64, error)
+	ClosePoll(ctx context.Context, pollID primitive.ObjectID, closedBy primitive.ObjectID, winningOptionID *primitive.ObjectID) (*model.Poll, error)
+	ReopenPoll(ctx context.Context, pollID primitive.ObjectID) error
+	TouchVotablePoll(ctx context.Context, pollID primitive.ObjectID, now time.Time) (bool, error)
+	SetPollActivity(ctx context.Context, pollID primitive.ObjectID, activityID primitive.ObjectID) (*model.Poll, error)
+	DeletePoll(ctx context.Context, pollID primitive.ObjectID) error
+	DeletePollsByRally(ctx context.Context, rallyID primitive.ObjectID) error
+}
+
+type pollRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewPollRepository initializes a MongoDB-backed PollRepository
+func NewPollRepository(db *mongo.Database) PollRepository {
+	return &pollRepository{
+		db:         db,
+		collection: db.Collection("polls"),
+	}
+}
+
+// CreatePoll inserts a new poll
+func (r *pollRepository) CreatePoll(ctx context.Context, poll *model.Poll) error {
+	if poll.ID.IsZero() {
+		poll.ID = primitive.NewObjectID()
+	}
+
+	now := time.Now()
+	if poll.CreatedAt.IsZero() {
+		poll.CreatedAt = now
+	}
+	if poll.UpdatedAt.IsZero() {
+		poll.UpdatedAt = now
+	}
+
+	_, err := r.collection.InsertOne(ctx, poll)
+	return err
+}
+
+// GetPollByID finds a poll by its ID
+func (r *pollRepository) GetPollByID(ctx context.Context, pollID string) (*model.Poll, error) {
+	objectID, err := primitive.ObjectIDFromHex(pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.findOne(ctx, bson.M{"_id": objectID})
+}
+
+// GetPollsByRally retrieves a page of a rally's polls, newest first, optionally only those with a status
+func (r *pollRepository) GetPollsByRally(ctx context.Context, rallyID primitive.ObjectID, status model.PollStatus, page, pageSize int) ([]model.Poll, int64, error) {
+	filter := bson.M{"rally_id": rallyID}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize)).
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var polls []model.Poll
+	if err := cursor.All(ctx, &polls); err != nil {
+		return nil, 0, err
+	}
+
+	return polls, total, nil
+}
+
+// ClosePoll closes a poll that is still open and returns the updated document, or nil if it does not
+// exist or was already closed, so that concurrent closes cannot both convert the winner
+func (r *pollRepository) ClosePoll(ctx context.Context, pollID primitive.ObjectID, closedBy primitive.ObjectID, winningOptionID *primitive.ObjectID) (*model.Poll, error) {
+	now := time.Now()
+	setDoc := bson.M{
+		"status":     model.PollStatusClosed,
+		"closed_by":  closedBy,
+		"closed_at":  now,
+		"updated_at": now,
+	}
+	if winningOptionID != nil {
+		setDoc["winning_option_id"] = winningOptionID
+	}
+
+	return r.update(ctx, bson.M{"_id": pollID, "status": model.PollStatusOpen}, bson.M{"$set": setDoc})
+}
+
+// ReopenPoll undoes ClosePoll, used when creating the activity for the winning option fails
+func (r *pollRepository) ReopenPoll(ctx context.Context, pollID primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": pollID}, bson.M{
+		"$set":   bson.M{"status": model.PollStatusOpen, "updated_at": time.Now()},
+		"$unset": bson.M{"closed_by": "", "closed_at": "", "winning_option_id": ""},
+	})
+	return err
+}
+
+// TouchVotablePoll increments the vote counter of a poll that still accepts votes at now, reporting
+// whether it did. Votes write it in the same transaction as the vote itself, so they conflict with a
+// concurrent ClosePoll instead of landing after the votes were counted.
+func (r *pollRepository) TouchVotablePoll(ctx context.Context, pollID primitive.ObjectID, now time.Time) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx, bson.M{
+		"_id":    pollID,
+		"status": model.PollStatusOpen,
+		"$or": bson.A{
+			bson.M{"deadline": nil},
+			bson.M{"deadline": bson.M{"$gt": now}},
+		},
+	}, bson.M{"$inc": bson.M{"vote_seq": 1}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// SetPollActivity records the activity created from a poll's winning option and returns the updated
+// document, or nil if the poll no longer exists
+func (r *pollRepository) SetPollActivity(ctx context.Context, pollID primitive.ObjectID, activityID primitive.ObjectID) (*model.Poll, error) {
+	return r.update(ctx, bson.M{"_id": pollID}, bson.M{"$set": bson.M{
+		"activity_id": activityID,
+		"updated_at":  time.Now(),
+	}})
+}
+
+// DeletePoll permanently removes a poll
+func (r *pollRepository) DeletePoll(ctx context.Context, pollID primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": pollID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("poll not found")
+	}
+
+	return nil
+}
+
+// DeletePollsByRally removes every poll of a rally, used when the rally is purged
+func (r *pollRepository) DeletePollsByRally(ctx context.Context, rallyID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"rally_id": rallyID})
+	return err
+}
+
+// findOne decodes the first poll matching filter, or returns nil if there is none
+func (r *pollRepository) findOne(ctx context.Context, filter bson.M) (*model.Poll, error) {
+	var poll model.Poll
+	if err := r.collection.FindOne(ctx, filter).Decode(&poll); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &poll, nil
+}
+
+// update applies update to the first poll matching filter and returns the updated document, or nil
+// if there is none
+func (r *pollRepository) update(ctx context.Context, filter bson.M, update bson.M) (*model.Poll, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var poll model.Poll
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&poll); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &poll, nil
+}
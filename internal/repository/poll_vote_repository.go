@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PollVoteRepository interface {
+	EnsureIndexes(ctx context.Context) error
+	UpsertVote(ctx context.Context, vote *model.PollVote) error
+	DeleteVote(ctx context.Context, pollID primitive.ObjectID, userID primitive.ObjectID) error
+	GetVotesByPoll(ctx context.Context, pollID primitive.ObjectID) ([]model.PollVote, error)
+	GetVotesByPolls(ctx context.Context, pollIDs []primitive.ObjectID) ([]model.PollVote, error)
+	DeleteVotesByPoll(ctx context.Context, pollID primitive.ObjectID) error
+	DeleteVotesByRally(ctx context.Context, rallyID primitive.ObjectID) error
+}
+
+type pollVoteRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewPollVoteRepository initializes a MongoDB-backed PollVoteRepository
+func NewPollVoteRepository(db *mongo.Database) PollVoteRepository {
+	return &pollVoteRepository{
+		db:         db,
+		collection: db.Collection("poll_votes"),
+	}
+}
+
+// EnsureIndexes creates the unique (poll_id, user_id) index that keeps each user to a single vote per poll
+func (r *pollVoteRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "poll_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// UpsertVote stores a user's vote in a poll, replacing the options of any earlier vote of theirs
+func (r *pollVoteRepository) UpsertVote(ctx context.Context, vote *model.PollVote) error {
+	now := time.Now()
+	filter := bson.M{"poll_id": vote.PollID, "user_id": vote.UserID}
+	update := bson.M{
+		"$set": bson.M{
+			"option_ids": vote.OptionIDs,
+			"updated_at": now,
+		},
+		"$setOnInsert": bson.M{
+			"_id":        primitive.NewObjectID(),
+			"rally_id":   vote.RallyID,
+			"created_at": now,
+		},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// DeleteVote removes a user's vote from a poll
+func (r *pollVoteRepository) DeleteVote(ctx context.Context, pollID primitive.ObjectID, userID primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"poll_id": pollID, "user_id": userID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("vote not found")
+	}
+
+	return nil
+}
+
+// GetVotesByPoll retrieves every vote cast in a poll, oldest first
+func (r *pollVoteRepository) GetVotesByPoll(ctx context.Context, pollID primitive.ObjectID) ([]model.PollVote, error) {
+	return r.find(ctx, bson.M{"poll_id": pollID})
+}
+
+// GetVotesByPolls retrieves every vote cast in any of the given polls, oldest first
+func (r *pollVoteRepository) GetVotesByPolls(ctx context.Context, pollIDs []primitive.ObjectID) ([]model.PollVote, error) {
+	if len(pollIDs) == 0 {
+		return []model.PollVote{}, nil
+	}
+	return r.find(ctx, bson.M{"poll_id": bson.M{"$in": pollIDs}})
+}
+
+// DeleteVotesByPoll removes every vote of a poll, used when the poll is deleted
+func (r *pollVoteRepository) DeleteVotesByPoll(ctx context.Context, pollID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"poll_id": pollID})
+	return err
+}
+
+// DeleteVotesByRally removes every poll vote of a rally, used when the rally is purged
+func (r *pollVoteRepository) DeleteVotesByRally(ctx context.Context, rallyID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"rally_id": rallyID})
+	return err
+}
+
+// find decodes every vote matching filter, oldest first
+func (r *pollVoteRepository) find(ctx context.Context, filter bson.M) ([]model.PollVote, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var votes []model.PollVote
+	if err := cursor.All(ctx, &votes); err != nil {
+		return nil, err
+	}
+
+	return votes, nil
+}
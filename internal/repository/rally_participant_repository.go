@@ -9,6 +9,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type RallyParticipantRepository interface {
@@ -16,9 +17,18 @@ type RallyParticipantRepository interface {
 	GetParticipant(ctx context.Context, participantID string) (*model.RallyParticipant, error)
 	GetParticipantByRallyAndUser(ctx context.Context, rallyID, userID primitive.ObjectID) (*model.RallyParticipant, error)
 	UpdateParticipant(ctx context.Context, participantID string, updates *model.UpdateParticipantRequest) (*model.RallyParticipant, error)
-	GetParticipantsList(ctx context.Context, rallyID primitive.ObjectID, role string, page, pageSize int) ([]model.RallyParticipantDetailResponse, int64, error)
+	DeleteParticipant(ctx context.Context, participantID string) error
+	UpdateParticipantWithStatus(ctx context.Context, participantID primitive.ObjectID, status model.ParticipationStatus, updates *model.UpdateParticipantRequest) (*model.RallyParticipant, error)
+	EnsureIndexes(ctx context.Context) error
+	UpsertJoinedParticipant(ctx context.Context, rallyID, userID primitive.ObjectID, role model.ParticipantRole, invitedBy primitive.ObjectID) (*model.RallyParticipant, error)
+	SetJoinRequest(ctx context.Context, participantID primitive.ObjectID, role model.ParticipantRole, invitedBy primitive.ObjectID, message string) (*model.RallyParticipant, error)
+	GetParticipantsList(ctx context.Context, rallyID primitive.ObjectID, role string, status string, page, pageSize int) ([]model.RallyParticipantDetailResponse, int64, error)
 	CountJoinedParticipants(ctx context.Context, rallyID primitive.ObjectID) (int64, error)
+	FindOtherJoinedOwner(ctx context.Context, rallyID, excludeUserID primitive.ObjectID) (*model.RallyParticipant, error)
 	GetPendingInvitations(ctx context.Context, userID primitive.ObjectID) ([]model.PendingInvitationItem, error)
+	DeleteParticipantsByRally(ctx context.Context, rallyID primitive.ObjectID) error
+	DeleteStaleInvitations(ctx context.Context, invitedBefore time.Time) (int64, error)
+	GetJoinedUserIDs(ctx context.Context, rallyID primitive.ObjectID) ([]primitive.ObjectID, error)
 }
 
 type rallyParticipantRepository struct {
@@ -113,14 +123,145 @@ func (r *rallyParticipantRepository) UpdateParticipant(ctx context.Context, part
 	return r.GetParticipant(ctx, participantID)
 }
 
+// UpdateParticipantWithStatus applies a role/status update only while the participant still has the
+// given status, returning the updated participant or nil if the status has changed in the meantime
+func (r *rallyParticipantRepository) UpdateParticipantWithStatus(ctx context.Context, participantID primitive.ObjectID, status model.ParticipationStatus, updates *model.UpdateParticipantRequest) (*model.RallyParticipant, error) {
+	updateDoc := bson.M{}
+	if updates.Role != nil {
+		updateDoc["role"] = *updates.Role
+	}
+	if updates.Status != nil {
+		updateDoc["status"] = *updates.Status
+		if *updates.Status == model.ParticipationStatusJoined {
+			updateDoc["joined_at"] = time.Now()
+		}
+	}
+	if len(updateDoc) == 0 {
+		return nil, errors.New("no participant changes to apply")
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var participant model.RallyParticipant
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": participantID, "status": status},
+		bson.M{"$set": updateDoc},
+		opts,
+	).Decode(&participant)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &participant, nil
+}
+
+// EnsureIndexes creates the unique (rally_id, user_id) index that keeps each user to a single
+// participant record per rally
+func (r *rallyParticipantRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "rally_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// UpsertJoinedParticipant marks a user as joined with the given role, creating their participant
+// record if they have none. An existing record keeps its original inviter and invitation time.
+// A banned record is never matched, so the upsert collides with it on the unique (rally_id, user_id)
+// index instead of overwriting the ban; that case returns nil, nil.
+func (r *rallyParticipantRepository) UpsertJoinedParticipant(ctx context.Context, rallyID, userID primitive.ObjectID, role model.ParticipantRole, invitedBy primitive.ObjectID) (*model.RallyParticipant, error) {
+	now := time.Now()
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var participant model.RallyParticipant
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"rally_id": rallyID,
+			"user_id":  userID,
+			"status":   bson.M{"$ne": model.ParticipationStatusBanned},
+		},
+		bson.M{
+			"$set": bson.M{
+				"role":      role,
+				"status":    model.ParticipationStatusJoined,
+				"joined_at": now,
+			},
+			"$setOnInsert": bson.M{
+				"_id":        primitive.NewObjectID(),
+				"invited_by": invitedBy,
+				"invited_at": now,
+			},
+		},
+		opts,
+	).Decode(&participant)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &participant, nil
+}
+
+// SetJoinRequest turns an existing (declined or left) participant record into a fresh pending join request.
+// Returns nil, nil if the record has been banned in the meantime.
+func (r *rallyParticipantRepository) SetJoinRequest(ctx context.Context, participantID primitive.ObjectID, role model.ParticipantRole, invitedBy primitive.ObjectID, message string) (*model.RallyParticipant, error) {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": participantID, "status": bson.M{"$ne": model.ParticipationStatusBanned}},
+		bson.M{"$set": bson.M{
+			"role":            role,
+			"status":          model.ParticipationStatusPending,
+			"invited_by":      invitedBy,
+			"invited_at":      time.Now(),
+			"joined_at":       nil,
+			"request_message": message,
+		}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, nil
+	}
+
+	return r.GetParticipant(ctx, participantID.Hex())
+}
+
+// DeleteParticipant permanently removes a single participant record
+func (r *rallyParticipantRepository) DeleteParticipant(ctx context.Context, participantID string) error {
+	objectID, err := primitive.ObjectIDFromHex(participantID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("participant not found")
+	}
+
+	return nil
+}
+
 // GetParticipantsList retrieves a paginated list of participants for a given rally, including user and inviter information.
-func (r *rallyParticipantRepository) GetParticipantsList(ctx context.Context, rallyID primitive.ObjectID, role string, page, pageSize int) ([]model.RallyParticipantDetailResponse, int64, error) {
+func (r *rallyParticipantRepository) GetParticipantsList(ctx context.Context, rallyID primitive.ObjectID, role string, status string, page, pageSize int) ([]model.RallyParticipantDetailResponse, int64, error) {
 	skip := (page - 1) * pageSize
 
 	matchFilter := bson.M{"rally_id": rallyID}
 	if role != "" {
 		matchFilter["role"] = role
 	}
+	if status != "" {
+		matchFilter["status"] = status
+	}
 
 	pipeline := mongo.Pipeline{
 		{{Key: "$match", Value: matchFilter}},
@@ -180,6 +321,7 @@ func (r *rallyParticipantRepository) GetParticipantsList(ctx context.Context, ra
 		InvitedAt   time.Time                 `bson:"invited_at"`
 		UserInfo    userInfoBson              `bson:"user_info"`
 		InviterInfo *userInfoBson             `bson:"inviter_info"`
+		Message     string                    `bson:"request_message"`
 	}
 
 	type FacetResult struct {
@@ -226,14 +368,15 @@ func (r *rallyParticipantRepository) GetParticipantsList(ctx context.Context, ra
 		}
 
 		responses[i] = model.RallyParticipantDetailResponse{
-			ID:        raw.ID.Hex(),
-			RallyID:   raw.RallyID.Hex(),
-			Role:      raw.Role,
-			Status:    raw.Status,
-			JoinedAt:  raw.JoinedAt,
-			InvitedAt: raw.InvitedAt,
-			User:      user,
-			InvitedBy: inviter,
+			ID:             raw.ID.Hex(),
+			RallyID:        raw.RallyID.Hex(),
+			Role:           raw.Role,
+			Status:         raw.Status,
+			JoinedAt:       raw.JoinedAt,
+			InvitedAt:      raw.InvitedAt,
+			User:           user,
+			InvitedBy:      inviter,
+			RequestMessage: raw.Message,
 		}
 	}
 
@@ -247,6 +390,27 @@ func (r *rallyParticipantRepository) CountJoinedParticipants(ctx context.Context
 	})
 }
 
+// FindOtherJoinedOwner returns the longest-standing joined owner of a rally other than the given user,
+// or nil if the given user is the only one
+func (r *rallyParticipantRepository) FindOtherJoinedOwner(ctx context.Context, rallyID, excludeUserID primitive.ObjectID) (*model.RallyParticipant, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "joined_at", Value: 1}})
+
+	var participant model.RallyParticipant
+	err := r.collection.FindOne(ctx, bson.M{
+		"rally_id": rallyID,
+		"user_id":  bson.M{"$ne": excludeUserID},
+		"role":     string(model.ParticipantRoleOwner),
+		"status":   string(model.ParticipationStatusJoined),
+	}, opts).Decode(&participant)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &participant, nil
+}
+
 // GetPendingInvitations retrieves all "invited" participant records for a user, enriched with rally and inviter info.
 func (r *rallyParticipantRepository) GetPendingInvitations(ctx context.Context, userID primitive.ObjectID) ([]model.PendingInvitationItem, error) {
 	pipeline := mongo.Pipeline{
@@ -265,6 +429,8 @@ func (r *rallyParticipantRepository) GetPendingInvitations(ctx context.Context,
 			"path":                       "$rally_info",
 			"preserveNullAndEmptyArrays": true,
 		}}},
+		// Hide invitations to rallies that are in the trash
+		{{Key: "$match", Value: bson.M{"rally_info.deleted_at": nil}}},
 		// Join inviter user info
 		{{Key: "$lookup", Value: bson.M{
 			"from":         "users",
@@ -374,3 +540,51 @@ func (r *rallyParticipantRepository) GetPendingInvitations(ctx context.Context,
 
 	return items, nil
 }
+
+// DeleteParticipantsByRally permanently removes all participant records of a rally
+func (r *rallyParticipantRepository) DeleteParticipantsByRally(ctx context.Context, rallyID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"rally_id": rallyID})
+	return err
+}
+
+// DeleteStaleInvitations removes invitations that are still unanswered after being sent before
+// invitedBefore, returning how many were removed
+func (r *rallyParticipantRepository) DeleteStaleInvitations(ctx context.Context, invitedBefore time.Time) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{
+		"status":     model.ParticipationStatusInvited,
+		"invited_at": bson.M{"$lt": invitedBefore},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
+
+// GetJoinedUserIDs returns the user IDs of every joined participant of a rally
+func (r *rallyParticipantRepository) GetJoinedUserIDs(ctx context.Context, rallyID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	opts := options.Find().SetProjection(bson.M{"user_id": 1})
+
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"rally_id": rallyID,
+		"status":   model.ParticipationStatusJoined,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var participants []struct {
+		UserID primitive.ObjectID `bson:"user_id"`
+	}
+	if err := cursor.All(ctx, &participants); err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]primitive.ObjectID, len(participants))
+	for i, participant := range participants {
+		userIDs[i] = participant.UserID
+	}
+
+	return userIDs, nil
+}
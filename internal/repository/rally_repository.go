@@ -9,13 +9,23 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type RallyRepository interface {
 	CreateRally(ctx context.Context, rally *model.Rally) error
 	GetRallyByID(ctx context.Context, rallyID string) (*model.Rally, error)
-	UpdateRally(ctx context.Context, rallyID string, updates *model.UpdateRallyRequest) (*model.Rally, error)
+	UpdateRally(ctx context.Context, rallyID string, updates *model.UpdateRallyRequest, expectedVersion *int64) (*model.Rally, error)
 	GetRalliesList(ctx context.Context, userID primitive.ObjectID, nameFilter string, statusFilter string, sortOrder string, page int, pageSize int) ([]model.Rally, int, error)
+	DeleteRally(ctx context.Context, rallyID string) error
+	SoftDeleteRally(ctx context.Context, rallyID string, deletedAt time.Time) error
+	RestoreRally(ctx context.Context, rallyID string) error
+	GetDeletedRallyByID(ctx context.Context, rallyID string) (*model.Rally, error)
+	GetDeletedRallyIDs(ctx context.Context, deletedBefore time.Time) ([]primitive.ObjectID, error)
+	SetRallyOwner(ctx context.Context, rallyID, ownerID primitive.ObjectID) (*model.Rally, error)
+	TouchOwnershipGuard(ctx context.Context, rallyID primitive.ObjectID) error
+	ActivateStartedRallies(ctx context.Context, now time.Time) (int64, error)
+	CompleteEndedRallies(ctx context.Context, now time.Time) (int64, error)
 }
 
 type rallyRepository struct {
@@ -42,6 +52,9 @@ func (r *rallyRepository) CreateRally(ctx context.Context, rally *model.Rally) e
 	if rally.UpdatedAt.IsZero() {
 		rally.UpdatedAt = now
 	}
+	if rally.Version == 0 {
+		rally.Version = 1
+	}
 
 	_, err := r.collection.InsertOne(ctx, rally)
 	return err
@@ -54,7 +67,7 @@ func (r *rallyRepository) GetRallyByID(ctx context.Context, rallyID string) (*mo
 	}
 
 	var rally model.Rally
-	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&rally)
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID, "deleted_at": nil}).Decode(&rally)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, nil
@@ -64,7 +77,7 @@ func (r *rallyRepository) GetRallyByID(ctx context.Context, rallyID string) (*mo
 	return &rally, nil
 }
 
-func (r *rallyRepository) UpdateRally(ctx context.Context, rallyID string, updates *model.UpdateRallyRequest) (*model.Rally, error) {
+func (r *rallyRepository) UpdateRally(ctx context.Context, rallyID string, updates *model.UpdateRallyRequest, expectedVersion *int64) (*model.Rally, error) {
 	objectID, err := primitive.ObjectIDFromHex(rallyID)
 	if err != nil {
 		return nil, err
@@ -92,22 +105,36 @@ func (r *rallyRepository) UpdateRally(ctx context.Context, rallyID string, updat
 	if updates.EndDate != nil {
 		updateDoc["end_date"] = *updates.EndDate
 	}
+	if updates.BaseCurrency != nil {
+		updateDoc["base_currency"] = *updates.BaseCurrency
+	}
+
+	filter := withExpectedVersion(bson.M{"_id": objectID, "deleted_at": nil}, expectedVersion)
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 
-	_, err = r.collection.UpdateOne(
+	var updated model.Rally
+	err = r.collection.FindOneAndUpdate(
 		ctx,
-		bson.M{"_id": objectID},
-		bson.M{"$set": updateDoc},
-	)
+		filter,
+		bson.M{"$set": updateDoc, "$inc": bson.M{"version": 1}},
+		opts,
+	).Decode(&updated)
 	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// Not found, or the expected version is stale
+			return nil, nil
+		}
 		return nil, err
 	}
 
-	return r.GetRallyByID(ctx, rallyID)
+	return &updated, nil
 }
 
 func (r *rallyRepository) GetRalliesList(ctx context.Context, userID primitive.ObjectID, nameFilter string, statusFilter string, sortOrder string, page int, pageSize int) ([]model.Rally, int, error) {
-	// Build base pipeline for filtering
-	basePipeline := []bson.M{}
+	// Build base pipeline for filtering, excluding rallies in the trash
+	basePipeline := []bson.M{
+		{"$match": bson.M{"deleted_at": nil}},
+	}
 
 	// Stage 1: Lookup rally_participants to filter by user participation
 	basePipeline = append(basePipeline, bson.M{
@@ -200,3 +227,168 @@ func (r *rallyRepository) GetRalliesList(ctx context.Context, userID primitive.O
 
 	return results[0].Data, total, nil
 }
+
+// DeleteRally permanently removes a rally document
+func (r *rallyRepository) DeleteRally(ctx context.Context, rallyID string) error {
+	objectID, err := primitive.ObjectIDFromHex(rallyID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("rally not found")
+	}
+
+	return nil
+}
+
+// SoftDeleteRally moves a rally to the trash by setting its deleted_at marker
+func (r *rallyRepository) SoftDeleteRally(ctx context.Context, rallyID string, deletedAt time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(rallyID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objectID, "deleted_at": nil},
+		bson.M{"$set": bson.M{"deleted_at": deletedAt}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("rally not found")
+	}
+
+	return nil
+}
+
+// RestoreRally moves a rally out of the trash by removing its deleted_at marker
+func (r *rallyRepository) RestoreRally(ctx context.Context, rallyID string) error {
+	objectID, err := primitive.ObjectIDFromHex(rallyID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objectID, "deleted_at": bson.M{"$ne": nil}},
+		bson.M{
+			"$unset": bson.M{"deleted_at": ""},
+			"$set":   bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("rally not found in trash")
+	}
+
+	return nil
+}
+
+// GetDeletedRallyByID finds a rally that is currently in the trash
+func (r *rallyRepository) GetDeletedRallyByID(ctx context.Context, rallyID string) (*model.Rally, error) {
+	objectID, err := primitive.ObjectIDFromHex(rallyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rally model.Rally
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID, "deleted_at": bson.M{"$ne": nil}}).Decode(&rally)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rally, nil
+}
+
+// GetDeletedRallyIDs retrieves the IDs of rallies moved to the trash before the given time
+func (r *rallyRepository) GetDeletedRallyIDs(ctx context.Context, deletedBefore time.Time) ([]primitive.ObjectID, error) {
+	return findIDs(ctx, r.collection, bson.M{"deleted_at": bson.M{"$lt": deletedBefore}})
+}
+
+// SetRallyOwner points a rally at a new owning user and returns the updated rally
+func (r *rallyRepository) SetRallyOwner(ctx context.Context, rallyID, ownerID primitive.ObjectID) (*model.Rally, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated model.Rally
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": rallyID, "deleted_at": nil},
+		bson.M{
+			"$set": bson.M{"owner_id": ownerID, "updated_at": time.Now()},
+			"$inc": bson.M{"version": 1},
+		},
+		opts,
+	).Decode(&updated)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.New("rally not found")
+		}
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// TouchOwnershipGuard increments the rally's ownership guard counter. Transactions that change who owns
+// a rally write it first, so two of them running concurrently conflict instead of both succeeding on
+// stale reads. The counter is internal and, unlike version, does not invalidate client ETags.
+func (r *rallyRepository) TouchOwnershipGuard(ctx context.Context, rallyID primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": rallyID}, bson.M{"$inc": bson.M{"ownership_guard": 1}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("rally not found")
+	}
+	return nil
+}
+
+// ActivateStartedRallies moves draft rallies whose start date has passed to active
+func (r *rallyRepository) ActivateStartedRallies(ctx context.Context, now time.Time) (int64, error) {
+	return r.advanceStatus(ctx, bson.M{
+		"status":     model.RallyStatusDraft,
+		"start_date": bson.M{"$lte": now},
+	}, model.RallyStatusActive, now)
+}
+
+// CompleteEndedRallies moves active rallies whose end date has passed to completed
+func (r *rallyRepository) CompleteEndedRallies(ctx context.Context, now time.Time) (int64, error) {
+	return r.advanceStatus(ctx, bson.M{
+		"status":   model.RallyStatusActive,
+		"end_date": bson.M{"$lte": now},
+	}, model.RallyStatusCompleted, now)
+}
+
+// advanceStatus sets the status of every live rally matching filter, bumping its version so
+// clients holding an older ETag notice the change
+func (r *rallyRepository) advanceStatus(ctx context.Context, filter bson.M, status model.RallyStatus, now time.Time) (int64, error) {
+	filter["deleted_at"] = nil
+
+	result, err := r.collection.UpdateMany(
+		ctx,
+		filter,
+		bson.M{
+			"$set": bson.M{"status": status, "updated_at": now},
+			"$inc": bson.M{"version": 1},
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
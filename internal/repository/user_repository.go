@@ -15,6 +15,7 @@ import (
 type UserRepository interface {
 	GetUserByFirebaseUID(ctx context.Context, firebaseUID string) (*model.User, error)
 	GetUserByID(ctx context.Context, userID string) (*model.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
 	CreateUser(ctx context.Context, user *model.User) error
 	UpdateUserProfile(ctx context.Context, userID string, updates *model.ProfileUpdateRequest) (*model.User, error)
 	ExistsEmail(ctx context.Context, email string) (bool, error)
@@ -24,6 +25,7 @@ type UserRepository interface {
 	IncrementFollowingCount(ctx context.Context, userID string) error
 	DecrementFollowingCount(ctx context.Context, userID string) error
 	SearchUsers(ctx context.Context, query string, page, pageSize int) ([]*model.User, int64, error)
+	GetUsersByUsernames(ctx context.Context, usernames []string) ([]*model.User, error)
 }
 
 type userRepository struct {
@@ -147,6 +149,19 @@ func (r *userRepository) UpdateUserProfile(ctx context.Context, userID string, u
 	return r.GetUserByID(ctx, userID)
 }
 
+// GetUserByEmail finds a user by email address
+func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	var user model.User
+	err := r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil // Not found
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
 // ExistsEmail checks if an email already exists in the database
 func (r *userRepository) ExistsEmail(ctx context.Context, email string) (bool, error) {
 	count, err := r.collection.CountDocuments(ctx, bson.M{"email": email})
@@ -270,3 +285,23 @@ func (r *userRepository) SearchUsers(ctx context.Context, query string, page, pa
 
 	return users, total, nil
 }
+
+// GetUsersByUsernames finds the users with any of the given usernames
+func (r *userRepository) GetUsersByUsernames(ctx context.Context, usernames []string) ([]*model.User, error) {
+	if len(usernames) == 0 {
+		return []*model.User{}, nil
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"username": bson.M{"$in": usernames}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []*model.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
@@ -2,6 +2,7 @@ package router
 
 import (
 	"context"
+	"time"
 
 	fb "firebase.google.com/go/v4"
 	_ "github.com/Hoi-Trang-Huynh/rally-backend-api/api/docs"
@@ -9,15 +10,19 @@ import (
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/handler"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/infrastructure/database"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/infrastructure/firebase"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/infrastructure/mailer"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/infrastructure/push"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/middleware"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/realtime"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/repository"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/scheduler"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/service"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/utils"
 	"github.com/gofiber/fiber/v2"
 	fiberSwagger "github.com/swaggo/fiber-swagger"
 )
 
-func Setup(cfg *config.Config) *fiber.App {
+func Setup(cfg *config.Config, sched *scheduler.Scheduler) *fiber.App {
 	db := database.GetDB()
 	internalDB := database.GetInternalDB()
 
@@ -29,6 +34,17 @@ func Setup(cfg *config.Config) *fiber.App {
 	activityRepo := repository.NewActivityRepository(db)
 	participantRepo := repository.NewRallyParticipantRepository(db)
 	inviteLinkRepo := repository.NewInviteLinkRepository(db)
+	redemptionRepo := repository.NewInviteLinkRedemptionRepository(db)
+	emailInviteRepo := repository.NewEmailInvitationRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	deviceRepo := repository.NewDeviceRepository(db)
+	expenseRepo := repository.NewExpenseRepository(db)
+	exchangeRateRepo := repository.NewExchangeRateRepository(db)
+	budgetRepo := repository.NewBudgetRepository(db)
+	pollRepo := repository.NewPollRepository(db)
+	pollVoteRepo := repository.NewPollVoteRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	auditRepo := repository.NewAuditLogRepository(db)
 
 	fbApp := firebase.GetClient()
 
@@ -37,7 +53,19 @@ func Setup(cfg *config.Config) *fiber.App {
 		panic(err)
 	}
 
-	app, err := SetupWithDeps(userRepo, followRepo, feedbackRepo, rallyRepo, eventRepo, activityRepo, participantRepo, inviteLinkRepo, fbApp, cld)
+	mail, err := mailer.New(cfg.Mailer)
+	if err != nil {
+		panic(err)
+	}
+
+	pushSender, err := push.New(context.Background(), cfg.Push, fbApp)
+	if err != nil {
+		panic(err)
+	}
+
+	pubsub := realtime.NewMemoryPubSub()
+
+	app, err := SetupWithDeps(userRepo, followRepo, feedbackRepo, rallyRepo, eventRepo, activityRepo, participantRepo, inviteLinkRepo, redemptionRepo, emailInviteRepo, notificationRepo, deviceRepo, expenseRepo, exchangeRateRepo, budgetRepo, pollRepo, pollVoteRepo, commentRepo, auditRepo, fbApp, cld, mail, pushSender, pubsub, cfg.Jobs, cfg.InviteLinks, cfg.Mailer, sched)
 	if err != nil {
 		panic(err)
 	}
@@ -54,8 +82,26 @@ func SetupWithDeps(
 	activityRepo repository.ActivityRepository,
 	participantRepo repository.RallyParticipantRepository,
 	inviteLinkRepo repository.InviteLinkRepository,
+	redemptionRepo repository.InviteLinkRedemptionRepository,
+	emailInviteRepo repository.EmailInvitationRepository,
+	notificationRepo repository.NotificationRepository,
+	deviceRepo repository.DeviceRepository,
+	expenseRepo repository.ExpenseRepository,
+	exchangeRateRepo repository.ExchangeRateRepository,
+	budgetRepo repository.BudgetRepository,
+	pollRepo repository.PollRepository,
+	pollVoteRepo repository.PollVoteRepository,
+	commentRepo repository.CommentRepository,
+	auditRepo repository.AuditLogRepository,
 	fbApp *fb.App,
 	cld *utils.CloudinaryUploader,
+	mail mailer.Mailer,
+	pushSender push.Sender,
+	pubsub realtime.PubSub,
+	jobsCfg config.JobsConfig,
+	inviteLinksCfg config.InviteLinksConfig,
+	mailerCfg config.MailerConfig,
+	sched *scheduler.Scheduler,
 ) (*fiber.App, error) {
 
 	// Create Firebase auth client once and share across all services
@@ -69,15 +115,25 @@ func SetupWithDeps(
 	app.Use(middleware.Logger())
 	app.Use(middleware.CORS())
 
-	authService := service.NewAuthService(firebaseAuth, userRepo)
+	authService := service.NewAuthService(firebaseAuth, userRepo, rallyRepo, participantRepo, emailInviteRepo)
 	userService := service.NewUserService(firebaseAuth, userRepo)
-	followService := service.NewFollowService(firebaseAuth, followRepo, userRepo)
+	pushDispatcher := service.NewPushDispatcher(pushSender, deviceRepo, userRepo, rallyRepo)
+	notificationService := service.NewNotificationService(firebaseAuth, notificationRepo, userRepo, pushDispatcher)
+	deviceService := service.NewDeviceService(firebaseAuth, deviceRepo, userRepo)
+	followService := service.NewFollowService(firebaseAuth, followRepo, userRepo, notificationService)
 	feedbackService := service.NewFeedbackService(feedbackRepo)
-	rallyService := service.NewRallyService(database.GetDB(), firebaseAuth, rallyRepo, participantRepo, userRepo)
-	eventService := service.NewEventService(firebaseAuth, eventRepo, rallyRepo, participantRepo, userRepo)
-	activityService := service.NewActivityService(firebaseAuth, activityRepo, eventRepo, participantRepo, userRepo)
-	participantService := service.NewRallyParticipantService(firebaseAuth, participantRepo, rallyRepo, userRepo, followRepo)
-	inviteLinkService := service.NewInviteLinkService(firebaseAuth, inviteLinkRepo, participantRepo, rallyRepo, userRepo, eventRepo)
+	rallyService := service.NewRallyService(database.GetDB(), firebaseAuth, rallyRepo, eventRepo, activityRepo, participantRepo, inviteLinkRepo, redemptionRepo, emailInviteRepo, expenseRepo, exchangeRateRepo, budgetRepo, pollRepo, pollVoteRepo, commentRepo, userRepo, auditRepo, pubsub)
+	eventService := service.NewEventService(database.GetDB(), firebaseAuth, eventRepo, activityRepo, rallyRepo, participantRepo, userRepo, auditRepo, notificationService, pubsub)
+	activityService := service.NewActivityService(database.GetDB(), firebaseAuth, activityRepo, eventRepo, rallyRepo, participantRepo, userRepo, auditRepo, pubsub)
+	participantService := service.NewRallyParticipantService(database.GetDB(), firebaseAuth, participantRepo, rallyRepo, userRepo, followRepo, emailInviteRepo, auditRepo, notificationService, pubsub, mail, mailerCfg.AppURL)
+	inviteLinkService := service.NewInviteLinkService(database.GetDB(), firebaseAuth, inviteLinkRepo, redemptionRepo, participantRepo, rallyRepo, userRepo, eventRepo, auditRepo, notificationService, pubsub, inviteLinksCfg.BaseURL)
+	auditLogService := service.NewAuditLogService(auditRepo, eventRepo, activityRepo, rallyRepo, pubsub)
+	realtimeService := service.NewRealtimeService(pubsub)
+	expenseService := service.NewExpenseService(expenseRepo, rallyRepo, eventRepo, activityRepo, participantRepo, exchangeRateRepo, auditRepo, pubsub)
+	exchangeRateService := service.NewExchangeRateService(database.GetDB(), exchangeRateRepo, rallyRepo, auditRepo, pubsub)
+	budgetService := service.NewBudgetService(budgetRepo, expenseRepo, rallyRepo, eventRepo, participantRepo, exchangeRateRepo, auditRepo, pubsub)
+	pollService := service.NewPollService(database.GetDB(), pollRepo, pollVoteRepo, rallyRepo, eventRepo, activityService, auditRepo, pubsub)
+	commentService := service.NewCommentService(commentRepo, rallyRepo, eventRepo, activityRepo, participantRepo, userRepo, auditRepo, notificationService, pubsub)
 
 	authHandler := handler.NewAuthHandler(authService)
 	userHandler := handler.NewUserHandler(userService)
@@ -89,6 +145,45 @@ func SetupWithDeps(
 	activityHandler := handler.NewActivityHandler(activityService)
 	participantHandler := handler.NewRallyParticipantHandler(participantService)
 	inviteLinkHandler := handler.NewInviteLinkHandler(inviteLinkService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	deviceHandler := handler.NewDeviceHandler(deviceService)
+	auditLogHandler := handler.NewAuditLogHandler(auditLogService)
+	realtimeHandler := handler.NewRealtimeHandler(realtimeService)
+	expenseHandler := handler.NewExpenseHandler(expenseService)
+	exchangeRateHandler := handler.NewExchangeRateHandler(exchangeRateService)
+	budgetHandler := handler.NewBudgetHandler(budgetService)
+	pollHandler := handler.NewPollHandler(pollService)
+	commentHandler := handler.NewCommentHandler(commentService)
+
+	// Background jobs
+	sched.Register(scheduler.Job{
+		Name:     "purge-trash",
+		Interval: jobsCfg.TrashPurgeInterval,
+		Run: func(ctx context.Context) error {
+			return rallyService.PurgeTrash(ctx, time.Now().Add(-jobsCfg.TrashRetention))
+		},
+	})
+	sched.Register(scheduler.Job{
+		Name:     "rally-status",
+		Interval: jobsCfg.RallyStatusInterval,
+		Run: func(ctx context.Context) error {
+			return rallyService.AdvanceRallyStatuses(ctx, time.Now())
+		},
+	})
+	sched.Register(scheduler.Job{
+		Name:     "invite-link-expiry",
+		Interval: jobsCfg.InviteLinkExpiryInterval,
+		Run: func(ctx context.Context) error {
+			return inviteLinkService.DeactivateExpiredLinks(ctx, time.Now())
+		},
+	})
+	sched.Register(scheduler.Job{
+		Name:     "invitation-expiry",
+		Interval: jobsCfg.InvitationExpiryInterval,
+		Run: func(ctx context.Context) error {
+			return participantService.ExpireStaleInvitations(ctx, time.Now().Add(-jobsCfg.InvitationTTL))
+		},
+	})
 
 	auth := middleware.AuthRequired()
 
@@ -108,7 +203,13 @@ func SetupWithDeps(
 	users := v1.Group("/user")
 	users.Get("/me/profile", auth, userHandler.GetMyProfile)
 	users.Get("/me/profile/details", auth, userHandler.GetMyProfileDetails)
-	users.Get("/me/invitations", auth, participantHandler.GetPendingInvitations) // TODO: temporary until realtime notifications
+	users.Get("/me/invitations", auth, participantHandler.GetPendingInvitations)
+	users.Get("/me/notifications", auth, notificationHandler.GetNotifications)
+	users.Get("/me/notifications/unread-count", auth, notificationHandler.GetUnreadCount)
+	users.Post("/me/notifications/read-all", auth, notificationHandler.MarkAllRead)
+	users.Post("/me/notifications/:id/read", auth, notificationHandler.MarkRead)
+	users.Post("/me/devices", auth, deviceHandler.RegisterDevice)
+	users.Delete("/me/devices", auth, deviceHandler.UnregisterDevice)
 	users.Get("/search", userHandler.SearchUsers)
 	users.Get("/:id/profile", followHandler.GetUserPublicProfile)
 	users.Put("/:id/profile", auth, userHandler.UpdateProfile)
@@ -134,31 +235,101 @@ func SetupWithDeps(
 	loadParticipant := middleware.LoadRallyParticipant(participantRepo)
 	joined := middleware.RequireJoined()
 	ownerOrEditor := middleware.RequireRole("owner", "editor")
+	ownerOnly := middleware.RequireRole("owner")
+	admin := middleware.RequireAdmin()
 
 	// Rally routes (all require auth + resolved user)
 	rallies := v1.Group("/rallies", auth, resolveUser)
-	rallies.Post("/join-via-link", inviteLinkHandler.JoinViaLink)                                                              // No rally ID — manual validation
-	rallies.Get("/invite-links/:token/preview", inviteLinkHandler.PreviewInviteLink)                                           // Preview an invite link
-	rallies.Post("/", rallyHandler.CreateRally)                                                                                // No rally ID yet
-	rallies.Get("/:id", loadParticipant, rallyHandler.GetRally)                                                                // Allows invited — handler checks status
-	rallies.Put("/:id", loadParticipant, joined, ownerOrEditor, rallyHandler.UpdateRally)                                      // Owner/Editor + joined
-	rallies.Post("/:id/events", loadParticipant, joined, ownerOrEditor, eventHandler.CreateEvent)                              // Owner/Editor + joined
-	rallies.Get("/:id/participants", loadParticipant, joined, participantHandler.GetParticipantsList)                          // Any joined participant
-	rallies.Get("/:id/invitable-friends", loadParticipant, joined, participantHandler.GetInvitableFriends)                     // Any joined participant
-	rallies.Post("/:id/participants", loadParticipant, joined, ownerOrEditor, participantHandler.InviteParticipant)            // Owner/Editor + joined
-	rallies.Put("/:id/participants/:participantId", loadParticipant, participantHandler.UpdateParticipant)                     // Conditional — service handles self vs. others
-	rallies.Post("/:id/invite-links", loadParticipant, joined, ownerOrEditor, inviteLinkHandler.CreateInviteLink)              // Owner/Editor + joined (extra owner check for elevated roles in service)
-	rallies.Get("/:id/invite-links", loadParticipant, joined, ownerOrEditor, inviteLinkHandler.GetActiveInviteLinks)           // Owner/Editor + joined
-	rallies.Delete("/:id/invite-links/:token", loadParticipant, joined, ownerOrEditor, inviteLinkHandler.DeactivateInviteLink) // Owner/Editor + joined
+	rallies.Post("/join-via-link", inviteLinkHandler.JoinViaLink)                                                                            // No rally ID — manual validation
+	rallies.Get("/invite-links/:token/preview", inviteLinkHandler.PreviewInviteLink)                                                         // Preview an invite link
+	rallies.Post("/", rallyHandler.CreateRally)                                                                                              // No rally ID yet
+	rallies.Get("/:id", loadParticipant, rallyHandler.GetRally)                                                                              // Allows invited — handler checks status
+	rallies.Put("/:id", loadParticipant, joined, ownerOrEditor, rallyHandler.UpdateRally)                                                    // Owner/Editor + joined
+	rallies.Delete("/:id", loadParticipant, joined, ownerOnly, rallyHandler.DeleteRally)                                                     // Owner + joined (moves to trash, cascades)
+	rallies.Post("/:id/restore", loadParticipant, joined, ownerOnly, rallyHandler.RestoreRally)                                              // Owner + joined
+	rallies.Get("/:id/trash", loadParticipant, joined, ownerOrEditor, eventHandler.GetTrash)                                                 // Owner/Editor + joined
+	rallies.Get("/:id/history", loadParticipant, joined, auditLogHandler.GetRallyHistory)                                                    // Any joined participant
+	rallies.Get("/:id/realtime/ws", loadParticipant, joined, realtimeHandler.StreamWebSocket)                                                // Any joined participant
+	rallies.Get("/:id/realtime/sse", loadParticipant, joined, realtimeHandler.StreamEvents)                                                  // Any joined participant (WebSocket fallback)
+	rallies.Post("/:id/history/:entryId/revert", loadParticipant, joined, ownerOrEditor, auditLogHandler.RevertHistoryEntry)                 // Owner/Editor + joined
+	rallies.Post("/:id/events", loadParticipant, joined, ownerOrEditor, eventHandler.CreateEvent)                                            // Owner/Editor + joined
+	rallies.Get("/:id/events", loadParticipant, joined, eventHandler.GetEventsList)                                                          // Any joined participant
+	rallies.Put("/:id/events/order", loadParticipant, joined, ownerOrEditor, eventHandler.ReorderEvents)                                     // Owner/Editor + joined
+	rallies.Get("/:id/itinerary", loadParticipant, joined, eventHandler.GetItinerary)                                                        // Any joined participant
+	rallies.Post("/:id/expenses", loadParticipant, joined, expenseHandler.CreateExpense)                                                     // Any joined participant
+	rallies.Get("/:id/expenses", loadParticipant, joined, expenseHandler.GetExpensesList)                                                    // Any joined participant
+	rallies.Get("/:id/expenses/:expenseId", loadParticipant, joined, expenseHandler.GetExpense)                                              // Any joined participant
+	rallies.Put("/:id/expenses/:expenseId", loadParticipant, joined, expenseHandler.UpdateExpense)                                           // Any joined participant (creator/payer or owner/editor checked in service)
+	rallies.Delete("/:id/expenses/:expenseId", loadParticipant, joined, expenseHandler.DeleteExpense)                                        // Any joined participant (creator/payer or owner/editor checked in service)
+	rallies.Get("/:id/balances", loadParticipant, joined, expenseHandler.GetBalances)                                                        // Any joined participant
+	rallies.Get("/:id/exchange-rates", loadParticipant, joined, exchangeRateHandler.GetRallyRates)                                           // Any joined participant
+	rallies.Put("/:id/exchange-rates", loadParticipant, joined, ownerOrEditor, exchangeRateHandler.ReplaceRallyRates)                        // Owner/Editor + joined
+	rallies.Put("/:id/exchange-rates/:from/:to", loadParticipant, joined, ownerOrEditor, exchangeRateHandler.SetRallyRate)                   // Owner/Editor + joined
+	rallies.Delete("/:id/exchange-rates/:from/:to", loadParticipant, joined, ownerOrEditor, exchangeRateHandler.DeleteRallyRate)             // Owner/Editor + joined
+	rallies.Post("/:id/budgets", loadParticipant, joined, ownerOrEditor, budgetHandler.CreateBudget)                                         // Owner/Editor + joined
+	rallies.Get("/:id/budgets", loadParticipant, joined, budgetHandler.GetBudgets)                                                           // Any joined participant
+	rallies.Put("/:id/budgets/:budgetId", loadParticipant, joined, ownerOrEditor, budgetHandler.UpdateBudget)                                // Owner/Editor + joined
+	rallies.Delete("/:id/budgets/:budgetId", loadParticipant, joined, ownerOrEditor, budgetHandler.DeleteBudget)                             // Owner/Editor + joined
+	rallies.Get("/:id/budget/summary", loadParticipant, joined, budgetHandler.GetBudgetSummary)                                              // Any joined participant
+	rallies.Post("/:id/polls", loadParticipant, joined, pollHandler.CreatePoll)                                                              // Any joined participant
+	rallies.Get("/:id/polls", loadParticipant, joined, pollHandler.GetPollsList)                                                             // Any joined participant
+	rallies.Get("/:id/polls/:pollId", loadParticipant, joined, pollHandler.GetPoll)                                                          // Any joined participant
+	rallies.Delete("/:id/polls/:pollId", loadParticipant, joined, pollHandler.DeletePoll)                                                    // Any joined participant (creator or owner/editor checked in service)
+	rallies.Put("/:id/polls/:pollId/vote", loadParticipant, joined, pollHandler.Vote)                                                        // Any joined participant
+	rallies.Delete("/:id/polls/:pollId/vote", loadParticipant, joined, pollHandler.RetractVote)                                              // Any joined participant
+	rallies.Post("/:id/polls/:pollId/close", loadParticipant, joined, ownerOrEditor, pollHandler.ClosePoll)                                  // Owner/Editor + joined
+	rallies.Post("/:id/comments", commentHandler.CreateRallyComment)                                                                         // Rally access checked in service
+	rallies.Get("/:id/comments", commentHandler.GetRallyComments)                                                                            // Rally access checked in service
+	rallies.Get("/:id/participants", loadParticipant, joined, participantHandler.GetParticipantsList)                                        // Any joined participant
+	rallies.Get("/:id/invitable-friends", loadParticipant, joined, participantHandler.GetInvitableFriends)                                   // Any joined participant
+	rallies.Post("/:id/participants", loadParticipant, joined, ownerOrEditor, participantHandler.InviteParticipant)                          // Owner/Editor + joined
+	rallies.Put("/:id/participants/:participantId", loadParticipant, participantHandler.UpdateParticipant)                                   // Conditional — service handles self vs. others
+	rallies.Delete("/:id/participants/:participantId", loadParticipant, joined, ownerOrEditor, participantHandler.RemoveParticipant)         // Owner/Editor + joined (role hierarchy in service)
+	rallies.Get("/:id/email-invitations", loadParticipant, joined, ownerOrEditor, participantHandler.GetEmailInvitations)                    // Owner/Editor + joined
+	rallies.Delete("/:id/email-invitations/:invitationId", loadParticipant, joined, ownerOrEditor, participantHandler.CancelEmailInvitation) // Owner/Editor + joined
+	rallies.Get("/:id/join-requests", loadParticipant, joined, ownerOrEditor, participantHandler.GetJoinRequests)                            // Owner/Editor + joined
+	rallies.Post("/:id/join-requests/:participantId/approve", loadParticipant, joined, ownerOrEditor, participantHandler.ApproveJoinRequest) // Owner/Editor + joined
+	rallies.Post("/:id/join-requests/:participantId/reject", loadParticipant, joined, ownerOrEditor, participantHandler.RejectJoinRequest)   // Owner/Editor + joined
+	rallies.Post("/:id/transfer-ownership", loadParticipant, joined, ownerOnly, participantHandler.TransferOwnership)                        // Owner + joined
+	rallies.Post("/:id/invite-links", loadParticipant, joined, ownerOrEditor, inviteLinkHandler.CreateInviteLink)                            // Owner/Editor + joined (extra owner check for elevated roles in service)
+	rallies.Get("/:id/invite-links", loadParticipant, joined, ownerOrEditor, inviteLinkHandler.GetActiveInviteLinks)                         // Owner/Editor + joined
+	rallies.Get("/:id/invite-links/:token/qr", loadParticipant, joined, ownerOrEditor, inviteLinkHandler.GetInviteLinkQRCode)                // Owner/Editor + joined
+	rallies.Get("/:id/invite-links/:token/redemptions", loadParticipant, joined, ownerOrEditor, inviteLinkHandler.GetLinkRedemptions)        // Owner/Editor + joined
+	rallies.Patch("/:id/invite-links/:token", loadParticipant, joined, ownerOrEditor, inviteLinkHandler.UpdateInviteLink)                    // Owner/Editor + joined (extra owner check for elevated roles in service)
+	rallies.Delete("/:id/invite-links/:token", loadParticipant, joined, ownerOrEditor, inviteLinkHandler.DeactivateInviteLink)               // Owner/Editor + joined
 
 	// Event routes (auth + resolved user, rally access checked in service via event lookup)
 	events := v1.Group("/events", auth, resolveUser)
 	events.Put("/:id", eventHandler.UpdateEvent)
+	events.Delete("/:id", eventHandler.DeleteEvent)
+	events.Post("/:id/restore", eventHandler.RestoreEvent)
 	events.Post("/:id/activities", activityHandler.CreateActivity)
+	events.Get("/:id/activities", activityHandler.GetActivitiesList)
+	events.Put("/:id/activities/order", activityHandler.ReorderActivities)
+	events.Post("/:id/comments", commentHandler.CreateEventComment)
+	events.Get("/:id/comments", commentHandler.GetEventComments)
 
 	// Activity routes (auth + resolved user, rally access checked in service via activity lookup)
 	activities := v1.Group("/activities", auth, resolveUser)
 	activities.Put("/:id", activityHandler.UpdateActivity)
+	activities.Delete("/:id", activityHandler.DeleteActivity)
+	activities.Post("/:id/restore", activityHandler.RestoreActivity)
+	activities.Post("/:id/comments", commentHandler.CreateActivityComment)
+	activities.Get("/:id/comments", commentHandler.GetActivityComments)
+
+	// Comment routes (auth + resolved user, rally access checked in service via comment lookup)
+	comments := v1.Group("/comments", auth, resolveUser)
+	comments.Put("/:id", commentHandler.UpdateComment)
+	comments.Delete("/:id", commentHandler.DeleteComment)
+	comments.Get("/:id/replies", commentHandler.GetReplies)
+	comments.Post("/:id/replies", commentHandler.CreateReply)
+
+	// Global exchange rate routes (auth + resolved user, edits restricted to admins)
+	exchangeRates := v1.Group("/exchange-rates", auth, resolveUser)
+	exchangeRates.Get("/", exchangeRateHandler.GetGlobalRates)
+	exchangeRates.Put("/", admin, exchangeRateHandler.ReplaceGlobalRates)
+	exchangeRates.Put("/:from/:to", admin, exchangeRateHandler.SetGlobalRate)
+	exchangeRates.Delete("/:from/:to", admin, exchangeRateHandler.DeleteGlobalRate)
 
 	return app, nil
 }
@@ -0,0 +1,174 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// minJobInterval is the shortest interval a job may run on. Shorter ones would make the tickers in
+// loop and renewLease panic (or just spin), so such jobs are not started.
+const minJobInterval = time.Second
+
+// Job is a unit of background work that runs on a fixed interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Locker elects a single leader per job across server instances. A lease taken with AcquireLock
+// lasts for ttl and is renewed by its owner on every run and periodically while a run is in progress.
+type Locker interface {
+	AcquireLock(ctx context.Context, name string, owner string, ttl time.Duration) (bool, error)
+	ReleaseLock(ctx context.Context, name string, owner string) error
+}
+
+// Scheduler runs registered jobs in the background until stopped
+type Scheduler struct {
+	jobs       []Job
+	locker     Locker
+	instanceID string
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// New creates a scheduler that coordinates with other instances through locker.
+// A nil locker runs every job on this instance.
+func New(locker Locker) *Scheduler {
+	return &Scheduler{
+		locker:     locker,
+		instanceID: uuid.New().String(),
+	}
+}
+
+// Register adds a job to the scheduler. Jobs must be registered before Start is called.
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start launches every registered job in its own goroutine. Each job runs once immediately
+// and then on every tick of its interval. Jobs with an interval below minJobInterval are skipped.
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, job := range s.jobs {
+		if job.Interval < minJobInterval {
+			log.Printf("[scheduler] job %s not started, interval %s is below %s", job.Name, job.Interval, minJobInterval)
+			continue
+		}
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+}
+
+// Stop signals all jobs to stop, waits for in-flight runs to finish and hands this instance's
+// leases back so another instance can take over without waiting for them to expire
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	if s.locker == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, job := range s.jobs {
+		if err := s.locker.ReleaseLock(ctx, job.Name, s.instanceID); err != nil {
+			log.Printf("[scheduler] failed to release lock for job %s: %v", job.Name, err)
+		}
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.run(ctx, job)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// leaseTTL is how long a job lease lasts. It spans two intervals so a leader whose next tick is a
+// little late still renews the lease before another instance can take it over.
+func leaseTTL(job Job) time.Duration {
+	return 2 * job.Interval
+}
+
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	if s.locker != nil {
+		acquired, err := s.locker.AcquireLock(ctx, job.Name, s.instanceID, leaseTTL(job))
+		if err != nil {
+			log.Printf("[scheduler] job %s skipped, failed to acquire lock: %v", job.Name, err)
+			return
+		}
+		if !acquired {
+			return // Another instance is the leader for this job
+		}
+
+		// Keep extending the lease while the job runs, so a run longer than the lease does not let
+		// a second instance start the same job
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		renewing := make(chan struct{})
+		go func() {
+			defer close(renewing)
+			s.renewLease(ctx, cancel, job)
+		}()
+		defer func() {
+			cancel()
+			<-renewing
+		}()
+	}
+
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		log.Printf("[scheduler] job %s failed: %v", job.Name, err)
+		return
+	}
+	log.Printf("[scheduler] job %s completed in %s", job.Name, time.Since(start))
+}
+
+// renewLease extends this instance's lease on job every half interval until ctx is done. If the
+// lease turns out to be held by another instance, the run is cancelled.
+func (s *Scheduler) renewLease(ctx context.Context, cancel context.CancelFunc, job Job) {
+	ticker := time.NewTicker(job.Interval / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		acquired, err := s.locker.AcquireLock(ctx, job.Name, s.instanceID, leaseTTL(job))
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("[scheduler] failed to renew lock for job %s: %v", job.Name, err)
+			}
+			continue
+		}
+		if !acquired {
+			log.Printf("[scheduler] job %s lost its lock to another instance, cancelling run", job.Name)
+			cancel()
+			return
+		}
+	}
+}
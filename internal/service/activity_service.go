@@ -4,34 +4,49 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"firebase.google.com/go/v4/auth"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/realtime"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/repository"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type ActivityService struct {
+	db              *mongo.Database
 	firebaseAuth    *auth.Client
 	activityRepo    repository.ActivityRepository
 	eventRepo       repository.EventRepository
+	rallyRepo       repository.RallyRepository
 	participantRepo repository.RallyParticipantRepository
 	userRepo        repository.UserRepository
+	auditRepo       repository.AuditLogRepository
+	pubsub          realtime.PubSub
 }
 
 func NewActivityService(
+	db *mongo.Database,
 	firebaseAuth *auth.Client,
 	activityRepo repository.ActivityRepository,
 	eventRepo repository.EventRepository,
+	rallyRepo repository.RallyRepository,
 	participantRepo repository.RallyParticipantRepository,
 	userRepo repository.UserRepository,
+	auditRepo repository.AuditLogRepository,
+	pubsub realtime.PubSub,
 ) *ActivityService {
 	return &ActivityService{
+		db:              db,
 		firebaseAuth:    firebaseAuth,
 		activityRepo:    activityRepo,
 		eventRepo:       eventRepo,
+		rallyRepo:       rallyRepo,
 		participantRepo: participantRepo,
 		userRepo:        userRepo,
+		auditRepo:       auditRepo,
+		pubsub:          pubsub,
 	}
 }
 
@@ -54,10 +69,13 @@ func (s *ActivityService) validateRallyAccessViaEvent(ctx context.Context, userI
 // CreateActivity creates a new activity within an event (requires owner or editor role in the event's rally)
 func (s *ActivityService) CreateActivity(ctx context.Context, user *model.User, eventID string, req *model.CreateActivityRequest) (*model.ActivityResponse, error) {
 
-	_, err := s.validateRallyAccessViaEvent(ctx, user.ID, eventID, []string{"owner", "editor"})
+	event, err := s.validateRallyAccessViaEvent(ctx, user.ID, eventID, []string{"owner", "editor"})
 	if err != nil {
 		return nil, err
 	}
+	if err := ensureRallyEditable(ctx, s.rallyRepo, event.RallyID.Hex()); err != nil {
+		return nil, err
+	}
 
 	eventObjID, err := primitive.ObjectIDFromHex(eventID)
 	if err != nil {
@@ -82,11 +100,24 @@ func (s *ActivityService) CreateActivity(ctx context.Context, user *model.User,
 		return nil, fmt.Errorf("failed to create activity: %w", err)
 	}
 
-	return s.ConvertToActivityResponse(activity), nil
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    event.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionCreate,
+		TargetType: model.AuditTargetActivity,
+		TargetID:   activity.ID,
+		Changes:    diffFields(nil, activity),
+	})
+
+	resp := s.ConvertToActivityResponse(activity)
+	publishRallyUpdate(ctx, s.pubsub, event.RallyID, user.ID, model.RealtimeActivityCreated, resp)
+
+	return resp, nil
 }
 
-// UpdateActivity updates an existing activity (requires owner or editor role in the activity's rally)
-func (s *ActivityService) UpdateActivity(ctx context.Context, user *model.User, activityID string, req *model.UpdateActivityRequest) (*model.ActivityResponse, error) {
+// UpdateActivity updates an existing activity (requires owner or editor role in the activity's rally).
+// If expectedVersion is set and stale, the current activity is returned along with a precondition error.
+func (s *ActivityService) UpdateActivity(ctx context.Context, user *model.User, activityID string, req *model.UpdateActivityRequest, expectedVersion *int64) (*model.ActivityResponse, error) {
 
 	activity, err := s.activityRepo.GetActivityByID(ctx, activityID)
 	if err != nil {
@@ -96,21 +127,257 @@ func (s *ActivityService) UpdateActivity(ctx context.Context, user *model.User,
 		return nil, errors.New("activity not found")
 	}
 
-	_, err = s.validateRallyAccessViaEvent(ctx, user.ID, activity.EventID.Hex(), []string{"owner", "editor"})
+	event, err := s.validateRallyAccessViaEvent(ctx, user.ID, activity.EventID.Hex(), []string{"owner", "editor"})
 	if err != nil {
 		return nil, err
 	}
+	if err := ensureRallyEditable(ctx, s.rallyRepo, event.RallyID.Hex()); err != nil {
+		return nil, err
+	}
 
-	updated, err := s.activityRepo.UpdateActivity(ctx, activityID, req)
+	updated, err := s.activityRepo.UpdateActivity(ctx, activityID, req, expectedVersion)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update activity: %w", err)
 	}
+	if updated == nil {
+		// The activity was changed (or deleted) since the client last read it
+		current, err := s.activityRepo.GetActivityByID(ctx, activityID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get activity: %w", err)
+		}
+		if current == nil {
+			return nil, errors.New("activity not found")
+		}
+		return s.ConvertToActivityResponse(current), errors.New("precondition failed: resource has been modified")
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    event.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionUpdate,
+		TargetType: model.AuditTargetActivity,
+		TargetID:   updated.ID,
+		Changes:    diffFields(activity, updated),
+	})
+
+	resp := s.ConvertToActivityResponse(updated)
+	publishRallyUpdate(ctx, s.pubsub, event.RallyID, user.ID, model.RealtimeActivityUpdated, resp)
+
+	return resp, nil
+}
+
+// DeleteActivity moves an activity to the trash (requires owner or editor role in the activity's rally)
+func (s *ActivityService) DeleteActivity(ctx context.Context, user *model.User, activityID string) error {
+
+	activity, err := s.activityRepo.GetActivityByID(ctx, activityID)
+	if err != nil {
+		return fmt.Errorf("failed to get activity: %w", err)
+	}
+	if activity == nil {
+		return errors.New("activity not found")
+	}
+
+	event, err := s.validateRallyAccessViaEvent(ctx, user.ID, activity.EventID.Hex(), []string{"owner", "editor"})
+	if err != nil {
+		return err
+	}
+	if err := ensureRallyEditable(ctx, s.rallyRepo, event.RallyID.Hex()); err != nil {
+		return err
+	}
+
+	deletedAt := time.Now()
+	if err := s.activityRepo.SoftDeleteActivity(ctx, activityID, deletedAt); err != nil {
+		return fmt.Errorf("failed to delete activity: %w", err)
+	}
+
+	deleted := *activity
+	deleted.DeletedAt = &deletedAt
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    event.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionDelete,
+		TargetType: model.AuditTargetActivity,
+		TargetID:   activity.ID,
+		Changes:    diffFields(activity, &deleted),
+	})
+
+	publishRallyUpdate(ctx, s.pubsub, event.RallyID, user.ID, model.RealtimeActivityDeleted, &model.RealtimeDeletedData{ID: activity.ID.Hex()})
+
+	return nil
+}
+
+// RestoreActivity moves an activity out of the trash (requires owner or editor role in the activity's rally).
+// Activities whose event is itself in the trash must be restored through the event.
+func (s *ActivityService) RestoreActivity(ctx context.Context, user *model.User, activityID string) (*model.ActivityResponse, error) {
+
+	activity, err := s.activityRepo.GetDeletedActivityByID(ctx, activityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activity: %w", err)
+	}
+	if activity == nil {
+		return nil, errors.New("activity not found in trash")
+	}
+
+	event, err := s.eventRepo.GetEventByID(ctx, activity.EventID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	if event == nil {
+		deletedEvent, err := s.eventRepo.GetDeletedEventByID(ctx, activity.EventID.Hex())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get event: %w", err)
+		}
+		if deletedEvent == nil {
+			return nil, errors.New("event not found")
+		}
+		if err := validateRallyAccess(ctx, s.participantRepo, user.ID, deletedEvent.RallyID.Hex(), []string{"owner", "editor"}); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("cannot restore activity: its event is deleted")
+	}
+
+	if err := validateRallyAccess(ctx, s.participantRepo, user.ID, event.RallyID.Hex(), []string{"owner", "editor"}); err != nil {
+		return nil, err
+	}
+	if err := ensureRallyEditable(ctx, s.rallyRepo, event.RallyID.Hex()); err != nil {
+		return nil, err
+	}
+
+	if err := s.activityRepo.RestoreActivity(ctx, activityID); err != nil {
+		return nil, fmt.Errorf("failed to restore activity: %w", err)
+	}
+
+	restored, err := s.activityRepo.GetActivityByID(ctx, activityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activity: %w", err)
+	}
+	if restored == nil {
+		return nil, errors.New("activity not found")
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    event.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionRestore,
+		TargetType: model.AuditTargetActivity,
+		TargetID:   restored.ID,
+		Changes:    diffFields(activity, restored),
+	})
+
+	resp := s.ConvertToActivityResponse(restored)
+	publishRallyUpdate(ctx, s.pubsub, event.RallyID, user.ID, model.RealtimeActivityRestored, resp)
 
-	return s.ConvertToActivityResponse(updated), nil
+	return resp, nil
+}
+
+// ReorderActivities rewrites the activity order of every live activity of an event in a single transaction.
+// The request must list exactly the event's current activities; otherwise the client's view is stale
+// and nothing is written (requires owner or editor role in the event's rally).
+func (s *ActivityService) ReorderActivities(ctx context.Context, user *model.User, eventID string, req *model.ReorderActivitiesRequest) (*model.ActivityListResponse, error) {
+
+	event, err := s.validateRallyAccessViaEvent(ctx, user.ID, eventID, []string{"owner", "editor"})
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureRallyEditable(ctx, s.rallyRepo, event.RallyID.Hex()); err != nil {
+		return nil, err
+	}
+
+	orderedIDs, err := parseOrderedIDs(req.ActivityIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.db.Client().StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	var previousIDs []primitive.ObjectID
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		current, err := s.activityRepo.GetActivitiesByEvent(sessCtx, event.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get event activities: %w", err)
+		}
+		currentIDs := make([]primitive.ObjectID, len(current))
+		for i := range current {
+			currentIDs[i] = current[i].ID
+		}
+		if !sameIDSet(orderedIDs, currentIDs) {
+			return nil, errors.New("stale order: activities have changed")
+		}
+
+		matched, err := s.activityRepo.SetActivitiesOrder(sessCtx, event.ID, orderedIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reorder activities: %w", err)
+		}
+		if matched != int64(len(orderedIDs)) {
+			return nil, errors.New("stale order: activities have changed")
+		}
+
+		previousIDs = currentIDs
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    event.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionReorder,
+		TargetType: model.AuditTargetEvent,
+		TargetID:   event.ID,
+		Changes: []model.FieldChange{{
+			Field:  "activity_order",
+			Before: previousIDs,
+			After:  orderedIDs,
+		}},
+	})
+
+	list, err := s.GetActivitiesList(ctx, user, eventID)
+	if err != nil {
+		return nil, err
+	}
+	publishRallyUpdate(ctx, s.pubsub, event.RallyID, user.ID, model.RealtimeActivitiesReordered, list)
+
+	return list, nil
+}
+
+// GetActivitiesList retrieves all activities of an event ordered by activity order (requires joined participant in the event's rally)
+func (s *ActivityService) GetActivitiesList(ctx context.Context, user *model.User, eventID string) (*model.ActivityListResponse, error) {
+
+	event, err := s.validateRallyAccessViaEvent(ctx, user.ID, eventID, []string{"owner", "editor", "participant"})
+	if err != nil {
+		return nil, err
+	}
+
+	activities, err := s.activityRepo.GetActivitiesByEvent(ctx, event.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activities: %w", err)
+	}
+
+	items := make([]model.ActivityResponse, len(activities))
+	for i := range activities {
+		items[i] = *s.ConvertToActivityResponse(&activities[i])
+	}
+
+	return &model.ActivityListResponse{
+		Activities: items,
+		Total:      len(items),
+	}, nil
 }
 
 // ConvertToActivityResponse converts an Activity model to ActivityResponse
 func (s *ActivityService) ConvertToActivityResponse(activity *model.Activity) *model.ActivityResponse {
+	return convertToActivityResponse(activity)
+}
+
+// convertToActivityResponse converts an Activity model to ActivityResponse.
+// Shared with EventService, which nests activities in the itinerary, and AuditLogService.
+func convertToActivityResponse(activity *model.Activity) *model.ActivityResponse {
 	return &model.ActivityResponse{
 		ID:            activity.ID.Hex(),
 		EventID:       activity.EventID.Hex(),
@@ -126,5 +393,7 @@ func (s *ActivityService) ConvertToActivityResponse(activity *model.Activity) *m
 		ActivityOrder: activity.ActivityOrder,
 		CreatedAt:     activity.CreatedAt,
 		UpdatedAt:     activity.UpdatedAt,
+		DeletedAt:     activity.DeletedAt,
+		Version:       activity.Version,
 	}
 }
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// auditIgnoredFields are left out of audit diffs: bookkeeping fields that change on every write,
+// and secrets that must not be exposed through the rally history.
+var auditIgnoredFields = map[string]bool{
+	"_id":        true,
+	"created_at": true,
+	"updated_at": true,
+	"version":    true,
+	"token":      true,
+}
+
+// auditPrivateFields are left out of the changes recorded for specific target types. The rally history
+// is readable by every joined participant, so content hidden from some of them (such as a moderated
+// comment or an invitee's email address) must not be kept there.
+var auditPrivateFields = map[model.AuditTargetType]map[string]bool{
+	model.AuditTargetComment:         {"body": true, "mentions": true},
+	model.AuditTargetEmailInvitation: {"email": true}, // Email invitations are only listed to owners and editors
+}
+
+// withoutPrivateFields drops the changes to fields that must not be exposed for targetType
+func withoutPrivateFields(targetType model.AuditTargetType, changes []model.FieldChange) []model.FieldChange {
+	private := auditPrivateFields[targetType]
+	if len(private) == 0 {
+		return changes
+	}
+
+	kept := make([]model.FieldChange, 0, len(changes))
+	for _, c := range changes {
+		if !private[c.Field] {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// recordAudit appends an entry to a rally's audit log. Failures are logged rather than returned:
+// the mutation has already been applied and must not be reported as failed because auditing did.
+// Updates that did not change any tracked field are not recorded.
+func recordAudit(ctx context.Context, auditRepo repository.AuditLogRepository, entry *model.AuditLog) {
+	entry.Changes = withoutPrivateFields(entry.TargetType, entry.Changes)
+	if entry.Action == model.AuditActionUpdate && len(entry.Changes) == 0 {
+		return
+	}
+
+	if err := auditRepo.CreateAuditLog(ctx, entry); err != nil {
+		log.Printf("[audit] failed to record %s %s %s: %v", entry.Action, entry.TargetType, entry.TargetID.Hex(), err)
+	}
+}
+
+// diffFields compares the stored (bson) representation of two documents and returns the fields
+// whose values differ, sorted by field name. Either side may be nil (e.g. before a create).
+func diffFields(before, after interface{}) []model.FieldChange {
+	beforeDoc := toBsonMap(before)
+	afterDoc := toBsonMap(after)
+
+	fields := make([]string, 0, len(beforeDoc)+len(afterDoc))
+	for field := range beforeDoc {
+		fields = append(fields, field)
+	}
+	for field := range afterDoc {
+		if _, ok := beforeDoc[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := []model.FieldChange{}
+	for _, field := range fields {
+		if auditIgnoredFields[field] {
+			continue
+		}
+		if reflect.DeepEqual(beforeDoc[field], afterDoc[field]) {
+			continue
+		}
+		changes = append(changes, model.FieldChange{
+			Field:  field,
+			Before: beforeDoc[field],
+			After:  afterDoc[field],
+		})
+	}
+
+	return changes
+}
+
+// toBsonMap converts a model into its stored field/value representation
+func toBsonMap(doc interface{}) bson.M {
+	if doc == nil {
+		return bson.M{}
+	}
+	if v := reflect.ValueOf(doc); v.Kind() == reflect.Ptr && v.IsNil() {
+		return bson.M{}
+	}
+
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return bson.M{}
+	}
+
+	var m bson.M
+	if err := bson.Unmarshal(data, &m); err != nil {
+		return bson.M{}
+	}
+	return m
+}
+
+// errUnrevertableField is returned when a recorded change cannot be re-applied through an update request,
+// either because the field is not editable or because its previous value has an unexpected type
+var errUnrevertableField = errors.New("cannot revert: a changed field has no restorable previous value")
+
+// errNoPreviousValue is returned by the before* helpers when the field was unset before the change;
+// reverting it removes the field again
+var errNoPreviousValue = errors.New("field had no previous value")
+
+// eventUpdateFromChanges builds an event update request that restores the recorded before-values
+func eventUpdateFromChanges(changes []model.FieldChange) (*model.UpdateEventRequest, error) {
+	req := &model.UpdateEventRequest{}
+	var err error
+
+	for _, c := range changes {
+		switch c.Field {
+		case "google_place_id":
+			req.GooglePlaceID, err = beforeString(c)
+		case "name":
+			req.Name, err = beforeString(c)
+		case "lat":
+			req.Lat, err = beforeFloat(c)
+		case "lng":
+			req.Lng, err = beforeFloat(c)
+		case "start_time":
+			req.StartTime, err = beforeTime(c)
+		case "end_time":
+			req.EndTime, err = beforeTime(c)
+		case "notes":
+			req.Notes, err = beforeString(c)
+		case "visit_order":
+			req.VisitOrder, err = beforeInt(c)
+		default:
+			err = errUnrevertableField
+		}
+		if errors.Is(err, errNoPreviousValue) {
+			req.Unset = append(req.Unset, c.Field)
+			err = nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
+}
+
+// activityUpdateFromChanges builds an activity update request that restores the recorded before-values
+func activityUpdateFromChanges(changes []model.FieldChange) (*model.UpdateActivityRequest, error) {
+	req := &model.UpdateActivityRequest{}
+	var err error
+
+	for _, c := range changes {
+		switch c.Field {
+		case "name":
+			req.Name, err = beforeString(c)
+		case "description":
+			req.Description, err = beforeString(c)
+		case "status":
+			req.Status, err = beforeString(c)
+		case "google_place_id":
+			req.GooglePlaceID, err = beforeString(c)
+		case "lat":
+			req.Lat, err = beforeFloat(c)
+		case "lng":
+			req.Lng, err = beforeFloat(c)
+		case "start_time":
+			req.StartTime, err = beforeTime(c)
+		case "end_time":
+			req.EndTime, err = beforeTime(c)
+		case "notes":
+			req.Notes, err = beforeString(c)
+		case "activity_order":
+			req.ActivityOrder, err = beforeInt(c)
+		default:
+			err = errUnrevertableField
+		}
+		if errors.Is(err, errNoPreviousValue) {
+			req.Unset = append(req.Unset, c.Field)
+			err = nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
+}
+
+func beforeString(c model.FieldChange) (*string, error) {
+	if c.Before == nil {
+		return nil, errNoPreviousValue
+	}
+	v, ok := c.Before.(string)
+	if !ok {
+		return nil, errUnrevertableField
+	}
+	return &v, nil
+}
+
+func beforeFloat(c model.FieldChange) (*float64, error) {
+	if c.Before == nil {
+		return nil, errNoPreviousValue
+	}
+	var v float64
+	switch n := c.Before.(type) {
+	case float64:
+		v = n
+	case int32:
+		v = float64(n)
+	case int64:
+		v = float64(n)
+	default:
+		return nil, errUnrevertableField
+	}
+	return &v, nil
+}
+
+func beforeInt(c model.FieldChange) (*int, error) {
+	if c.Before == nil {
+		return nil, errNoPreviousValue
+	}
+	var v int
+	switch n := c.Before.(type) {
+	case int32:
+		v = int(n)
+	case int64:
+		v = int(n)
+	case float64:
+		v = int(n)
+	default:
+		return nil, errUnrevertableField
+	}
+	return &v, nil
+}
+
+func beforeTime(c model.FieldChange) (*time.Time, error) {
+	if c.Before == nil {
+		return nil, errNoPreviousValue
+	}
+	var v time.Time
+	switch t := c.Before.(type) {
+	case primitive.DateTime:
+		v = t.Time()
+	case time.Time:
+		v = t
+	default:
+		return nil, errUnrevertableField
+	}
+	return &v, nil
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/realtime"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/repository"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AuditLogService struct {
+	auditRepo    repository.AuditLogRepository
+	eventRepo    repository.EventRepository
+	activityRepo repository.ActivityRepository
+	rallyRepo    repository.RallyRepository
+	pubsub       realtime.PubSub
+}
+
+func NewAuditLogService(
+	auditRepo repository.AuditLogRepository,
+	eventRepo repository.EventRepository,
+	activityRepo repository.ActivityRepository,
+	rallyRepo repository.RallyRepository,
+	pubsub realtime.PubSub,
+) *AuditLogService {
+	return &AuditLogService{
+		auditRepo:    auditRepo,
+		eventRepo:    eventRepo,
+		activityRepo: activityRepo,
+		rallyRepo:    rallyRepo,
+		pubsub:       pubsub,
+	}
+}
+
+// GetRallyHistory retrieves a paginated, newest-first change history of a rally (middleware ensures joined participant)
+func (s *AuditLogService) GetRallyHistory(ctx context.Context, rallyID string, page, pageSize int) (*model.AuditLogListResponse, error) {
+	rallyObjID, err := primitive.ObjectIDFromHex(rallyID)
+	if err != nil {
+		return nil, errors.New("invalid rally ID")
+	}
+
+	entries, total, err := s.auditRepo.GetAuditLogsByRally(ctx, rallyObjID, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rally history: %w", err)
+	}
+
+	// Entries recorded before a field was made private still carry it
+	for i := range entries {
+		entries[i].Changes = withoutPrivateFields(entries[i].TargetType, entries[i].Changes)
+	}
+
+	totalPages := utils.CalcTotalPages(total, pageSize)
+
+	return &model.AuditLogListResponse{
+		Entries:    entries,
+		Total:      int(total),
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+		Pagination: model.PaginationMetadata{
+			HasNextPage:     page < totalPages,
+			HasPreviousPage: page > 1,
+		},
+	}, nil
+}
+
+// RevertEntry re-applies the previous values recorded in an event or activity update entry through the
+// regular update path (middleware ensures owner or editor role). Fields that were unset before the change
+// are removed again. It refuses if a later change touched any of the same fields, or if the target
+// changed while the revert was being applied.
+func (s *AuditLogService) RevertEntry(ctx context.Context, user *model.User, rallyID string, entryID string) (*model.RevertResponse, error) {
+	if _, err := primitive.ObjectIDFromHex(entryID); err != nil {
+		return nil, errors.New("invalid history entry ID")
+	}
+
+	entry, err := s.auditRepo.GetAuditLogByID(ctx, entryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history entry: %w", err)
+	}
+	if entry == nil || entry.RallyID.Hex() != rallyID {
+		return nil, errors.New("history entry not found")
+	}
+
+	revertible := entry.Action == model.AuditActionUpdate || entry.Action == model.AuditActionRevert
+	if !revertible || len(entry.Changes) == 0 {
+		return nil, errors.New("history entry cannot be reverted")
+	}
+
+	if err := ensureRallyEditable(ctx, s.rallyRepo, rallyID); err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, len(entry.Changes))
+	for i, c := range entry.Changes {
+		fields[i] = c.Field
+	}
+
+	conflict, err := s.auditRepo.HasLaterFieldChanges(ctx, entry, entry.TargetID, fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check later changes: %w", err)
+	}
+	if !conflict {
+		conflict, err = s.hasLaterReorder(ctx, entry, fields)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check later changes: %w", err)
+		}
+	}
+	if conflict {
+		return nil, errors.New("cannot revert: a later change modified the same fields")
+	}
+
+	switch entry.TargetType {
+	case model.AuditTargetEvent:
+		return s.revertEvent(ctx, user, entry)
+	case model.AuditTargetActivity:
+		return s.revertActivity(ctx, user, entry)
+	default:
+		return nil, errors.New("history entry cannot be reverted")
+	}
+}
+
+// hasLaterReorder reports whether an entry that changed an event's visit order or an activity's order
+// was followed by a bulk reorder. Bulk reorders are logged once against the parent (the rally or the
+// event) rather than on every item they move.
+func (s *AuditLogService) hasLaterReorder(ctx context.Context, entry *model.AuditLog, fields []string) (bool, error) {
+	var parentID primitive.ObjectID
+	var reorderField string
+
+	switch {
+	case entry.TargetType == model.AuditTargetEvent && slices.Contains(fields, "visit_order"):
+		parentID, reorderField = entry.RallyID, "event_order"
+	case entry.TargetType == model.AuditTargetActivity && slices.Contains(fields, "activity_order"):
+		activity, err := s.activityRepo.GetActivityByID(ctx, entry.TargetID.Hex())
+		if err != nil {
+			return false, err
+		}
+		if activity == nil {
+			return false, nil // Reported as a missing target by the revert itself
+		}
+		parentID, reorderField = activity.EventID, "activity_order"
+	default:
+		return false, nil
+	}
+
+	return s.auditRepo.HasLaterFieldChanges(ctx, entry, parentID, []string{reorderField})
+}
+
+func (s *AuditLogService) revertEvent(ctx context.Context, user *model.User, entry *model.AuditLog) (*model.RevertResponse, error) {
+	req, err := eventUpdateFromChanges(entry.Changes)
+	if err != nil {
+		return nil, err
+	}
+
+	eventID := entry.TargetID.Hex()
+	current, err := s.eventRepo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	if current == nil {
+		return nil, errors.New("cannot revert: target no longer exists")
+	}
+
+	// Guard against a concurrent edit between the history check and the update
+	updated, err := s.eventRepo.UpdateEvent(ctx, eventID, req, &current.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revert event: %w", err)
+	}
+	if updated == nil {
+		return nil, errors.New("cannot revert: a later change modified the same fields")
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    entry.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionRevert,
+		TargetType: model.AuditTargetEvent,
+		TargetID:   updated.ID,
+		Changes:    diffFields(current, updated),
+	})
+
+	resp := convertToEventResponse(updated)
+	publishRallyUpdate(ctx, s.pubsub, entry.RallyID, user.ID, model.RealtimeEventUpdated, resp)
+
+	return &model.RevertResponse{
+		TargetType: model.AuditTargetEvent,
+		Event:      resp,
+	}, nil
+}
+
+func (s *AuditLogService) revertActivity(ctx context.Context, user *model.User, entry *model.AuditLog) (*model.RevertResponse, error) {
+	req, err := activityUpdateFromChanges(entry.Changes)
+	if err != nil {
+		return nil, err
+	}
+
+	activityID := entry.TargetID.Hex()
+	current, err := s.activityRepo.GetActivityByID(ctx, activityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activity: %w", err)
+	}
+	if current == nil {
+		return nil, errors.New("cannot revert: target no longer exists")
+	}
+
+	// Guard against a concurrent edit between the history check and the update
+	updated, err := s.activityRepo.UpdateActivity(ctx, activityID, req, &current.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revert activity: %w", err)
+	}
+	if updated == nil {
+		return nil, errors.New("cannot revert: a later change modified the same fields")
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    entry.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionRevert,
+		TargetType: model.AuditTargetActivity,
+		TargetID:   updated.ID,
+		Changes:    diffFields(current, updated),
+	})
+
+	resp := convertToActivityResponse(updated)
+	publishRallyUpdate(ctx, s.pubsub, entry.RallyID, user.ID, model.RealtimeActivityUpdated, resp)
+
+	return &model.RevertResponse{
+		TargetType: model.AuditTargetActivity,
+		Activity:   resp,
+	}, nil
+}
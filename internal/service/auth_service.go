@@ -4,22 +4,36 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 
 	"firebase.google.com/go/v4/auth"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/repository"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/utils"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type AuthService struct {
-	firebaseAuth *auth.Client
-	userRepo     repository.UserRepository
+	firebaseAuth    *auth.Client
+	userRepo        repository.UserRepository
+	rallyRepo       repository.RallyRepository
+	participantRepo repository.RallyParticipantRepository
+	emailInviteRepo repository.EmailInvitationRepository
 }
 
-func NewAuthService(firebaseAuth *auth.Client, userRepo repository.UserRepository) *AuthService {
+func NewAuthService(
+	firebaseAuth *auth.Client,
+	userRepo repository.UserRepository,
+	rallyRepo repository.RallyRepository,
+	participantRepo repository.RallyParticipantRepository,
+	emailInviteRepo repository.EmailInvitationRepository,
+) *AuthService {
 	return &AuthService{
-		firebaseAuth: firebaseAuth,
-		userRepo:     userRepo,
+		firebaseAuth:    firebaseAuth,
+		userRepo:        userRepo,
+		rallyRepo:       rallyRepo,
+		participantRepo: participantRepo,
+		emailInviteRepo: emailInviteRepo,
 	}
 }
 
@@ -36,10 +50,17 @@ func (s *AuthService) RegisterOrLogin(ctx context.Context, idToken string) (*mod
 		return nil, errors.New("email not found in token claims")
 	}
 
+	// Invitations sent to this address are only handed over once Firebase has verified the
+	// user owns it; unverified users pick them up on a later call after verifying
+	emailVerified, _ := token.Claims["email_verified"].(bool)
+
 	// Check if user exists
 	existingUser, err := s.userRepo.GetUserByFirebaseUID(ctx, token.UID)
 	if err == nil && existingUser != nil {
 		// User exists, return it
+		if emailVerified {
+			s.claimEmailInvitations(ctx, existingUser, email)
+		}
 		return existingUser, nil
 	}
 
@@ -59,9 +80,74 @@ func (s *AuthService) RegisterOrLogin(ctx context.Context, idToken string) (*mod
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
+	if emailVerified {
+		s.claimEmailInvitations(ctx, newUser, email)
+	}
+
 	return newUser, nil
 }
 
+// claimEmailInvitations turns the rally invitations sent to an email address before its owner had
+// an account into regular "invited" participants. The original invitation time is kept so the
+// invitation expires on the same schedule. Failures are logged and never block signing in.
+func (s *AuthService) claimEmailInvitations(ctx context.Context, user *model.User, email string) {
+	invitations, err := s.emailInviteRepo.GetEmailInvitationsByEmail(ctx, utils.NormalizeEmail(email))
+	if err != nil {
+		log.Printf("[invitations] failed to load email invitations for user %s: %v", user.ID.Hex(), err)
+		return
+	}
+
+	for _, invitation := range invitations {
+		if err := s.claimEmailInvitation(ctx, user, invitation); err != nil {
+			log.Printf("[invitations] failed to claim email invitation %s: %v", invitation.ID.Hex(), err)
+		}
+	}
+}
+
+func (s *AuthService) claimEmailInvitation(ctx context.Context, user *model.User, invitation *model.EmailInvitation) error {
+	// A rally in the trash can still be restored, so its invitations are claimed like any other.
+	// Only rallies purged since the invitation was sent are skipped, the invitation is just dropped.
+	rally, err := s.rallyRepo.GetRallyByID(ctx, invitation.RallyID.Hex())
+	if err != nil {
+		return fmt.Errorf("failed to get rally: %w", err)
+	}
+	if rally == nil {
+		rally, err = s.rallyRepo.GetDeletedRallyByID(ctx, invitation.RallyID.Hex())
+		if err != nil {
+			return fmt.Errorf("failed to get deleted rally: %w", err)
+		}
+	}
+
+	if rally != nil {
+		existing, err := s.participantRepo.GetParticipantByRallyAndUser(ctx, invitation.RallyID, user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to check existing participant: %w", err)
+		}
+
+		if existing == nil {
+			invitedBy := invitation.InvitedBy
+			participant := &model.RallyParticipant{
+				ID:        primitive.NewObjectID(),
+				RallyID:   invitation.RallyID,
+				UserID:    user.ID,
+				Role:      invitation.Role,
+				Status:    model.ParticipationStatusInvited,
+				InvitedBy: &invitedBy,
+				InvitedAt: invitation.InvitedAt,
+			}
+			if err := s.participantRepo.CreateParticipant(ctx, participant); err != nil {
+				return fmt.Errorf("failed to create participant: %w", err)
+			}
+		}
+	}
+
+	if err := s.emailInviteRepo.DeleteEmailInvitation(ctx, invitation.ID); err != nil {
+		return fmt.Errorf("failed to delete email invitation: %w", err)
+	}
+
+	return nil
+}
+
 func (s *AuthService) Login(ctx context.Context, idToken string) (*model.User, error) {
 	// Verify Firebase token
 	token, err := s.firebaseAuth.VerifyIDToken(ctx, idToken)
@@ -0,0 +1,511 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/realtime"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/repository"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxBudgetNotesLength is the longest budget note accepted, in characters
+const maxBudgetNotesLength = 500
+
+type BudgetService struct {
+	budgetRepo      repository.BudgetRepository
+	expenseRepo     repository.ExpenseRepository
+	rallyRepo       repository.RallyRepository
+	eventRepo       repository.EventRepository
+	participantRepo repository.RallyParticipantRepository
+	rateRepo        repository.ExchangeRateRepository
+	auditRepo       repository.AuditLogRepository
+	pubsub          realtime.PubSub
+}
+
+func NewBudgetService(
+	budgetRepo repository.BudgetRepository,
+	expenseRepo repository.ExpenseRepository,
+	rallyRepo repository.RallyRepository,
+	eventRepo repository.EventRepository,
+	participantRepo repository.RallyParticipantRepository,
+	rateRepo repository.ExchangeRateRepository,
+	auditRepo repository.AuditLogRepository,
+	pubsub realtime.PubSub,
+) *BudgetService {
+	return &BudgetService{
+		budgetRepo:      budgetRepo,
+		expenseRepo:     expenseRepo,
+		rallyRepo:       rallyRepo,
+		eventRepo:       eventRepo,
+		participantRepo: participantRepo,
+		rateRepo:        rateRepo,
+		auditRepo:       auditRepo,
+		pubsub:          pubsub,
+	}
+}
+
+// CreateBudget plans spend for a category of a rally or one of its events (middleware ensures owner or
+// editor role). The currency defaults to the rally's base currency.
+func (s *BudgetService) CreateBudget(ctx context.Context, user *model.User, rallyID string, req *model.CreateBudgetRequest) (*model.BudgetResponse, error) {
+	rally, err := getUnarchivedRally(ctx, s.rallyRepo, rallyID, "rally is archived and its budget cannot be changed")
+	if err != nil {
+		return nil, err
+	}
+
+	budget := &model.Budget{
+		ID:        primitive.NewObjectID(),
+		RallyID:   rally.ID,
+		CreatedBy: user.ID,
+	}
+
+	if req.EventID != "" {
+		event, err := s.eventRepo.GetEventByID(ctx, req.EventID)
+		if err != nil || event == nil || event.RallyID != rally.ID {
+			return nil, errors.New("event not found")
+		}
+		budget.EventID = &event.ID
+	}
+
+	currency := req.Currency
+	if currency == "" {
+		currency = rally.BaseCurrency
+	}
+	if err := setBudgetDetails(budget, req.Category, req.Amount, currency, req.Notes); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.budgetRepo.GetBudget(ctx, rally.ID, budget.EventID, budget.Category)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing budget: %w", err)
+	}
+	if existing != nil {
+		return nil, errors.New("a budget already exists for this category")
+	}
+
+	if err := s.budgetRepo.CreateBudget(ctx, budget); err != nil {
+		return nil, fmt.Errorf("failed to create budget: %w", err)
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    rally.ID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionCreate,
+		TargetType: model.AuditTargetBudget,
+		TargetID:   budget.ID,
+		Changes:    diffFields(nil, budget),
+	})
+
+	resp := convertToBudgetResponse(budget)
+	publishRallyUpdate(ctx, s.pubsub, rally.ID, user.ID, model.RealtimeBudgetCreated, resp)
+
+	return resp, nil
+}
+
+// GetBudgets retrieves every budget of a rally (middleware ensures joined participant)
+func (s *BudgetService) GetBudgets(ctx context.Context, rallyID string) (*model.BudgetListResponse, error) {
+	rallyObjID, err := primitive.ObjectIDFromHex(rallyID)
+	if err != nil {
+		return nil, errors.New("invalid rally ID")
+	}
+
+	budgets, err := s.budgetRepo.GetBudgetsByRally(ctx, rallyObjID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budgets: %w", err)
+	}
+
+	responses := make([]model.BudgetResponse, len(budgets))
+	for i := range budgets {
+		responses[i] = *convertToBudgetResponse(&budgets[i])
+	}
+
+	return &model.BudgetListResponse{Budgets: responses}, nil
+}
+
+// UpdateBudget changes the category, amount, currency or notes of a budget (middleware ensures owner
+// or editor role)
+func (s *BudgetService) UpdateBudget(ctx context.Context, user *model.User, rallyID string, budgetID string, req *model.UpdateBudgetRequest) (*model.BudgetResponse, error) {
+	existing, err := s.getBudget(ctx, rallyID, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := getUnarchivedRally(ctx, s.rallyRepo, rallyID, "rally is archived and its budget cannot be changed"); err != nil {
+		return nil, err
+	}
+
+	budget := *existing
+	category, amount, currency, notes := budget.Category, budget.Amount, budget.Currency, budget.Notes
+	if req.Category != nil {
+		category = *req.Category
+	}
+	if req.Amount != nil {
+		amount = *req.Amount
+	}
+	if req.Currency != nil {
+		currency = *req.Currency
+	}
+	if req.Notes != nil {
+		notes = *req.Notes
+	}
+	if err := setBudgetDetails(&budget, category, amount, currency, notes); err != nil {
+		return nil, err
+	}
+
+	if budget.Category != existing.Category {
+		conflicting, err := s.budgetRepo.GetBudget(ctx, budget.RallyID, budget.EventID, budget.Category)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check existing budget: %w", err)
+		}
+		if conflicting != nil {
+			return nil, errors.New("a budget already exists for this category")
+		}
+	}
+
+	updated, err := s.budgetRepo.UpdateBudget(ctx, &budget)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update budget: %w", err)
+	}
+	if updated == nil {
+		return nil, errors.New("budget not found")
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    updated.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionUpdate,
+		TargetType: model.AuditTargetBudget,
+		TargetID:   updated.ID,
+		Changes:    diffFields(existing, updated),
+	})
+
+	resp := convertToBudgetResponse(updated)
+	publishRallyUpdate(ctx, s.pubsub, updated.RallyID, user.ID, model.RealtimeBudgetUpdated, resp)
+
+	return resp, nil
+}
+
+// DeleteBudget permanently removes a budget (middleware ensures owner or editor role)
+func (s *BudgetService) DeleteBudget(ctx context.Context, user *model.User, rallyID string, budgetID string) error {
+	budget, err := s.getBudget(ctx, rallyID, budgetID)
+	if err != nil {
+		return err
+	}
+	if _, err := getUnarchivedRally(ctx, s.rallyRepo, rallyID, "rally is archived and its budget cannot be changed"); err != nil {
+		return err
+	}
+
+	if err := s.budgetRepo.DeleteBudget(ctx, budget.ID); err != nil {
+		if err.Error() == "budget not found" {
+			return err
+		}
+		return fmt.Errorf("failed to delete budget: %w", err)
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    budget.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionDelete,
+		TargetType: model.AuditTargetBudget,
+		TargetID:   budget.ID,
+		Changes:    diffFields(budget, nil),
+	})
+
+	publishRallyUpdate(ctx, s.pubsub, budget.RallyID, user.ID, model.RealtimeBudgetDeleted, &model.RealtimeDeletedData{ID: budget.ID.Hex()})
+
+	return nil
+}
+
+// GetSummary compares a rally's budgets with what was actually spent according to its expenses, per
+// category and per event, and projects what each joined participant will owe once the remaining
+// budget is spent, shared equally (middleware ensures joined participant). Everything is converted
+// into the rally's base currency; amounts in a currency without an exchange rate are left out and
+// reported. Budgets of events in the trash are ignored.
+func (s *BudgetService) GetSummary(ctx context.Context, rallyID string) (*model.BudgetSummaryResponse, error) {
+	if _, err := primitive.ObjectIDFromHex(rallyID); err != nil {
+		return nil, errors.New("invalid rally ID")
+	}
+
+	rally, err := s.rallyRepo.GetRallyByID(ctx, rallyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rally: %w", err)
+	}
+	if rally == nil {
+		return nil, errors.New("rally not found")
+	}
+	if rally.BaseCurrency == "" {
+		return nil, errors.New("rally has no base currency")
+	}
+
+	budgets, err := s.budgetRepo.GetBudgetsByRally(ctx, rally.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budgets: %w", err)
+	}
+	expenses, err := s.expenseRepo.GetAllExpensesByRally(ctx, rally.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expenses: %w", err)
+	}
+	events, err := s.eventRepo.GetEventsByRally(ctx, rally.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+	joinedIDs, err := s.participantRepo.GetJoinedUserIDs(ctx, rally.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rally participants: %w", err)
+	}
+	rates, err := loadExchangeRates(ctx, s.rateRepo, rally.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.BudgetSummaryResponse{
+		RallyID:      rally.ID.Hex(),
+		Currency:     rally.BaseCurrency,
+		Categories:   []model.CategorySpend{},
+		Events:       []model.EventBudgetSummary{},
+		Participants: []model.ParticipantProjection{},
+		Rates:        []model.ExchangeRateResponse{},
+		MissingRates: []string{},
+	}
+
+	applied := make(map[primitive.ObjectID]bool)
+	missing := make(map[string]bool)
+	convert := func(amount int64, currency string) (int64, bool, error) {
+		if currency == rally.BaseCurrency {
+			return amount, true, nil
+		}
+		rate, stored := rates.lookup(currency, rally.BaseCurrency)
+		if rate == nil {
+			missing[currency] = true
+			return 0, false, nil
+		}
+		if !applied[stored.ID] {
+			applied[stored.ID] = true
+			response.Rates = append(response.Rates, *convertToExchangeRateResponse(stored))
+		}
+		converted, err := convertAmount(amount, currency, rally.BaseCurrency, rate)
+		return converted, err == nil, err
+	}
+
+	liveEvents := make(map[primitive.ObjectID]bool, len(events))
+	for _, event := range events {
+		liveEvents[event.ID] = true
+	}
+
+	type spend map[model.ExpenseCategory]int64
+	rallyPlanned, rallyBudgeted := spend{}, map[model.ExpenseCategory]bool{}
+	eventPlanned, eventPlannedTotal := map[primitive.ObjectID]spend{}, spend{}
+	for _, budget := range budgets {
+		if budget.EventID != nil && !liveEvents[*budget.EventID] {
+			continue
+		}
+		amount, ok, err := convert(budget.Amount, budget.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		if budget.EventID == nil {
+			rallyPlanned[budget.Category] += amount
+			rallyBudgeted[budget.Category] = true
+			continue
+		}
+		if eventPlanned[*budget.EventID] == nil {
+			eventPlanned[*budget.EventID] = spend{}
+		}
+		eventPlanned[*budget.EventID][budget.Category] += amount
+		eventPlannedTotal[budget.Category] += amount
+	}
+	// Without a rally-wide budget, a category is planned through its event budgets
+	for category, amount := range eventPlannedTotal {
+		if !rallyBudgeted[category] {
+			rallyPlanned[category] = amount
+		}
+	}
+
+	actual, eventActual := spend{}, map[primitive.ObjectID]spend{}
+	spent := make(map[primitive.ObjectID]int64)
+	for _, expense := range expenses {
+		amount, ok, err := convert(expense.Amount, expense.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		category := expense.Category
+		if category == "" {
+			category = model.ExpenseCategoryOther
+		}
+		actual[category] += amount
+		if expense.EventID != nil && liveEvents[*expense.EventID] {
+			if eventActual[*expense.EventID] == nil {
+				eventActual[*expense.EventID] = spend{}
+			}
+			eventActual[*expense.EventID][category] += amount
+		}
+
+		owed := make([]int64, len(expense.Splits))
+		for i, split := range expense.Splits {
+			owed[i] = split.Amount
+		}
+		for i, part := range allocateProportionally(amount, owed) {
+			spent[expense.Splits[i].UserID] += part
+		}
+	}
+
+	response.Categories, response.Planned, response.Actual, response.Unbudgeted = summarizeCategories(rallyPlanned, actual)
+	response.Remaining = response.Planned - response.Actual
+	response.OverBudget = isOverBudget(response.Planned, response.Actual)
+
+	for _, event := range events {
+		if eventPlanned[event.ID] == nil && eventActual[event.ID] == nil {
+			continue
+		}
+		categories, planned, actualTotal, unbudgeted := summarizeCategories(eventPlanned[event.ID], eventActual[event.ID])
+		response.Events = append(response.Events, model.EventBudgetSummary{
+			EventID:    event.ID.Hex(),
+			EventName:  event.Name,
+			Planned:    planned,
+			Actual:     actualTotal,
+			Unbudgeted: unbudgeted,
+			Remaining:  planned - actualTotal,
+			OverBudget: isOverBudget(planned, actualTotal),
+			Categories: categories,
+		})
+	}
+
+	// The budget still to be spent is shared equally by the joined participants; anyone who has
+	// left keeps what they already owe
+	sort.Slice(joinedIDs, func(a, b int) bool { return joinedIDs[a].Hex() < joinedIDs[b].Hex() })
+	projected := make(map[primitive.ObjectID]int64, len(spent)+len(joinedIDs))
+	for userID, amount := range spent {
+		projected[userID] = amount
+	}
+	if remaining := response.Remaining; remaining > 0 && len(joinedIDs) > 0 {
+		weights := make([]int64, len(joinedIDs))
+		for i := range weights {
+			weights[i] = 1
+		}
+		for i, share := range allocateProportionally(remaining, weights) {
+			projected[joinedIDs[i]] += share
+		}
+	}
+	for _, userID := range joinedIDs {
+		if _, ok := projected[userID]; !ok {
+			projected[userID] = 0
+		}
+	}
+	for userID, amount := range projected {
+		response.Participants = append(response.Participants, model.ParticipantProjection{
+			UserID:    userID.Hex(),
+			Spent:     spent[userID],
+			Projected: amount,
+		})
+	}
+	sort.Slice(response.Participants, func(a, b int) bool {
+		return response.Participants[a].UserID < response.Participants[b].UserID
+	})
+
+	for currency := range missing {
+		response.MissingRates = append(response.MissingRates, currency)
+	}
+	sort.Strings(response.MissingRates)
+
+	return response, nil
+}
+
+// getBudget loads a budget and checks it belongs to the rally
+func (s *BudgetService) getBudget(ctx context.Context, rallyID string, budgetID string) (*model.Budget, error) {
+	budget, err := s.budgetRepo.GetBudgetByID(ctx, budgetID)
+	if err != nil || budget == nil || budget.RallyID.Hex() != rallyID {
+		return nil, errors.New("budget not found")
+	}
+	return budget, nil
+}
+
+// setBudgetDetails validates and sets the category, amount, currency and notes of a budget
+func setBudgetDetails(budget *model.Budget, category model.ExpenseCategory, amount int64, currency string, notes string) error {
+	if !isValidExpenseCategory(category) {
+		return errors.New("invalid expense category")
+	}
+	if amount <= 0 || amount > maxExpenseAmount {
+		return errors.New("budget amount must be positive")
+	}
+	if currency == "" {
+		return errors.New("budget currency is required")
+	}
+	code, ok := utils.NormalizeCurrency(currency)
+	if !ok {
+		return errors.New("invalid currency")
+	}
+	notes = strings.TrimSpace(notes)
+	if len([]rune(notes)) > maxBudgetNotesLength {
+		return errors.New("budget notes are too long")
+	}
+
+	budget.Category = category
+	budget.Amount = amount
+	budget.Currency = code
+	budget.Notes = notes
+	return nil
+}
+
+// summarizeCategories compares planned and actual spend for every category that has either, in
+// display order, and returns the totals. Like isOverBudget, the actual total only covers categories
+// with something planned; spend in the other categories is totalled separately as unbudgeted.
+func summarizeCategories(planned map[model.ExpenseCategory]int64, actual map[model.ExpenseCategory]int64) ([]model.CategorySpend, int64, int64, int64) {
+	categories := []model.CategorySpend{}
+	var plannedTotal, actualTotal, unbudgetedTotal int64
+	for _, category := range model.ExpenseCategories {
+		p, a := planned[category], actual[category]
+		if p == 0 && a == 0 {
+			continue
+		}
+		categories = append(categories, model.CategorySpend{
+			Category:   category,
+			Planned:    p,
+			Actual:     a,
+			Remaining:  p - a,
+			OverBudget: isOverBudget(p, a),
+		})
+		if p == 0 {
+			unbudgetedTotal += a
+			continue
+		}
+		plannedTotal += p
+		actualTotal += a
+	}
+	return categories, plannedTotal, actualTotal, unbudgetedTotal
+}
+
+// isOverBudget reports whether more was spent than planned; unplanned spend is not flagged
+func isOverBudget(planned int64, actual int64) bool {
+	return planned > 0 && actual > planned
+}
+
+// convertToBudgetResponse converts a Budget model to BudgetResponse
+func convertToBudgetResponse(budget *model.Budget) *model.BudgetResponse {
+	resp := &model.BudgetResponse{
+		ID:        budget.ID.Hex(),
+		RallyID:   budget.RallyID.Hex(),
+		Category:  budget.Category,
+		Amount:    budget.Amount,
+		Currency:  budget.Currency,
+		Notes:     budget.Notes,
+		CreatedBy: budget.CreatedBy.Hex(),
+		CreatedAt: budget.CreatedAt,
+		UpdatedAt: budget.UpdatedAt,
+	}
+	if budget.EventID != nil {
+		resp.EventID = budget.EventID.Hex()
+	}
+	return resp
+}
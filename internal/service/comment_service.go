@@ -0,0 +1,523 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/realtime"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/repository"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxCommentLength   = 2000 // Longest comment accepted, in characters
+	maxCommentMentions = 20   // Most distinct users a single comment can mention
+)
+
+// mentionPattern matches @username mentions; trailing dots and dashes are punctuation, not part of the name
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w.\-]+)`)
+
+// allParticipantRoles lets any joined participant through validateRallyAccess
+var allParticipantRoles = []string{"owner", "editor", "participant"}
+
+type CommentService struct {
+	commentRepo     repository.CommentRepository
+	rallyRepo       repository.RallyRepository
+	eventRepo       repository.EventRepository
+	activityRepo    repository.ActivityRepository
+	participantRepo repository.RallyParticipantRepository
+	userRepo        repository.UserRepository
+	auditRepo       repository.AuditLogRepository
+	notifications   *NotificationService
+	pubsub          realtime.PubSub
+}
+
+func NewCommentService(
+	commentRepo repository.CommentRepository,
+	rallyRepo repository.RallyRepository,
+	eventRepo repository.EventRepository,
+	activityRepo repository.ActivityRepository,
+	participantRepo repository.RallyParticipantRepository,
+	userRepo repository.UserRepository,
+	auditRepo repository.AuditLogRepository,
+	notifications *NotificationService,
+	pubsub realtime.PubSub,
+) *CommentService {
+	return &CommentService{
+		commentRepo:     commentRepo,
+		rallyRepo:       rallyRepo,
+		eventRepo:       eventRepo,
+		activityRepo:    activityRepo,
+		participantRepo: participantRepo,
+		userRepo:        userRepo,
+		auditRepo:       auditRepo,
+		notifications:   notifications,
+		pubsub:          pubsub,
+	}
+}
+
+// commentTarget is the rally, event or activity a thread is about, resolved to its rally
+type commentTarget struct {
+	Type    model.CommentTargetType
+	ID      primitive.ObjectID
+	RallyID primitive.ObjectID
+}
+
+// CreateComment starts a thread on a rally, event or activity (requires joined participant in the
+// target's rally). Joined participants mentioned with @username are notified.
+func (s *CommentService) CreateComment(ctx context.Context, user *model.User, targetType model.CommentTargetType, targetID string, req *model.CreateCommentRequest) (*model.CommentResponse, error) {
+	target, err := s.resolveTarget(ctx, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateRallyAccess(ctx, s.participantRepo, user.ID, target.RallyID.Hex(), allParticipantRoles); err != nil {
+		return nil, err
+	}
+
+	comment := &model.Comment{
+		ID:         primitive.NewObjectID(),
+		RallyID:    target.RallyID,
+		TargetType: target.Type,
+		TargetID:   target.ID,
+		AuthorID:   user.ID,
+	}
+	return s.createComment(ctx, user, comment, req.Body)
+}
+
+// CreateReply replies to a comment (requires joined participant in the comment's rally). Replying to
+// a reply adds to the same thread, since threads are one level deep.
+func (s *CommentService) CreateReply(ctx context.Context, user *model.User, commentID string, req *model.CreateCommentRequest) (*model.CommentResponse, error) {
+	parent, err := s.getComment(ctx, user, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if parent.DeletedAt != nil {
+		return nil, errors.New("comment not found")
+	}
+	if parent.ParentID != nil {
+		if parent, err = s.getComment(ctx, user, parent.ParentID.Hex()); err != nil {
+			return nil, err
+		}
+		if parent.DeletedAt != nil {
+			return nil, errors.New("comment not found")
+		}
+	}
+
+	comment := &model.Comment{
+		ID:         primitive.NewObjectID(),
+		RallyID:    parent.RallyID,
+		TargetType: parent.TargetType,
+		TargetID:   parent.TargetID,
+		ParentID:   &parent.ID,
+		AuthorID:   user.ID,
+	}
+	resp, err := s.createComment(ctx, user, comment, req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.commentRepo.IncrementReplyCount(ctx, parent.ID, 1); err != nil {
+		return nil, fmt.Errorf("failed to update reply count: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetComments retrieves a paginated list of the threads on a rally, event or activity, newest first
+// (requires joined participant in the target's rally)
+func (s *CommentService) GetComments(ctx context.Context, user *model.User, targetType model.CommentTargetType, targetID string, page, pageSize int) (*model.CommentListResponse, error) {
+	target, err := s.resolveTarget(ctx, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateRallyAccess(ctx, s.participantRepo, user.ID, target.RallyID.Hex(), allParticipantRoles); err != nil {
+		return nil, err
+	}
+
+	comments, total, err := s.commentRepo.GetThreads(ctx, target.Type, target.ID, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+
+	return buildCommentListResponse(comments, total, page, pageSize), nil
+}
+
+// GetReplies retrieves a paginated list of the replies in a comment's thread, oldest first (requires
+// joined participant in the comment's rally)
+func (s *CommentService) GetReplies(ctx context.Context, user *model.User, commentID string, page, pageSize int) (*model.CommentListResponse, error) {
+	comment, err := s.getComment(ctx, user, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.ParentID != nil {
+		return nil, errors.New("replies cannot have replies")
+	}
+
+	comments, total, err := s.commentRepo.GetReplies(ctx, comment.ID, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replies: %w", err)
+	}
+
+	return buildCommentListResponse(comments, total, page, pageSize), nil
+}
+
+// UpdateComment edits the body of a comment (requires being its author and still a joined participant).
+// Participants mentioned for the first time are notified.
+func (s *CommentService) UpdateComment(ctx context.Context, user *model.User, commentID string, req *model.UpdateCommentRequest) (*model.CommentResponse, error) {
+	comment, err := s.getComment(ctx, user, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.DeletedAt != nil {
+		return nil, errors.New("comment not found")
+	}
+	if comment.AuthorID != user.ID {
+		return nil, errors.New("unauthorized: only the author can edit a comment")
+	}
+	if _, err := getUnarchivedRally(ctx, s.rallyRepo, comment.RallyID.Hex(), "rally is archived and its comments cannot be changed"); err != nil {
+		return nil, err
+	}
+
+	body, err := validateCommentBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	mentions, err := s.resolveMentions(ctx, comment.RallyID, body)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.commentRepo.UpdateComment(ctx, comment.ID, body, mentions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+	if updated == nil {
+		return nil, errors.New("comment not found")
+	}
+
+	alreadyMentioned := make(map[primitive.ObjectID]bool, len(comment.Mentions))
+	for _, userID := range comment.Mentions {
+		alreadyMentioned[userID] = true
+	}
+	var newMentions []primitive.ObjectID
+	for _, userID := range mentions {
+		if !alreadyMentioned[userID] {
+			newMentions = append(newMentions, userID)
+		}
+	}
+	s.notifyMentions(ctx, user, updated, newMentions)
+
+	resp := convertToCommentResponse(updated, participantUserInfo(user))
+	publishRallyUpdate(ctx, s.pubsub, updated.RallyID, user.ID, model.RealtimeCommentUpdated, resp)
+
+	return resp, nil
+}
+
+// DeleteComment removes a comment (requires being its author, or an owner or editor of its rally to
+// moderate). A thread's top-level comment that still has replies is blanked instead, and is removed
+// once its last reply is.
+func (s *CommentService) DeleteComment(ctx context.Context, user *model.User, commentID string) error {
+	comment, err := s.getComment(ctx, user, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.DeletedAt != nil {
+		return errors.New("comment not found")
+	}
+	moderated := comment.AuthorID != user.ID
+	if moderated {
+		if err := validateRallyAccess(ctx, s.participantRepo, user.ID, comment.RallyID.Hex(), []string{"owner", "editor"}); err != nil {
+			return err
+		}
+	}
+	if _, err := getUnarchivedRally(ctx, s.rallyRepo, comment.RallyID.Hex(), "rally is archived and its comments cannot be changed"); err != nil {
+		return err
+	}
+
+	if comment.ParentID == nil && comment.ReplyCount > 0 {
+		deleted, err := s.commentRepo.SoftDeleteComment(ctx, comment.ID, user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to delete comment: %w", err)
+		}
+		if deleted == nil {
+			return errors.New("comment not found")
+		}
+		s.recordModeration(ctx, user, comment, moderated)
+		publishRallyUpdate(ctx, s.pubsub, deleted.RallyID, user.ID, model.RealtimeCommentUpdated, convertToCommentResponse(deleted, nil))
+		return nil
+	}
+
+	if err := s.commentRepo.DeleteComment(ctx, comment.ID); err != nil {
+		if err.Error() == "comment not found" {
+			return err
+		}
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	s.recordModeration(ctx, user, comment, moderated)
+	publishRallyUpdate(ctx, s.pubsub, comment.RallyID, user.ID, model.RealtimeCommentDeleted, &model.RealtimeDeletedData{ID: comment.ID.Hex()})
+
+	if comment.ParentID != nil {
+		parent, err := s.commentRepo.IncrementReplyCount(ctx, *comment.ParentID, -1)
+		if err != nil {
+			return fmt.Errorf("failed to update reply count: %w", err)
+		}
+		// A blanked comment only stays to hold its thread together
+		if parent != nil && parent.DeletedAt != nil && parent.ReplyCount <= 0 {
+			if err := s.commentRepo.DeleteComment(ctx, parent.ID); err != nil && err.Error() != "comment not found" {
+				return fmt.Errorf("failed to delete comment: %w", err)
+			}
+			publishRallyUpdate(ctx, s.pubsub, parent.RallyID, user.ID, model.RealtimeCommentDeleted, &model.RealtimeDeletedData{ID: parent.ID.Hex()})
+		}
+	}
+
+	return nil
+}
+
+// createComment validates the body, resolves the mentions and stores a new comment or reply
+func (s *CommentService) createComment(ctx context.Context, user *model.User, comment *model.Comment, body string) (*model.CommentResponse, error) {
+	if _, err := getUnarchivedRally(ctx, s.rallyRepo, comment.RallyID.Hex(), "rally is archived and its comments cannot be changed"); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if comment.Body, err = validateCommentBody(body); err != nil {
+		return nil, err
+	}
+	if comment.Mentions, err = s.resolveMentions(ctx, comment.RallyID, comment.Body); err != nil {
+		return nil, err
+	}
+
+	if err := s.commentRepo.CreateComment(ctx, comment); err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	s.notifyMentions(ctx, user, comment, comment.Mentions)
+
+	resp := convertToCommentResponse(comment, participantUserInfo(user))
+	publishRallyUpdate(ctx, s.pubsub, comment.RallyID, user.ID, model.RealtimeCommentCreated, resp)
+
+	return resp, nil
+}
+
+// resolveTarget loads the live rally, event or activity a thread is about
+func (s *CommentService) resolveTarget(ctx context.Context, targetType model.CommentTargetType, targetID string) (*commentTarget, error) {
+	switch targetType {
+	case model.CommentTargetRally:
+		rallyObjID, err := primitive.ObjectIDFromHex(targetID)
+		if err != nil {
+			return nil, errors.New("invalid rally ID")
+		}
+		return &commentTarget{Type: targetType, ID: rallyObjID, RallyID: rallyObjID}, nil
+	case model.CommentTargetEvent:
+		event, err := s.eventRepo.GetEventByID(ctx, targetID)
+		if err != nil || event == nil {
+			return nil, errors.New("event not found")
+		}
+		return &commentTarget{Type: targetType, ID: event.ID, RallyID: event.RallyID}, nil
+	case model.CommentTargetActivity:
+		activity, err := s.activityRepo.GetActivityByID(ctx, targetID)
+		if err != nil || activity == nil {
+			return nil, errors.New("activity not found")
+		}
+		event, err := s.eventRepo.GetEventByID(ctx, activity.EventID.Hex())
+		if err != nil || event == nil {
+			return nil, errors.New("activity not found")
+		}
+		return &commentTarget{Type: targetType, ID: activity.ID, RallyID: event.RallyID}, nil
+	default:
+		return nil, errors.New("invalid comment target")
+	}
+}
+
+// getComment loads a comment, including a blanked one, and checks the user is a joined participant of
+// its rally
+func (s *CommentService) getComment(ctx context.Context, user *model.User, commentID string) (*model.Comment, error) {
+	comment, err := s.commentRepo.GetCommentByID(ctx, commentID)
+	if err != nil || comment == nil {
+		return nil, errors.New("comment not found")
+	}
+	if err := validateRallyAccess(ctx, s.participantRepo, user.ID, comment.RallyID.Hex(), allParticipantRoles); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// resolveMentions returns the joined participants of the rally mentioned with @username in body, in
+// order of first mention. Names that are not joined participants are left as plain text.
+func (s *CommentService) resolveMentions(ctx context.Context, rallyID primitive.ObjectID, body string) ([]primitive.ObjectID, error) {
+	usernames := extractMentions(body)
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+	if len(usernames) > maxCommentMentions {
+		return nil, errors.New("too many mentions in comment")
+	}
+
+	users, err := s.userRepo.GetUsersByUsernames(ctx, usernames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve mentions: %w", err)
+	}
+	joinedIDs, err := s.participantRepo.GetJoinedUserIDs(ctx, rallyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rally participants: %w", err)
+	}
+	joined := make(map[primitive.ObjectID]bool, len(joinedIDs))
+	for _, userID := range joinedIDs {
+		joined[userID] = true
+	}
+
+	byUsername := make(map[string]primitive.ObjectID, len(users))
+	for _, user := range users {
+		if joined[user.ID] {
+			byUsername[user.Username] = user.ID
+		}
+	}
+
+	var mentions []primitive.ObjectID
+	for _, username := range usernames {
+		if userID, ok := byUsername[username]; ok {
+			mentions = append(mentions, userID)
+		}
+	}
+	return mentions, nil
+}
+
+// notifyMentions tells the mentioned users, other than the author, about a comment
+func (s *CommentService) notifyMentions(ctx context.Context, user *model.User, comment *model.Comment, mentions []primitive.ObjectID) {
+	var notifications []*model.Notification
+	for _, userID := range mentions {
+		if userID == user.ID {
+			continue
+		}
+		notification := &model.Notification{
+			UserID:  userID,
+			Type:    model.NotificationTypeCommentMention,
+			ActorID: &user.ID,
+			RallyID: &comment.RallyID,
+			Data: map[string]interface{}{
+				"commentId":  comment.ID.Hex(),
+				"targetType": comment.TargetType,
+				"targetId":   comment.TargetID.Hex(),
+			},
+		}
+		if comment.TargetType == model.CommentTargetEvent {
+			notification.EventID = &comment.TargetID
+		}
+		notifications = append(notifications, notification)
+	}
+	if len(notifications) > 0 {
+		s.notifications.Notify(ctx, notifications...)
+	}
+}
+
+// recordModeration records the deletion of someone else's comment in the rally history. Authors
+// removing their own comments are not recorded. The removed body and mentions are left out of the
+// entry (see auditPrivateFields), so only the target and author remain.
+func (s *CommentService) recordModeration(ctx context.Context, user *model.User, comment *model.Comment, moderated bool) {
+	if !moderated {
+		return
+	}
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    comment.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionDelete,
+		TargetType: model.AuditTargetComment,
+		TargetID:   comment.ID,
+		Changes:    diffFields(comment, nil),
+	})
+}
+
+// validateCommentBody trims a comment body and checks its length
+func validateCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", errors.New("comment body is required")
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return "", errors.New("comment is too long")
+	}
+	return body, nil
+}
+
+// extractMentions returns the distinct usernames mentioned with @username in body, in order of first
+// mention
+func extractMentions(body string) []string {
+	seen := make(map[string]bool)
+	var usernames []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		username := strings.TrimRight(match[1], ".-")
+		if username == "" || seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+	}
+	return usernames
+}
+
+// participantUserInfo returns the public profile of a user shown next to their comments
+func participantUserInfo(user *model.User) *model.ParticipantUserInfo {
+	return &model.ParticipantUserInfo{
+		ID:        user.ID.Hex(),
+		Username:  user.Username,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		AvatarUrl: user.AvatarUrl,
+	}
+}
+
+// buildCommentListResponse converts a page of comments into a CommentListResponse
+func buildCommentListResponse(comments []model.CommentWithAuthor, total int64, page, pageSize int) *model.CommentListResponse {
+	responses := make([]model.CommentResponse, len(comments))
+	for i := range comments {
+		responses[i] = *convertToCommentResponse(&comments[i].Comment, comments[i].Author)
+	}
+
+	totalPages := utils.CalcTotalPages(total, pageSize)
+
+	return &model.CommentListResponse{
+		Comments:   responses,
+		Total:      int(total),
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+		Pagination: model.PaginationMetadata{
+			HasNextPage:     page < totalPages,
+			HasPreviousPage: page > 1,
+		},
+	}
+}
+
+// convertToCommentResponse converts a Comment model to CommentResponse. Blanked comments are returned
+// without their author.
+func convertToCommentResponse(comment *model.Comment, author *model.ParticipantUserInfo) *model.CommentResponse {
+	resp := &model.CommentResponse{
+		ID:         comment.ID.Hex(),
+		RallyID:    comment.RallyID.Hex(),
+		TargetType: comment.TargetType,
+		TargetID:   comment.TargetID.Hex(),
+		Body:       comment.Body,
+		ReplyCount: comment.ReplyCount,
+		IsEdited:   comment.EditedAt != nil,
+		EditedAt:   comment.EditedAt,
+		IsDeleted:  comment.DeletedAt != nil,
+		CreatedAt:  comment.CreatedAt,
+		UpdatedAt:  comment.UpdatedAt,
+	}
+	if comment.ParentID != nil {
+		resp.ParentID = comment.ParentID.Hex()
+	}
+	if comment.DeletedAt == nil {
+		resp.Author = author
+	}
+	for _, userID := range comment.Mentions {
+		resp.Mentions = append(resp.Mentions, userID.Hex())
+	}
+	return resp
+}
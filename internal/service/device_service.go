@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"firebase.google.com/go/v4/auth"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/repository"
+)
+
+// maxLocaleLength is the longest locale tag accepted at registration, well above any real BCP 47 tag
+const maxLocaleLength = 35
+
+type DeviceService struct {
+	firebaseAuth *auth.Client
+	deviceRepo   repository.DeviceRepository
+	userRepo     repository.UserRepository
+}
+
+func NewDeviceService(
+	firebaseAuth *auth.Client,
+	deviceRepo repository.DeviceRepository,
+	userRepo repository.UserRepository,
+) *DeviceService {
+	return &DeviceService{
+		firebaseAuth: firebaseAuth,
+		deviceRepo:   deviceRepo,
+		userRepo:     userRepo,
+	}
+}
+
+// RegisterDevice registers a push notification token for the authenticated user. Registering a token
+// that is already known updates its platform and locale, so apps can call this on every launch.
+func (s *DeviceService) RegisterDevice(ctx context.Context, idToken string, req *model.RegisterDeviceRequest) (*model.DeviceResponse, error) {
+	user, err := authenticateUser(ctx, s.firebaseAuth, s.userRepo, idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	token := strings.TrimSpace(req.Token)
+	if token == "" {
+		return nil, errors.New("device token is required")
+	}
+	switch req.Platform {
+	case model.DevicePlatformIOS, model.DevicePlatformAndroid, model.DevicePlatformWeb:
+	default:
+		return nil, errors.New("invalid platform")
+	}
+	locale := strings.TrimSpace(req.Locale)
+	if len(locale) > maxLocaleLength {
+		return nil, errors.New("invalid locale")
+	}
+
+	device, err := s.deviceRepo.UpsertDevice(ctx, &model.Device{
+		UserID:   user.ID,
+		Token:    token,
+		Platform: req.Platform,
+		Locale:   locale,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register device: %w", err)
+	}
+
+	return &model.DeviceResponse{
+		ID:        device.ID.Hex(),
+		Platform:  device.Platform,
+		Locale:    device.Locale,
+		CreatedAt: device.CreatedAt,
+		UpdatedAt: device.UpdatedAt,
+	}, nil
+}
+
+// UnregisterDevice removes one of the authenticated user's push notification tokens, e.g. on sign-out
+func (s *DeviceService) UnregisterDevice(ctx context.Context, idToken string, token string) error {
+	user, err := authenticateUser(ctx, s.firebaseAuth, s.userRepo, idToken)
+	if err != nil {
+		return err
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return errors.New("device token is required")
+	}
+
+	deleted, err := s.deviceRepo.DeleteDevice(ctx, user.ID, token)
+	if err != nil {
+		return fmt.Errorf("failed to unregister device: %w", err)
+	}
+	if !deleted {
+		return errors.New("device not found")
+	}
+
+	return nil
+}
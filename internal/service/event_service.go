@@ -4,34 +4,53 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"time"
 
 	"firebase.google.com/go/v4/auth"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/realtime"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/repository"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type EventService struct {
+	db              *mongo.Database
 	firebaseAuth    *auth.Client
 	eventRepo       repository.EventRepository
+	activityRepo    repository.ActivityRepository
 	rallyRepo       repository.RallyRepository
 	participantRepo repository.RallyParticipantRepository
 	userRepo        repository.UserRepository
+	auditRepo       repository.AuditLogRepository
+	notifications   *NotificationService
+	pubsub          realtime.PubSub
 }
 
 func NewEventService(
+	db *mongo.Database,
 	firebaseAuth *auth.Client,
 	eventRepo repository.EventRepository,
+	activityRepo repository.ActivityRepository,
 	rallyRepo repository.RallyRepository,
 	participantRepo repository.RallyParticipantRepository,
 	userRepo repository.UserRepository,
+	auditRepo repository.AuditLogRepository,
+	notifications *NotificationService,
+	pubsub realtime.PubSub,
 ) *EventService {
 	return &EventService{
+		db:              db,
 		firebaseAuth:    firebaseAuth,
 		eventRepo:       eventRepo,
+		activityRepo:    activityRepo,
 		rallyRepo:       rallyRepo,
 		participantRepo: participantRepo,
 		userRepo:        userRepo,
+		auditRepo:       auditRepo,
+		notifications:   notifications,
+		pubsub:          pubsub,
 	}
 }
 
@@ -45,6 +64,9 @@ func (s *EventService) CreateEvent(ctx context.Context, user *model.User, rallyI
 	if rally == nil {
 		return nil, errors.New("rally not found")
 	}
+	if isRallyReadOnly(rally) {
+		return nil, errors.New("rally is archived or completed and cannot be edited")
+	}
 
 	rallyObjID, err := primitive.ObjectIDFromHex(rallyID)
 	if err != nil {
@@ -67,11 +89,24 @@ func (s *EventService) CreateEvent(ctx context.Context, user *model.User, rallyI
 		return nil, fmt.Errorf("failed to create event: %w", err)
 	}
 
-	return s.ConvertToEventResponse(event), nil
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    event.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionCreate,
+		TargetType: model.AuditTargetEvent,
+		TargetID:   event.ID,
+		Changes:    diffFields(nil, event),
+	})
+
+	resp := s.ConvertToEventResponse(event)
+	publishRallyUpdate(ctx, s.pubsub, event.RallyID, user.ID, model.RealtimeEventCreated, resp)
+
+	return resp, nil
 }
 
-// UpdateEvent updates an existing event (requires owner or editor role in the event's rally)
-func (s *EventService) UpdateEvent(ctx context.Context, user *model.User, eventID string, req *model.UpdateEventRequest) (*model.EventResponse, error) {
+// UpdateEvent updates an existing event (requires owner or editor role in the event's rally).
+// If expectedVersion is set and stale, the current event is returned along with a precondition error.
+func (s *EventService) UpdateEvent(ctx context.Context, user *model.User, eventID string, req *model.UpdateEventRequest, expectedVersion *int64) (*model.EventResponse, error) {
 
 	event, err := s.eventRepo.GetEventByID(ctx, eventID)
 	if err != nil {
@@ -84,17 +119,389 @@ func (s *EventService) UpdateEvent(ctx context.Context, user *model.User, eventI
 	if err := validateRallyAccess(ctx, s.participantRepo, user.ID, event.RallyID.Hex(), []string{"owner", "editor"}); err != nil {
 		return nil, err
 	}
+	if err := ensureRallyEditable(ctx, s.rallyRepo, event.RallyID.Hex()); err != nil {
+		return nil, err
+	}
 
-	updated, err := s.eventRepo.UpdateEvent(ctx, eventID, req)
+	updated, err := s.eventRepo.UpdateEvent(ctx, eventID, req, expectedVersion)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update event: %w", err)
 	}
+	if updated == nil {
+		// The event was changed (or deleted) since the client last read it
+		current, err := s.eventRepo.GetEventByID(ctx, eventID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get event: %w", err)
+		}
+		if current == nil {
+			return nil, errors.New("event not found")
+		}
+		return s.ConvertToEventResponse(current), errors.New("precondition failed: resource has been modified")
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    updated.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionUpdate,
+		TargetType: model.AuditTargetEvent,
+		TargetID:   updated.ID,
+		Changes:    diffFields(event, updated),
+	})
+
+	if !sameTime(event.StartTime, updated.StartTime) || !sameTime(event.EndTime, updated.EndTime) {
+		s.notifyEventTimeChanged(ctx, user, updated)
+	}
+
+	resp := s.ConvertToEventResponse(updated)
+	publishRallyUpdate(ctx, s.pubsub, updated.RallyID, user.ID, model.RealtimeEventUpdated, resp)
+
+	return resp, nil
+}
+
+// notifyEventTimeChanged tells every joined participant of the event's rally that it was rescheduled
+func (s *EventService) notifyEventTimeChanged(ctx context.Context, user *model.User, event *model.Event) {
+	userIDs, err := s.participantRepo.GetJoinedUserIDs(ctx, event.RallyID)
+	if err != nil {
+		log.Printf("[notifications] failed to load participants of rally %s: %v", event.RallyID.Hex(), err)
+		return
+	}
+
+	notifications := make([]*model.Notification, len(userIDs))
+	for i, userID := range userIDs {
+		notifications[i] = &model.Notification{
+			UserID:  userID,
+			Type:    model.NotificationTypeEventTimeChanged,
+			ActorID: &user.ID,
+			RallyID: &event.RallyID,
+			EventID: &event.ID,
+			Data: map[string]interface{}{
+				"eventName": event.Name,
+				"startTime": event.StartTime,
+				"endTime":   event.EndTime,
+			},
+		}
+	}
+	s.notifications.Notify(ctx, notifications...)
+}
+
+// sameTime reports whether two optional times are both unset or the same instant
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// DeleteEvent moves an event and all of its activities to the trash in a single transaction
+// (requires owner or editor role in the event's rally)
+func (s *EventService) DeleteEvent(ctx context.Context, user *model.User, eventID string) error {
+
+	event, err := s.eventRepo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to get event: %w", err)
+	}
+	if event == nil {
+		return errors.New("event not found")
+	}
+
+	if err := validateRallyAccess(ctx, s.participantRepo, user.ID, event.RallyID.Hex(), []string{"owner", "editor"}); err != nil {
+		return err
+	}
+	if err := ensureRallyEditable(ctx, s.rallyRepo, event.RallyID.Hex()); err != nil {
+		return err
+	}
+
+	session, err := s.db.Client().StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	deletedAt := time.Now()
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		if err := s.activityRepo.SoftDeleteActivitiesByEvents(sessCtx, []primitive.ObjectID{event.ID}, deletedAt); err != nil {
+			return nil, fmt.Errorf("failed to delete activities: %w", err)
+		}
+		if err := s.eventRepo.SoftDeleteEvent(sessCtx, eventID, deletedAt); err != nil {
+			return nil, fmt.Errorf("failed to delete event: %w", err)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	deleted := *event
+	deleted.DeletedAt = &deletedAt
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    event.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionDelete,
+		TargetType: model.AuditTargetEvent,
+		TargetID:   event.ID,
+		Changes:    diffFields(event, &deleted),
+	})
+
+	publishRallyUpdate(ctx, s.pubsub, event.RallyID, user.ID, model.RealtimeEventDeleted, &model.RealtimeDeletedData{ID: event.ID.Hex()})
+
+	return nil
+}
+
+// RestoreEvent moves an event out of the trash together with the activities that were trashed along
+// with it (requires owner or editor role in the event's rally)
+func (s *EventService) RestoreEvent(ctx context.Context, user *model.User, eventID string) (*model.EventResponse, error) {
+
+	event, err := s.eventRepo.GetDeletedEventByID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	if event == nil {
+		return nil, errors.New("event not found in trash")
+	}
+
+	if err := validateRallyAccess(ctx, s.participantRepo, user.ID, event.RallyID.Hex(), []string{"owner", "editor"}); err != nil {
+		return nil, err
+	}
+
+	rally, err := s.rallyRepo.GetRallyByID(ctx, event.RallyID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rally: %w", err)
+	}
+	if rally == nil {
+		return nil, errors.New("cannot restore event: its rally is deleted")
+	}
+	if isRallyReadOnly(rally) {
+		return nil, errors.New("rally is archived or completed and cannot be edited")
+	}
+
+	session, err := s.db.Client().StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		if err := s.activityRepo.RestoreActivitiesByEvents(sessCtx, []primitive.ObjectID{event.ID}, *event.DeletedAt); err != nil {
+			return nil, fmt.Errorf("failed to restore activities: %w", err)
+		}
+		if err := s.eventRepo.RestoreEvent(sessCtx, eventID); err != nil {
+			return nil, fmt.Errorf("failed to restore event: %w", err)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err := s.eventRepo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	if restored == nil {
+		return nil, errors.New("event not found")
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    restored.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionRestore,
+		TargetType: model.AuditTargetEvent,
+		TargetID:   restored.ID,
+		Changes:    diffFields(event, restored),
+	})
+
+	resp := s.ConvertToEventResponse(restored)
+	publishRallyUpdate(ctx, s.pubsub, restored.RallyID, user.ID, model.RealtimeEventRestored, resp)
+
+	return resp, nil
+}
+
+// GetTrash retrieves the events and activities of a rally that are currently in the trash
+// (middleware ensures owner or editor role)
+func (s *EventService) GetTrash(ctx context.Context, rallyID string) (*model.TrashResponse, error) {
+	rallyObjID, err := primitive.ObjectIDFromHex(rallyID)
+	if err != nil {
+		return nil, errors.New("invalid rally ID")
+	}
+
+	events, err := s.eventRepo.GetDeletedEventsByRally(ctx, rallyObjID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted events: %w", err)
+	}
+
+	eventIDs, err := s.eventRepo.GetAllEventIDsByRally(ctx, rallyObjID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rally events: %w", err)
+	}
+
+	activities, err := s.activityRepo.GetDeletedActivitiesByEvents(ctx, eventIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted activities: %w", err)
+	}
+
+	eventItems := make([]model.EventResponse, len(events))
+	for i := range events {
+		eventItems[i] = *s.ConvertToEventResponse(&events[i])
+	}
+
+	activityItems := make([]model.ActivityResponse, len(activities))
+	for i := range activities {
+		activityItems[i] = *convertToActivityResponse(&activities[i])
+	}
 
-	return s.ConvertToEventResponse(updated), nil
+	return &model.TrashResponse{
+		Events:     eventItems,
+		Activities: activityItems,
+	}, nil
+}
+
+// ReorderEvents rewrites the visit order of every live event of a rally in a single transaction.
+// The request must list exactly the rally's current events; otherwise the client's view is stale
+// and nothing is written (middleware ensures owner or editor role).
+func (s *EventService) ReorderEvents(ctx context.Context, user *model.User, rallyID string, req *model.ReorderEventsRequest) (*model.EventListResponse, error) {
+	rallyObjID, err := primitive.ObjectIDFromHex(rallyID)
+	if err != nil {
+		return nil, errors.New("invalid rally ID")
+	}
+
+	orderedIDs, err := parseOrderedIDs(req.EventIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rally, err := s.rallyRepo.GetRallyByID(ctx, rallyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rally: %w", err)
+	}
+	if rally == nil {
+		return nil, errors.New("rally not found")
+	}
+	if isRallyReadOnly(rally) {
+		return nil, errors.New("rally is archived or completed and cannot be edited")
+	}
+
+	session, err := s.db.Client().StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	var previousIDs []primitive.ObjectID
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		current, err := s.eventRepo.GetEventsByRally(sessCtx, rallyObjID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rally events: %w", err)
+		}
+		currentIDs := make([]primitive.ObjectID, len(current))
+		for i := range current {
+			currentIDs[i] = current[i].ID
+		}
+		if !sameIDSet(orderedIDs, currentIDs) {
+			return nil, errors.New("stale order: events have changed")
+		}
+
+		matched, err := s.eventRepo.SetEventsOrder(sessCtx, rallyObjID, orderedIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reorder events: %w", err)
+		}
+		if matched != int64(len(orderedIDs)) {
+			return nil, errors.New("stale order: events have changed")
+		}
+
+		previousIDs = currentIDs
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    rallyObjID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionReorder,
+		TargetType: model.AuditTargetRally,
+		TargetID:   rallyObjID,
+		Changes: []model.FieldChange{{
+			Field:  "event_order",
+			Before: previousIDs,
+			After:  orderedIDs,
+		}},
+	})
+
+	list, err := s.GetEventsList(ctx, rallyID)
+	if err != nil {
+		return nil, err
+	}
+	publishRallyUpdate(ctx, s.pubsub, rallyObjID, user.ID, model.RealtimeEventsReordered, list)
+
+	return list, nil
+}
+
+// GetEventsList retrieves all events of a rally ordered by visit order (middleware ensures joined participant)
+func (s *EventService) GetEventsList(ctx context.Context, rallyID string) (*model.EventListResponse, error) {
+	rallyObjID, err := primitive.ObjectIDFromHex(rallyID)
+	if err != nil {
+		return nil, errors.New("invalid rally ID")
+	}
+
+	events, err := s.eventRepo.GetEventsByRally(ctx, rallyObjID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+
+	items := make([]model.EventResponse, len(events))
+	for i := range events {
+		items[i] = *s.ConvertToEventResponse(&events[i])
+	}
+
+	return &model.EventListResponse{
+		Events: items,
+		Total:  len(items),
+	}, nil
+}
+
+// GetItinerary retrieves the full itinerary of a rally: events ordered by visit order with their
+// activities ordered by activity order (middleware ensures joined participant)
+func (s *EventService) GetItinerary(ctx context.Context, rallyID string) (*model.ItineraryResponse, error) {
+	rallyObjID, err := primitive.ObjectIDFromHex(rallyID)
+	if err != nil {
+		return nil, errors.New("invalid rally ID")
+	}
+
+	results, err := s.eventRepo.GetItineraryByRally(ctx, rallyObjID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get itinerary: %w", err)
+	}
+
+	events := make([]model.ItineraryEventResponse, len(results))
+	for i := range results {
+		activities := make([]model.ActivityResponse, len(results[i].Activities))
+		for j := range results[i].Activities {
+			activities[j] = *convertToActivityResponse(&results[i].Activities[j])
+		}
+
+		events[i] = model.ItineraryEventResponse{
+			EventResponse: s.ConvertToEventResponse(&results[i].Event),
+			Activities:    activities,
+		}
+	}
+
+	return &model.ItineraryResponse{
+		RallyID: rallyID,
+		Events:  events,
+	}, nil
 }
 
 // ConvertToEventResponse converts an Event model to EventResponse
 func (s *EventService) ConvertToEventResponse(event *model.Event) *model.EventResponse {
+	return convertToEventResponse(event)
+}
+
+// convertToEventResponse converts an Event model to EventResponse.
+// Shared with AuditLogService, which returns reverted events.
+func convertToEventResponse(event *model.Event) *model.EventResponse {
 	return &model.EventResponse{
 		ID:            event.ID.Hex(),
 		RallyID:       event.RallyID.Hex(),
@@ -108,5 +515,7 @@ func (s *EventService) ConvertToEventResponse(event *model.Event) *model.EventRe
 		VisitOrder:    event.VisitOrder,
 		CreatedAt:     event.CreatedAt,
 		UpdatedAt:     event.UpdatedAt,
+		DeletedAt:     event.DeletedAt,
+		Version:       event.Version,
 	}
 }
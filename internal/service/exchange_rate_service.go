@@ -0,0 +1,437 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/realtime"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/repository"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// maxExchangeRates caps the size of an exchange rate table
+const maxExchangeRates = 500
+
+// exchangeRatePattern matches the decimal strings accepted as rates, e.g. "25400" or "0.0000394"
+var exchangeRatePattern = regexp.MustCompile(`^[0-9]{1,15}(\.[0-9]{1,12})?$`)
+
+type ExchangeRateService struct {
+	db        *mongo.Database
+	rateRepo  repository.ExchangeRateRepository
+	rallyRepo repository.RallyRepository
+	auditRepo repository.AuditLogRepository
+	pubsub    realtime.PubSub
+}
+
+func NewExchangeRateService(
+	db *mongo.Database,
+	rateRepo repository.ExchangeRateRepository,
+	rallyRepo repository.RallyRepository,
+	auditRepo repository.AuditLogRepository,
+	pubsub realtime.PubSub,
+) *ExchangeRateService {
+	return &ExchangeRateService{
+		db:        db,
+		rateRepo:  rateRepo,
+		rallyRepo: rallyRepo,
+		auditRepo: auditRepo,
+		pubsub:    pubsub,
+	}
+}
+
+// GetGlobalRates retrieves the global exchange rate table
+func (s *ExchangeRateService) GetGlobalRates(ctx context.Context) (*model.ExchangeRateListResponse, error) {
+	return s.getRates(ctx, nil)
+}
+
+// ReplaceGlobalRates uploads a new global exchange rate table, replacing the previous one
+// (middleware ensures admin)
+func (s *ExchangeRateService) ReplaceGlobalRates(ctx context.Context, user *model.User, req *model.ReplaceExchangeRatesRequest) (*model.ExchangeRateListResponse, error) {
+	if _, err := s.replaceRates(ctx, user, nil, req); err != nil {
+		return nil, err
+	}
+	return s.getRates(ctx, nil)
+}
+
+// SetGlobalRate adds or edits one rate of the global table (middleware ensures admin)
+func (s *ExchangeRateService) SetGlobalRate(ctx context.Context, user *model.User, from string, to string, req *model.SetExchangeRateRequest) (*model.ExchangeRateResponse, error) {
+	_, updated, err := s.setRate(ctx, user, nil, from, to, req)
+	if err != nil {
+		return nil, err
+	}
+	return convertToExchangeRateResponse(updated), nil
+}
+
+// DeleteGlobalRate removes the rate of a currency pair from the global table (middleware ensures admin)
+func (s *ExchangeRateService) DeleteGlobalRate(ctx context.Context, from string, to string) error {
+	_, err := s.deleteRate(ctx, nil, from, to)
+	return err
+}
+
+// GetRallyRates retrieves a rally's own exchange rate table (middleware ensures joined participant)
+func (s *ExchangeRateService) GetRallyRates(ctx context.Context, rallyID string) (*model.ExchangeRateListResponse, error) {
+	rallyObjID, err := primitive.ObjectIDFromHex(rallyID)
+	if err != nil {
+		return nil, errors.New("invalid rally ID")
+	}
+	return s.getRates(ctx, &rallyObjID)
+}
+
+// ReplaceRallyRates uploads a new exchange rate table for a rally, replacing the previous one
+// (middleware ensures owner or editor role)
+func (s *ExchangeRateService) ReplaceRallyRates(ctx context.Context, user *model.User, rallyID string, req *model.ReplaceExchangeRatesRequest) (*model.ExchangeRateListResponse, error) {
+	rally, err := getUnarchivedRally(ctx, s.rallyRepo, rallyID, "rally is archived and its exchange rates cannot be changed")
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := s.replaceRates(ctx, user, &rally.ID, req)
+	if err != nil {
+		return nil, err
+	}
+	after, err := s.getRates(ctx, &rally.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    rally.ID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionUpdate,
+		TargetType: model.AuditTargetExchangeRate,
+		TargetID:   rally.ID,
+		Changes:    diffFields(rateTableSnapshot(before), rateTableSnapshot(after.Rates)),
+	})
+	publishRallyUpdate(ctx, s.pubsub, rally.ID, user.ID, model.RealtimeExchangeRatesUpdated, after)
+
+	return after, nil
+}
+
+// SetRallyRate adds or edits one rate of a rally's table (middleware ensures owner or editor role)
+func (s *ExchangeRateService) SetRallyRate(ctx context.Context, user *model.User, rallyID string, from string, to string, req *model.SetExchangeRateRequest) (*model.ExchangeRateResponse, error) {
+	rally, err := getUnarchivedRally(ctx, s.rallyRepo, rallyID, "rally is archived and its exchange rates cannot be changed")
+	if err != nil {
+		return nil, err
+	}
+
+	existing, updated, err := s.setRate(ctx, user, &rally.ID, from, to, req)
+	if err != nil {
+		return nil, err
+	}
+
+	action := model.AuditActionUpdate
+	if existing == nil {
+		action = model.AuditActionCreate
+	}
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    rally.ID,
+		ActorID:    user.ID,
+		Action:     action,
+		TargetType: model.AuditTargetExchangeRate,
+		TargetID:   updated.ID,
+		Changes:    diffFields(existing, updated),
+	})
+	s.publishRallyRates(ctx, rally.ID, user.ID)
+
+	return convertToExchangeRateResponse(updated), nil
+}
+
+// DeleteRallyRate removes the rate of a currency pair from a rally's table (middleware ensures owner
+// or editor role)
+func (s *ExchangeRateService) DeleteRallyRate(ctx context.Context, user *model.User, rallyID string, from string, to string) error {
+	rally, err := getUnarchivedRally(ctx, s.rallyRepo, rallyID, "rally is archived and its exchange rates cannot be changed")
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.deleteRate(ctx, &rally.ID, from, to)
+	if err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    rally.ID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionDelete,
+		TargetType: model.AuditTargetExchangeRate,
+		TargetID:   deleted.ID,
+		Changes:    diffFields(deleted, nil),
+	})
+	s.publishRallyRates(ctx, rally.ID, user.ID)
+
+	return nil
+}
+
+// getRates retrieves a table as a response
+func (s *ExchangeRateService) getRates(ctx context.Context, rallyID *primitive.ObjectID) (*model.ExchangeRateListResponse, error) {
+	rates, err := s.rateRepo.GetExchangeRates(ctx, rallyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange rates: %w", err)
+	}
+
+	responses := make([]model.ExchangeRateResponse, len(rates))
+	for i := range rates {
+		responses[i] = *convertToExchangeRateResponse(&rates[i])
+	}
+	return &model.ExchangeRateListResponse{Rates: responses}, nil
+}
+
+// replaceRates validates an uploaded table and swaps it in atomically, returning the previous table
+func (s *ExchangeRateService) replaceRates(ctx context.Context, user *model.User, rallyID *primitive.ObjectID, req *model.ReplaceExchangeRatesRequest) ([]model.ExchangeRateResponse, error) {
+	if len(req.Rates) > maxExchangeRates {
+		return nil, errors.New("too many exchange rates")
+	}
+
+	rates := make([]model.ExchangeRate, len(req.Rates))
+	seen := make(map[[2]string]bool, len(req.Rates))
+	for i, input := range req.Rates {
+		from, to, err := parseCurrencyPair(input.From, input.To)
+		if err != nil {
+			return nil, err
+		}
+		// A rate also converts the other way, so a pair may only appear once in either direction
+		if seen[[2]string{from, to}] || seen[[2]string{to, from}] {
+			return nil, errors.New("duplicate exchange rate for a currency pair")
+		}
+		seen[[2]string{from, to}] = true
+
+		rate, err := normalizeExchangeRate(input.Rate)
+		if err != nil {
+			return nil, err
+		}
+
+		rates[i] = model.ExchangeRate{
+			ID:        primitive.NewObjectID(),
+			RallyID:   rallyID,
+			From:      from,
+			To:        to,
+			Rate:      rate,
+			UpdatedBy: user.ID,
+		}
+	}
+
+	before, err := s.getRates(ctx, rallyID)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.db.Client().StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		now := time.Now()
+		for i := range rates {
+			rates[i].UpdatedAt = now
+		}
+		if err := s.rateRepo.ReplaceExchangeRates(sessCtx, rallyID, rates); err != nil {
+			return nil, fmt.Errorf("failed to replace exchange rates: %w", err)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return before.Rates, nil
+}
+
+// setRate validates and stores one rate, returning the rate previously stored for the pair (in
+// either direction, nil if none) and the new one
+func (s *ExchangeRateService) setRate(ctx context.Context, user *model.User, rallyID *primitive.ObjectID, from string, to string, req *model.SetExchangeRateRequest) (*model.ExchangeRate, *model.ExchangeRate, error) {
+	from, to, err := parseCurrencyPair(from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	rate, err := normalizeExchangeRate(req.Rate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	existing, err := s.findRate(ctx, rallyID, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updated, err := s.rateRepo.UpsertExchangeRate(ctx, &model.ExchangeRate{
+		RallyID:   rallyID,
+		From:      from,
+		To:        to,
+		Rate:      rate,
+		UpdatedBy: user.ID,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to save exchange rate: %w", err)
+	}
+
+	return existing, updated, nil
+}
+
+// deleteRate removes the rate of a pair from a table, returning the removed rate
+func (s *ExchangeRateService) deleteRate(ctx context.Context, rallyID *primitive.ObjectID, from string, to string) (*model.ExchangeRate, error) {
+	from, to, err := parseCurrencyPair(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.findRate(ctx, rallyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, errors.New("exchange rate not found")
+	}
+
+	deleted, err := s.rateRepo.DeleteExchangeRate(ctx, rallyID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete exchange rate: %w", err)
+	}
+	if !deleted {
+		return nil, errors.New("exchange rate not found")
+	}
+
+	return existing, nil
+}
+
+// findRate returns the rate stored in a table for a pair in either direction, or nil
+func (s *ExchangeRateService) findRate(ctx context.Context, rallyID *primitive.ObjectID, from string, to string) (*model.ExchangeRate, error) {
+	rates, err := s.rateRepo.GetExchangeRates(ctx, rallyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange rates: %w", err)
+	}
+
+	for i := range rates {
+		if (rates[i].From == from && rates[i].To == to) || (rates[i].From == to && rates[i].To == from) {
+			return &rates[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// publishRallyRates pushes a rally's current exchange rate table to its subscribers
+func (s *ExchangeRateService) publishRallyRates(ctx context.Context, rallyID primitive.ObjectID, actorID primitive.ObjectID) {
+	rates, err := s.getRates(ctx, &rallyID)
+	if err != nil {
+		return
+	}
+	publishRallyUpdate(ctx, s.pubsub, rallyID, actorID, model.RealtimeExchangeRatesUpdated, rates)
+}
+
+// rateTableSnapshot flattens a table into pair -> rate for audit diffs of whole-table uploads
+func rateTableSnapshot(rates []model.ExchangeRateResponse) interface{} {
+	table := make(map[string]string, len(rates))
+	for _, rate := range rates {
+		table[rate.From+"/"+rate.To] = rate.Rate
+	}
+	return &struct {
+		Rates map[string]string `bson:"rates"`
+	}{Rates: table}
+}
+
+// parseCurrencyPair normalizes the two currencies of a rate
+func parseCurrencyPair(from string, to string) (string, string, error) {
+	fromCode, ok := utils.NormalizeCurrency(from)
+	if !ok {
+		return "", "", errors.New("invalid currency")
+	}
+	toCode, ok := utils.NormalizeCurrency(to)
+	if !ok {
+		return "", "", errors.New("invalid currency")
+	}
+	if fromCode == toCode {
+		return "", "", errors.New("exchange rate must be between two different currencies")
+	}
+	return fromCode, toCode, nil
+}
+
+// normalizeExchangeRate checks a rate is a positive decimal string and returns it in canonical form
+func normalizeExchangeRate(rate string) (string, error) {
+	if !exchangeRatePattern.MatchString(rate) {
+		return "", errors.New("invalid exchange rate")
+	}
+	value, ok := new(big.Rat).SetString(rate)
+	if !ok || value.Sign() <= 0 {
+		return "", errors.New("invalid exchange rate")
+	}
+	return value.FloatString(decimalPlaces(rate)), nil
+}
+
+// decimalPlaces counts the digits after the decimal point of a decimal string, ignoring trailing zeros
+func decimalPlaces(rate string) int {
+	for i := range rate {
+		if rate[i] == '.' {
+			places := len(rate) - i - 1
+			for places > 0 && rate[i+places] == '0' {
+				places--
+			}
+			return places
+		}
+	}
+	return 0
+}
+
+// exchangeRates resolves conversions for a rally: the rally's own table first, then the global one
+type exchangeRates struct {
+	rally  []model.ExchangeRate
+	global []model.ExchangeRate
+}
+
+// loadExchangeRates loads the tables used to convert a rally's amounts
+func loadExchangeRates(ctx context.Context, rateRepo repository.ExchangeRateRepository, rallyID primitive.ObjectID) (*exchangeRates, error) {
+	rallyRates, err := rateRepo.GetExchangeRates(ctx, &rallyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange rates: %w", err)
+	}
+	globalRates, err := rateRepo.GetExchangeRates(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange rates: %w", err)
+	}
+	return &exchangeRates{rally: rallyRates, global: globalRates}, nil
+}
+
+// lookup finds the rate converting from into to, inverting a rate stored the other way round. It also
+// returns the stored rate that was used, or nil if neither table has the pair.
+func (r *exchangeRates) lookup(from string, to string) (*big.Rat, *model.ExchangeRate) {
+	for _, table := range [][]model.ExchangeRate{r.rally, r.global} {
+		for i := range table {
+			rate := &table[i]
+			value, ok := new(big.Rat).SetString(rate.Rate)
+			if !ok || value.Sign() <= 0 {
+				continue
+			}
+			switch {
+			case rate.From == from && rate.To == to:
+				return value, rate
+			case rate.From == to && rate.To == from:
+				return value.Inv(value), rate
+			}
+		}
+	}
+	return nil, nil
+}
+
+// convertToExchangeRateResponse converts an ExchangeRate model to ExchangeRateResponse
+func convertToExchangeRateResponse(rate *model.ExchangeRate) *model.ExchangeRateResponse {
+	scope := model.ExchangeRateScopeGlobal
+	if rate.RallyID != nil {
+		scope = model.ExchangeRateScopeRally
+	}
+
+	return &model.ExchangeRateResponse{
+		From:      rate.From,
+		To:        rate.To,
+		Rate:      rate.Rate,
+		Scope:     scope,
+		UpdatedBy: rate.UpdatedBy.Hex(),
+		UpdatedAt: rate.UpdatedAt,
+	}
+}
@@ -0,0 +1,620 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/realtime"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/repository"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxExpenseDescriptionLength is the longest expense description accepted, in characters
+const maxExpenseDescriptionLength = 200
+
+type ExpenseService struct {
+	expenseRepo     repository.ExpenseRepository
+	rallyRepo       repository.RallyRepository
+	eventRepo       repository.EventRepository
+	activityRepo    repository.ActivityRepository
+	participantRepo repository.RallyParticipantRepository
+	rateRepo        repository.ExchangeRateRepository
+	auditRepo       repository.AuditLogRepository
+	pubsub          realtime.PubSub
+}
+
+func NewExpenseService(
+	expenseRepo repository.ExpenseRepository,
+	rallyRepo repository.RallyRepository,
+	eventRepo repository.EventRepository,
+	activityRepo repository.ActivityRepository,
+	participantRepo repository.RallyParticipantRepository,
+	rateRepo repository.ExchangeRateRepository,
+	auditRepo repository.AuditLogRepository,
+	pubsub realtime.PubSub,
+) *ExpenseService {
+	return &ExpenseService{
+		expenseRepo:     expenseRepo,
+		rallyRepo:       rallyRepo,
+		eventRepo:       eventRepo,
+		activityRepo:    activityRepo,
+		participantRepo: participantRepo,
+		rateRepo:        rateRepo,
+		auditRepo:       auditRepo,
+		pubsub:          pubsub,
+	}
+}
+
+// CreateExpense records money paid by a participant on behalf of others (middleware ensures joined participant).
+// The payer defaults to the caller, and without splits the expense is shared equally by every joined participant.
+func (s *ExpenseService) CreateExpense(ctx context.Context, user *model.User, rallyID string, req *model.CreateExpenseRequest) (*model.ExpenseResponse, error) {
+	rally, err := getUnarchivedRally(ctx, s.rallyRepo, rallyID, "rally is archived and its expenses cannot be changed")
+	if err != nil {
+		return nil, err
+	}
+
+	joined, err := s.joinedUsers(ctx, rally.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	expense := &model.Expense{
+		ID:        primitive.NewObjectID(),
+		RallyID:   rally.ID,
+		PaidBy:    user.ID,
+		SplitMode: req.SplitMode,
+		SpentAt:   time.Now(),
+		CreatedBy: user.ID,
+	}
+	if req.SpentAt != nil {
+		expense.SpentAt = *req.SpentAt
+	}
+	if expense.SplitMode == "" {
+		expense.SplitMode = model.ExpenseSplitEqual
+	}
+	expense.Category = model.ExpenseCategoryOther
+	if req.Category != "" {
+		if !isValidExpenseCategory(req.Category) {
+			return nil, errors.New("invalid expense category")
+		}
+		expense.Category = req.Category
+	}
+
+	if err := setExpenseDetails(expense, req.Description, req.Amount, req.Currency); err != nil {
+		return nil, err
+	}
+	if req.PaidBy != "" {
+		if expense.PaidBy, err = joinedUserID(req.PaidBy, joined); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.setExpenseLinks(ctx, expense, req.EventID, req.ActivityID); err != nil {
+		return nil, err
+	}
+
+	splits, err := parseExpenseSplits(req.Splits, joined)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Splits) == 0 {
+		splits = make([]model.ExpenseSplit, 0, len(joined))
+		for userID := range joined {
+			splits = append(splits, model.ExpenseSplit{UserID: userID})
+		}
+		// Map order is random, so fix the order in which rounding leftovers are handed out
+		sort.Slice(splits, func(a, b int) bool { return splits[a].UserID.Hex() < splits[b].UserID.Hex() })
+	}
+	if expense.Splits, err = splitExpense(expense.Amount, expense.SplitMode, splits); err != nil {
+		return nil, err
+	}
+
+	if err := s.expenseRepo.CreateExpense(ctx, expense); err != nil {
+		return nil, fmt.Errorf("failed to create expense: %w", err)
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    rally.ID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionCreate,
+		TargetType: model.AuditTargetExpense,
+		TargetID:   expense.ID,
+		Changes:    diffFields(nil, expense),
+	})
+
+	resp := convertToExpenseResponse(expense)
+	publishRallyUpdate(ctx, s.pubsub, rally.ID, user.ID, model.RealtimeExpenseCreated, resp)
+
+	return resp, nil
+}
+
+// GetExpense retrieves a single expense of a rally (middleware ensures joined participant)
+func (s *ExpenseService) GetExpense(ctx context.Context, rallyID string, expenseID string) (*model.ExpenseResponse, error) {
+	expense, err := s.getExpense(ctx, rallyID, expenseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToExpenseResponse(expense), nil
+}
+
+// GetExpensesList retrieves a paginated list of a rally's expenses, most recently spent first
+// (middleware ensures joined participant)
+func (s *ExpenseService) GetExpensesList(ctx context.Context, rallyID string, page, pageSize int) (*model.ExpenseListResponse, error) {
+	rallyObjID, err := primitive.ObjectIDFromHex(rallyID)
+	if err != nil {
+		return nil, errors.New("invalid rally ID")
+	}
+
+	expenses, total, err := s.expenseRepo.GetExpensesByRally(ctx, rallyObjID, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expenses: %w", err)
+	}
+
+	responses := make([]model.ExpenseResponse, len(expenses))
+	for i := range expenses {
+		responses[i] = *convertToExpenseResponse(&expenses[i])
+	}
+
+	totalPages := utils.CalcTotalPages(total, pageSize)
+
+	return &model.ExpenseListResponse{
+		Expenses:   responses,
+		Total:      int(total),
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+		Pagination: model.PaginationMetadata{
+			HasNextPage:     page < totalPages,
+			HasPreviousPage: page > 1,
+		},
+	}, nil
+}
+
+// UpdateExpense changes an expense. The participant who recorded or paid it may edit it, as may owners
+// and editors (middleware ensures joined participant). Splits are recomputed from the stored shares or
+// amounts when only the amount changes; exact splits then have to be resent.
+func (s *ExpenseService) UpdateExpense(ctx context.Context, user *model.User, callerParticipant *model.RallyParticipant, rallyID string, expenseID string, req *model.UpdateExpenseRequest) (*model.ExpenseResponse, error) {
+	existing, err := s.getExpense(ctx, rallyID, expenseID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageExpense(existing, user, callerParticipant) {
+		return nil, errors.New("unauthorized: insufficient permissions")
+	}
+	if _, err := getUnarchivedRally(ctx, s.rallyRepo, rallyID, "rally is archived and its expenses cannot be changed"); err != nil {
+		return nil, err
+	}
+
+	joined, err := s.joinedUsers(ctx, existing.RallyID)
+	if err != nil {
+		return nil, err
+	}
+
+	expense := *existing
+	description, amount, currency := expense.Description, expense.Amount, expense.Currency
+	if req.Description != nil {
+		description = *req.Description
+	}
+	if req.Amount != nil {
+		amount = *req.Amount
+	}
+	if req.Currency != nil {
+		currency = *req.Currency
+	}
+	if err := setExpenseDetails(&expense, description, amount, currency); err != nil {
+		return nil, err
+	}
+	if req.PaidBy != nil {
+		if expense.PaidBy, err = joinedUserID(*req.PaidBy, joined); err != nil {
+			return nil, err
+		}
+	}
+	if req.SpentAt != nil {
+		expense.SpentAt = *req.SpentAt
+	}
+	if req.Category != nil {
+		if !isValidExpenseCategory(*req.Category) {
+			return nil, errors.New("invalid expense category")
+		}
+		expense.Category = *req.Category
+	}
+
+	eventID, activityID := "", ""
+	if expense.EventID != nil {
+		eventID = expense.EventID.Hex()
+	}
+	if expense.ActivityID != nil {
+		activityID = expense.ActivityID.Hex()
+	}
+	if req.EventID != nil {
+		eventID = *req.EventID
+	}
+	if req.ActivityID != nil {
+		activityID = *req.ActivityID
+	}
+	if req.EventID != nil || req.ActivityID != nil {
+		if err := s.setExpenseLinks(ctx, &expense, eventID, activityID); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.SplitMode != nil {
+		expense.SplitMode = *req.SplitMode
+	}
+	splits := expense.Splits
+	if req.Splits != nil {
+		if splits, err = parseExpenseSplits(*req.Splits, joined); err != nil {
+			return nil, err
+		}
+	}
+	if expense.Splits, err = splitExpense(expense.Amount, expense.SplitMode, splits); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.expenseRepo.UpdateExpense(ctx, &expense)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update expense: %w", err)
+	}
+	if updated == nil {
+		return nil, errors.New("expense not found")
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    updated.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionUpdate,
+		TargetType: model.AuditTargetExpense,
+		TargetID:   updated.ID,
+		Changes:    diffFields(existing, updated),
+	})
+
+	resp := convertToExpenseResponse(updated)
+	publishRallyUpdate(ctx, s.pubsub, updated.RallyID, user.ID, model.RealtimeExpenseUpdated, resp)
+
+	return resp, nil
+}
+
+// DeleteExpense permanently removes an expense. The participant who recorded or paid it may delete it,
+// as may owners and editors (middleware ensures joined participant).
+func (s *ExpenseService) DeleteExpense(ctx context.Context, user *model.User, callerParticipant *model.RallyParticipant, rallyID string, expenseID string) error {
+	expense, err := s.getExpense(ctx, rallyID, expenseID)
+	if err != nil {
+		return err
+	}
+	if !canManageExpense(expense, user, callerParticipant) {
+		return errors.New("unauthorized: insufficient permissions")
+	}
+	if _, err := getUnarchivedRally(ctx, s.rallyRepo, rallyID, "rally is archived and its expenses cannot be changed"); err != nil {
+		return err
+	}
+
+	if err := s.expenseRepo.DeleteExpense(ctx, expense.ID); err != nil {
+		if err.Error() == "expense not found" {
+			return err
+		}
+		return fmt.Errorf("failed to delete expense: %w", err)
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    expense.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionDelete,
+		TargetType: model.AuditTargetExpense,
+		TargetID:   expense.ID,
+		Changes:    diffFields(expense, nil),
+	})
+
+	publishRallyUpdate(ctx, s.pubsub, expense.RallyID, user.ID, model.RealtimeExpenseDeleted, &model.RealtimeDeletedData{ID: expense.ID.Hex()})
+
+	return nil
+}
+
+// GetBalances computes what every participant paid and owes across a rally's expenses, and the
+// transfers that settle the group up (middleware ensures joined participant). When the rally has a
+// base currency, expenses in other currencies are converted into it using the rally's exchange rates,
+// falling back to the global ones; a currency without any rate is balanced on its own and reported.
+// Without a base currency, every currency is balanced separately.
+func (s *ExpenseService) GetBalances(ctx context.Context, rallyID string) (*model.RallyBalancesResponse, error) {
+	if _, err := primitive.ObjectIDFromHex(rallyID); err != nil {
+		return nil, errors.New("invalid rally ID")
+	}
+
+	rally, err := s.rallyRepo.GetRallyByID(ctx, rallyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rally: %w", err)
+	}
+	if rally == nil {
+		return nil, errors.New("rally not found")
+	}
+
+	expenses, err := s.expenseRepo.GetAllExpensesByRally(ctx, rally.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expenses: %w", err)
+	}
+
+	var rates *exchangeRates
+	if rally.BaseCurrency != "" {
+		if rates, err = loadExchangeRates(ctx, s.rateRepo, rally.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	type ledger struct {
+		paid map[primitive.ObjectID]int64
+		owed map[primitive.ObjectID]int64
+	}
+	ledgers := make(map[string]*ledger)
+	appliedRates := []model.ExchangeRateResponse{}
+	applied := make(map[primitive.ObjectID]bool)
+	missing := make(map[string]bool)
+	for _, expense := range expenses {
+		currency, amount := expense.Currency, expense.Amount
+		owed := make([]int64, len(expense.Splits))
+		for i, split := range expense.Splits {
+			owed[i] = split.Amount
+		}
+
+		if rates != nil && currency != rally.BaseCurrency {
+			if rate, stored := rates.lookup(currency, rally.BaseCurrency); rate != nil {
+				// Convert the expense as a whole, then spread the rounded total over the splits in
+				// proportion to what each owed, so converted splits still add up to what was paid
+				if amount, err = convertAmount(expense.Amount, currency, rally.BaseCurrency, rate); err != nil {
+					return nil, err
+				}
+				owed = allocateProportionally(amount, owed)
+				currency = rally.BaseCurrency
+
+				if !applied[stored.ID] {
+					applied[stored.ID] = true
+					appliedRates = append(appliedRates, *convertToExchangeRateResponse(stored))
+				}
+			} else {
+				missing[currency] = true
+			}
+		}
+
+		l := ledgers[currency]
+		if l == nil {
+			l = &ledger{paid: make(map[primitive.ObjectID]int64), owed: make(map[primitive.ObjectID]int64)}
+			ledgers[currency] = l
+		}
+		l.paid[expense.PaidBy] += amount
+		for i, split := range expense.Splits {
+			l.owed[split.UserID] += owed[i]
+		}
+	}
+
+	// The base currency comes first, followed by any currency that could not be converted into it
+	currencies := make([]string, 0, len(ledgers))
+	for currency := range ledgers {
+		currencies = append(currencies, currency)
+	}
+	sort.Slice(currencies, func(a, b int) bool {
+		if (currencies[a] == rally.BaseCurrency) != (currencies[b] == rally.BaseCurrency) {
+			return currencies[a] == rally.BaseCurrency
+		}
+		return currencies[a] < currencies[b]
+	})
+
+	response := &model.RallyBalancesResponse{
+		RallyID:      rally.ID.Hex(),
+		BaseCurrency: rally.BaseCurrency,
+		Currencies:   make([]model.CurrencyBalances, 0, len(currencies)),
+		Rates:        appliedRates,
+		MissingRates: make([]string, 0, len(missing)),
+	}
+	for currency := range missing {
+		response.MissingRates = append(response.MissingRates, currency)
+	}
+	sort.Strings(response.MissingRates)
+
+	for _, currency := range currencies {
+		l := ledgers[currency]
+
+		nets := make(map[primitive.ObjectID]int64)
+		for userID, paid := range l.paid {
+			nets[userID] += paid
+		}
+		for userID, owed := range l.owed {
+			nets[userID] -= owed
+		}
+
+		balances := make([]model.ParticipantBalance, 0, len(nets))
+		for userID, net := range nets {
+			balances = append(balances, model.ParticipantBalance{
+				UserID: userID.Hex(),
+				Paid:   l.paid[userID],
+				Owed:   l.owed[userID],
+				Net:    net,
+			})
+		}
+		sort.Slice(balances, func(a, b int) bool {
+			if balances[a].Net != balances[b].Net {
+				return balances[a].Net > balances[b].Net
+			}
+			return balances[a].UserID < balances[b].UserID
+		})
+
+		response.Currencies = append(response.Currencies, model.CurrencyBalances{
+			Currency:  currency,
+			Balances:  balances,
+			Transfers: settleUp(nets),
+		})
+	}
+
+	return response, nil
+}
+
+// getExpense loads an expense and checks it belongs to the rally
+func (s *ExpenseService) getExpense(ctx context.Context, rallyID string, expenseID string) (*model.Expense, error) {
+	expense, err := s.expenseRepo.GetExpenseByID(ctx, expenseID)
+	if err != nil {
+		return nil, errors.New("expense not found")
+	}
+	if expense == nil || expense.RallyID.Hex() != rallyID {
+		return nil, errors.New("expense not found")
+	}
+	return expense, nil
+}
+
+// joinedUsers returns the set of users currently joined to a rally, the only users allowed in expenses
+func (s *ExpenseService) joinedUsers(ctx context.Context, rallyID primitive.ObjectID) (map[primitive.ObjectID]bool, error) {
+	userIDs, err := s.participantRepo.GetJoinedUserIDs(ctx, rallyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rally participants: %w", err)
+	}
+
+	joined := make(map[primitive.ObjectID]bool, len(userIDs))
+	for _, userID := range userIDs {
+		joined[userID] = true
+	}
+	return joined, nil
+}
+
+// setExpenseLinks validates and sets the optional event and activity an expense is attached to. Both
+// must be live and belong to the expense's rally, and an activity must belong to the given event; an
+// activity given alone links its event too.
+func (s *ExpenseService) setExpenseLinks(ctx context.Context, expense *model.Expense, eventID string, activityID string) error {
+	expense.EventID = nil
+	expense.ActivityID = nil
+
+	if eventID != "" {
+		event, err := s.eventRepo.GetEventByID(ctx, eventID)
+		if err != nil || event == nil || event.RallyID != expense.RallyID {
+			return errors.New("event not found")
+		}
+		expense.EventID = &event.ID
+	}
+
+	if activityID != "" {
+		activity, err := s.activityRepo.GetActivityByID(ctx, activityID)
+		if err != nil || activity == nil {
+			return errors.New("activity not found")
+		}
+		if expense.EventID != nil {
+			if activity.EventID != *expense.EventID {
+				return errors.New("activity does not belong to the event")
+			}
+		} else {
+			event, err := s.eventRepo.GetEventByID(ctx, activity.EventID.Hex())
+			if err != nil || event == nil || event.RallyID != expense.RallyID {
+				return errors.New("activity not found")
+			}
+			// Spend on an activity counts towards its event, e.g. for event budgets
+			expense.EventID = &event.ID
+		}
+		expense.ActivityID = &activity.ID
+	}
+
+	return nil
+}
+
+// setExpenseDetails validates and sets the description, amount and currency of an expense
+func setExpenseDetails(expense *model.Expense, description string, amount int64, currency string) error {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return errors.New("expense description is required")
+	}
+	if len([]rune(description)) > maxExpenseDescriptionLength {
+		return errors.New("expense description is too long")
+	}
+	if amount <= 0 || amount > maxExpenseAmount {
+		return errors.New("expense amount must be positive")
+	}
+	code, ok := utils.NormalizeCurrency(currency)
+	if !ok {
+		return errors.New("invalid currency")
+	}
+
+	expense.Description = description
+	expense.Amount = amount
+	expense.Currency = code
+	return nil
+}
+
+// parseExpenseSplits converts client splits into model splits, checking every user is a distinct
+// joined participant of the rally
+func parseExpenseSplits(requests []model.ExpenseSplitRequest, joined map[primitive.ObjectID]bool) ([]model.ExpenseSplit, error) {
+	splits := make([]model.ExpenseSplit, len(requests))
+	seen := make(map[primitive.ObjectID]bool, len(requests))
+	for i, req := range requests {
+		userID, err := joinedUserID(req.UserID, joined)
+		if err != nil {
+			return nil, err
+		}
+		if seen[userID] {
+			return nil, errors.New("a participant can only appear once in the splits")
+		}
+		seen[userID] = true
+
+		splits[i] = model.ExpenseSplit{UserID: userID, Shares: req.Shares, Amount: req.Amount}
+	}
+	return splits, nil
+}
+
+// isValidExpenseCategory reports whether category is one of the known expense categories
+func isValidExpenseCategory(category model.ExpenseCategory) bool {
+	for _, known := range model.ExpenseCategories {
+		if category == known {
+			return true
+		}
+	}
+	return false
+}
+
+// joinedUserID parses a user ID and checks the user is a joined participant of the rally
+func joinedUserID(userID string, joined map[primitive.ObjectID]bool) (primitive.ObjectID, error) {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil || !joined[objID] {
+		return primitive.NilObjectID, errors.New("only joined participants can be part of an expense")
+	}
+	return objID, nil
+}
+
+// canManageExpense reports whether a participant may edit or delete an expense: whoever recorded or
+// paid it, or an owner or editor of the rally
+func canManageExpense(expense *model.Expense, user *model.User, participant *model.RallyParticipant) bool {
+	return expense.CreatedBy == user.ID || expense.PaidBy == user.ID ||
+		participant.Role == model.ParticipantRoleOwner || participant.Role == model.ParticipantRoleEditor
+}
+
+// convertToExpenseResponse converts an Expense model to ExpenseResponse
+func convertToExpenseResponse(expense *model.Expense) *model.ExpenseResponse {
+	splits := make([]model.ExpenseSplitResponse, len(expense.Splits))
+	for i, split := range expense.Splits {
+		splits[i] = model.ExpenseSplitResponse{
+			UserID: split.UserID.Hex(),
+			Shares: split.Shares,
+			Amount: split.Amount,
+		}
+	}
+
+	resp := &model.ExpenseResponse{
+		ID:          expense.ID.Hex(),
+		RallyID:     expense.RallyID.Hex(),
+		Description: expense.Description,
+		Amount:      expense.Amount,
+		Currency:    expense.Currency,
+		Category:    expense.Category,
+		PaidBy:      expense.PaidBy.Hex(),
+		SplitMode:   expense.SplitMode,
+		Splits:      splits,
+		SpentAt:     expense.SpentAt,
+		CreatedBy:   expense.CreatedBy.Hex(),
+		CreatedAt:   expense.CreatedAt,
+		UpdatedAt:   expense.UpdatedAt,
+	}
+	if expense.EventID != nil {
+		resp.EventID = expense.EventID.Hex()
+	}
+	if expense.ActivityID != nil {
+		resp.ActivityID = expense.ActivityID.Hex()
+	}
+	return resp
+}
@@ -13,16 +13,18 @@ import (
 )
 
 type FollowService struct {
-	firebaseAuth *auth.Client
-	followRepo   repository.FollowRepository
-	userRepo     repository.UserRepository
+	firebaseAuth  *auth.Client
+	followRepo    repository.FollowRepository
+	userRepo      repository.UserRepository
+	notifications *NotificationService
 }
 
-func NewFollowService(firebaseAuth *auth.Client, followRepo repository.FollowRepository, userRepo repository.UserRepository) *FollowService {
+func NewFollowService(firebaseAuth *auth.Client, followRepo repository.FollowRepository, userRepo repository.UserRepository, notifications *NotificationService) *FollowService {
 	return &FollowService{
-		firebaseAuth: firebaseAuth,
-		followRepo:   followRepo,
-		userRepo:     userRepo,
+		firebaseAuth:  firebaseAuth,
+		followRepo:    followRepo,
+		userRepo:      userRepo,
+		notifications: notifications,
 	}
 }
 
@@ -83,6 +85,12 @@ func (s *FollowService) FollowUser(ctx context.Context, idToken string, targetUs
 		return nil, fmt.Errorf("failed to increment following count: %w", err)
 	}
 
+	s.notifications.Notify(ctx, &model.Notification{
+		UserID:  targetObjID,
+		Type:    model.NotificationTypeFollow,
+		ActorID: &currentUser.ID,
+	})
+
 	return &model.FollowResponse{
 		Success:     true,
 		Message:     "Successfully followed user",
@@ -57,3 +57,41 @@ func validateRallyAccess(ctx context.Context, participantRepo repository.RallyPa
 
 	return errors.New("unauthorized: insufficient permissions")
 }
+
+// parseOrderedIDs converts a client-supplied ordered list of hex IDs into ObjectIDs, rejecting
+// malformed and duplicate entries.
+func parseOrderedIDs(ids []string) ([]primitive.ObjectID, error) {
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	parsed := make([]primitive.ObjectID, len(ids))
+	for i, id := range ids {
+		objID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, errors.New("invalid order: malformed ID")
+		}
+		if seen[objID] {
+			return nil, errors.New("invalid order: duplicate ID")
+		}
+		seen[objID] = true
+		parsed[i] = objID
+	}
+	return parsed, nil
+}
+
+// sameIDSet reports whether the ordered list contains exactly the current IDs. The ordered list
+// is expected to be free of duplicates (see parseOrderedIDs).
+func sameIDSet(ordered []primitive.ObjectID, current []primitive.ObjectID) bool {
+	if len(ordered) != len(current) {
+		return false
+	}
+
+	currentSet := make(map[primitive.ObjectID]bool, len(current))
+	for _, id := range current {
+		currentSet[id] = true
+	}
+	for _, id := range ordered {
+		if !currentSet[id] {
+			return false
+		}
+	}
+	return true
+}
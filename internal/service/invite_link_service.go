@@ -4,40 +4,64 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"net/url"
+	"strings"
 	"time"
 
 	"firebase.google.com/go/v4/auth"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/realtime"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/repository"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/utils"
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type InviteLinkService struct {
+	db              *mongo.Database
 	firebaseAuth    *auth.Client
 	inviteLinkRepo  repository.InviteLinkRepository
+	redemptionRepo  repository.InviteLinkRedemptionRepository
 	participantRepo repository.RallyParticipantRepository
 	rallyRepo       repository.RallyRepository
 	userRepo        repository.UserRepository
 	eventRepo       repository.EventRepository
+	auditRepo       repository.AuditLogRepository
+	notifications   *NotificationService
+	pubsub          realtime.PubSub
+	linkBaseURL     string
 }
 
 // NewInviteLinkService initializes a new InviteLinkService
 func NewInviteLinkService(
+	db *mongo.Database,
 	firebaseAuth *auth.Client,
 	inviteLinkRepo repository.InviteLinkRepository,
+	redemptionRepo repository.InviteLinkRedemptionRepository,
 	participantRepo repository.RallyParticipantRepository,
 	rallyRepo repository.RallyRepository,
 	userRepo repository.UserRepository,
 	eventRepo repository.EventRepository,
+	auditRepo repository.AuditLogRepository,
+	notifications *NotificationService,
+	pubsub realtime.PubSub,
+	linkBaseURL string,
 ) *InviteLinkService {
 	return &InviteLinkService{
+		db:              db,
 		firebaseAuth:    firebaseAuth,
 		inviteLinkRepo:  inviteLinkRepo,
+		redemptionRepo:  redemptionRepo,
 		participantRepo: participantRepo,
 		rallyRepo:       rallyRepo,
 		userRepo:        userRepo,
 		eventRepo:       eventRepo,
+		auditRepo:       auditRepo,
+		notifications:   notifications,
+		pubsub:          pubsub,
+		linkBaseURL:     linkBaseURL,
 	}
 }
 
@@ -90,12 +114,23 @@ func (s *InviteLinkService) CreateInviteLink(ctx context.Context, user *model.Us
 		CurrentUses: 0,
 		IsActive:    true,
 		CreatedAt:   time.Now(),
+
+		RequiresApproval: req.RequiresApproval,
 	}
 
 	if err := s.inviteLinkRepo.CreateInviteLink(ctx, link); err != nil {
 		return nil, fmt.Errorf("failed to create invite link: %w", err)
 	}
 
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    rallyObjID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionCreate,
+		TargetType: model.AuditTargetInviteLink,
+		TargetID:   link.ID,
+		Changes:    diffFields(nil, link),
+	})
+
 	return convertToInviteLinkResponse(link), nil
 }
 
@@ -120,16 +155,108 @@ func (s *InviteLinkService) GetActiveInviteLinks(ctx context.Context, rallyID st
 	return responses, nil
 }
 
-// DeactivateInviteLink revokes an existing invite link (middleware ensures owner/editor)
-func (s *InviteLinkService) DeactivateInviteLink(ctx context.Context, rallyID string, token string) error {
+// UpdateInviteLink edits an existing invite link in place so its token (and any printed QR code) keeps
+// working: the expiry can be extended, MaxUses raised and the granted role downgraded. A link that was
+// auto-deactivated for expiry or max uses is reactivated once the new limits make it usable again
+// (middleware ensures owner/editor)
+func (s *InviteLinkService) UpdateInviteLink(ctx context.Context, user *model.User, callerParticipant *model.RallyParticipant, rallyID string, token string, req *model.UpdateInviteLinkRequest) (*model.InviteLinkResponse, error) {
+	link, err := s.inviteLinkRepo.GetInviteLinkByToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link: %w", err)
+	}
+	if link == nil || link.RallyID.Hex() != rallyID {
+		return nil, errors.New("link not found")
+	}
+
+	// Only owners can edit owner/editor links, same as creating them
+	if link.RoleToGrant == model.ParticipantRoleOwner || link.RoleToGrant == model.ParticipantRoleEditor {
+		if callerParticipant.Role != model.ParticipantRoleOwner {
+			return nil, errors.New("only owners can edit links for owner/editor roles")
+		}
+	}
+
+	// Revoked links stay revoked
+	if isInviteLinkRevoked(link) {
+		return nil, errors.New("revoked links cannot be edited")
+	}
+
+	updated := *link
+
+	if req.Role != nil {
+		if _, ok := participantRoleRank[*req.Role]; !ok {
+			return nil, errors.New("invalid role")
+		}
+		if participantRoleRank[*req.Role] > participantRoleRank[link.RoleToGrant] {
+			return nil, errors.New("invite link role can only be downgraded")
+		}
+		updated.RoleToGrant = *req.Role
+	}
+
+	now := time.Now()
+	if req.ExpiresInDays != nil {
+		if *req.ExpiresInDays < 1 || *req.ExpiresInDays > 365 {
+			return nil, errors.New("expiresInDays must be between 1 and 365")
+		}
+		// A link without an expiry never expires, so giving it one would tighten it
+		exp := now.AddDate(0, 0, *req.ExpiresInDays)
+		if link.ExpiresAt == nil || exp.Before(*link.ExpiresAt) {
+			return nil, errors.New("invite link expiry can only be extended")
+		}
+		updated.ExpiresAt = &exp
+	}
+
+	if req.MaxUses != nil {
+		// 0 means unlimited, which is always a raise
+		if *req.MaxUses < 0 || (*req.MaxUses > 0 && (link.MaxUses <= 0 || *req.MaxUses < link.MaxUses)) {
+			return nil, errors.New("invite link max uses can only be raised")
+		}
+		updated.MaxUses = *req.MaxUses
+	}
+
+	saved, err := s.inviteLinkRepo.UpdateInviteLink(ctx, &updated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update invite link: %w", err)
+	}
+	if saved == nil {
+		// Revoked since it was read
+		return nil, errors.New("revoked links cannot be edited")
+	}
+
+	// Reactivate auto-deactivated links whose new limits no longer block redemption. The check runs
+	// against the stored link, so a use claimed or a revocation made in the meantime is respected.
+	if !saved.IsActive {
+		reactivated, err := s.inviteLinkRepo.ReactivateInviteLink(ctx, token, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reactivate invite link: %w", err)
+		}
+		if reactivated != nil {
+			saved = reactivated
+		}
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    link.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionUpdate,
+		TargetType: model.AuditTargetInviteLink,
+		TargetID:   link.ID,
+		Changes:    diffFields(link, saved),
+	})
+
+	return convertToInviteLinkResponse(saved), nil
+}
+
+// DeactivateInviteLink revokes an existing invite link, including one that was auto-deactivated for
+// expiry or max uses so it can no longer be reactivated (middleware ensures owner/editor)
+func (s *InviteLinkService) DeactivateInviteLink(ctx context.Context, user *model.User, rallyID string, token string) error {
 
 	// Optionally check if the link belongs to this rally
 	link, err := s.inviteLinkRepo.GetInviteLinkByToken(ctx, token)
 	if err != nil {
 		return fmt.Errorf("failed to get link: %w", err)
 	}
-	if link == nil || !link.IsActive {
-		return errors.New("link not found or already inactive")
+	if link == nil || isInviteLinkRevoked(link) {
+		return errors.New("link not found or already revoked")
 	}
 	if link.RallyID.Hex() != rallyID {
 		return errors.New("link does not belong to this rally")
@@ -138,13 +265,114 @@ func (s *InviteLinkService) DeactivateInviteLink(ctx context.Context, rallyID st
 	// Owners can revoke any link, editors can only revoke links they created or lower tier links
 	// Assuming simple validation for now: owner/editor can revoke. Add strict checks if necessary.
 
-	if err := s.inviteLinkRepo.DeactivateInviteLink(ctx, token); err != nil {
+	if err := s.inviteLinkRepo.RevokeInviteLink(ctx, token); err != nil {
+		if err.Error() == "invite link not found or already revoked" {
+			return errors.New("link not found or already revoked")
+		}
 		return fmt.Errorf("failed to deactivate link: %w", err)
 	}
 
+	deactivated := *link
+	deactivated.IsActive = false
+	deactivated.DeactivatedReason = model.InviteLinkDeactivatedRevoked
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    link.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionUpdate,
+		TargetType: model.AuditTargetInviteLink,
+		TargetID:   link.ID,
+		Changes:    diffFields(link, &deactivated),
+	})
+
 	return nil
 }
 
+// isInviteLinkRevoked reports whether a link was revoked. Inactive links without a reason predate
+// deactivation reasons and are treated as revoked.
+func isInviteLinkRevoked(link *model.InviteLink) bool {
+	return !link.IsActive && link.DeactivatedReason != model.InviteLinkDeactivatedExpired && link.DeactivatedReason != model.InviteLinkDeactivatedMaxUses
+}
+
+// DeactivateExpiredLinks deactivates every invite link whose expiry has passed, instead of waiting
+// for someone to try redeeming it
+func (s *InviteLinkService) DeactivateExpiredLinks(ctx context.Context, now time.Time) error {
+
+	deactivated, err := s.inviteLinkRepo.DeactivateExpiredInviteLinks(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to deactivate expired invite links: %w", err)
+	}
+
+	if deactivated > 0 {
+		log.Printf("[invite-links] deactivated %d expired invite links", deactivated)
+	}
+
+	return nil
+}
+
+// GetInviteLinkQRCode renders a QR code pointing at the deep link of one of a rally's invite links
+// (middleware ensures owner/editor)
+func (s *InviteLinkService) GetInviteLinkQRCode(ctx context.Context, rallyID string, token string, format string, size int, level string) ([]byte, error) {
+	link, err := s.inviteLinkRepo.GetInviteLinkByToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link: %w", err)
+	}
+	if link == nil || link.RallyID.Hex() != rallyID {
+		return nil, errors.New("link not found")
+	}
+
+	deepLink := strings.TrimRight(s.linkBaseURL, "/") + "/" + url.PathEscape(link.Token)
+
+	return utils.RenderQRCode(deepLink, format, size, level)
+}
+
+// GetLinkRedemptions retrieves the redemption history of one of a rally's invite links together with
+// its preview/join statistics (middleware ensures owner/editor)
+func (s *InviteLinkService) GetLinkRedemptions(ctx context.Context, rallyID string, token string, page, pageSize int) (*model.InviteLinkRedemptionListResponse, error) {
+	link, err := s.inviteLinkRepo.GetInviteLinkByToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link: %w", err)
+	}
+	if link == nil || link.RallyID.Hex() != rallyID {
+		return nil, errors.New("link not found")
+	}
+
+	redemptions, total, err := s.redemptionRepo.GetRedemptionsByLink(ctx, link.ID, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link redemptions: %w", err)
+	}
+
+	counts, err := s.redemptionRepo.CountRedemptionsByStatus(ctx, link.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count link redemptions: %w", err)
+	}
+
+	stats := model.InviteLinkStats{
+		Previews:     link.PreviewCount,
+		Joins:        counts[model.ParticipationStatusJoined],
+		JoinRequests: counts[model.ParticipationStatusPending],
+	}
+	stats.Redemptions = stats.Joins + stats.JoinRequests
+	if stats.Previews > 0 {
+		stats.Conversion = float64(stats.Redemptions) / float64(stats.Previews)
+	}
+
+	totalPages := utils.CalcTotalPages(total, pageSize)
+
+	return &model.InviteLinkRedemptionListResponse{
+		Token:       link.Token,
+		Stats:       stats,
+		Redemptions: redemptions,
+		Total:       int(total),
+		Page:        page,
+		PageSize:    pageSize,
+		TotalPages:  totalPages,
+		Pagination: model.PaginationMetadata{
+			HasNextPage:     page < totalPages,
+			HasPreviousPage: page > 1,
+		},
+	}, nil
+}
+
 // PreviewInviteLink gets details about an invitation link for a preview card
 func (s *InviteLinkService) PreviewInviteLink(ctx context.Context, idToken string, token string) (*model.InviteLinkPreviewResponse, error) {
 	user, err := authenticateUser(ctx, s.firebaseAuth, s.userRepo, idToken)
@@ -180,9 +408,12 @@ func (s *InviteLinkService) PreviewInviteLink(ctx context.Context, idToken strin
 	if err != nil {
 		return nil, fmt.Errorf("failed to check existing participation: %w", err)
 	}
+	if existing != nil && existing.Status == model.ParticipationStatusBanned {
+		return nil, errors.New("you are banned from this rally")
+	}
 
-	// If they are not already joined/invited, enforce expiration/max uses
-	if existing == nil || (existing.Status != model.ParticipationStatusJoined && existing.Status != model.ParticipationStatusInvited) {
+	// If they are not already joined/invited/awaiting approval, enforce expiration/max uses
+	if existing == nil || (existing.Status != model.ParticipationStatusJoined && existing.Status != model.ParticipationStatusInvited && existing.Status != model.ParticipationStatusPending) {
 		if isExpired {
 			return nil, errors.New("link is expired")
 		}
@@ -212,6 +443,11 @@ func (s *InviteLinkService) PreviewInviteLink(ctx context.Context, idToken strin
 		eventCount = 0
 	}
 
+	// Count the preview for the link's analytics; a failed count must not break the preview
+	if err := s.inviteLinkRepo.IncrementPreviewCount(ctx, token); err != nil {
+		log.Printf("[invite-links] failed to count preview of link %s: %v", link.ID.Hex(), err)
+	}
+
 	participantID := ""
 	participantStatus := ""
 	if existing != nil {
@@ -234,6 +470,7 @@ func (s *InviteLinkService) PreviewInviteLink(ctx context.Context, idToken strin
 			AvatarUrl: owner.AvatarUrl,
 		},
 		RoleOffered:       link.RoleToGrant,
+		RequiresApproval:  link.RequiresApproval,
 		MemberCount:       memberCount,
 		EventCount:        eventCount,
 		ParticipantID:     participantID,
@@ -243,9 +480,9 @@ func (s *InviteLinkService) PreviewInviteLink(ctx context.Context, idToken strin
 }
 
 // JoinViaLink allows a user to accept an invite link and join a rally directly.
-// This is called when the user taps "Accept" on the invite preview screen.
-// It creates or updates the participant record with "joined" status and increments link usage.
-func (s *InviteLinkService) JoinViaLink(ctx context.Context, idToken string, token string) (*model.JoinViaLinkResponse, error) {
+// The link use is claimed by a conditional update in the same transaction as the participant write
+// (see redeemLink), so concurrent redemptions of a popular link can never overshoot MaxUses.
+func (s *InviteLinkService) JoinViaLink(ctx context.Context, idToken string, token string, message string) (*model.JoinViaLinkResponse, error) {
 	user, err := authenticateUser(ctx, s.firebaseAuth, s.userRepo, idToken)
 	if err != nil {
 		return nil, err
@@ -260,28 +497,35 @@ func (s *InviteLinkService) JoinViaLink(ctx context.Context, idToken string, tok
 		return nil, errors.New("invalid or expired invite link")
 	}
 
-	// Validate Expiration
+	// Fail fast on links already known to be used up; redeemLink enforces these limits atomically
 	if link.ExpiresAt != nil && time.Now().After(*link.ExpiresAt) {
-		_ = s.inviteLinkRepo.DeactivateInviteLink(ctx, token)
+		_ = s.inviteLinkRepo.DeactivateInviteLink(ctx, token, model.InviteLinkDeactivatedExpired)
 		return nil, errors.New("invite link has expired")
 	}
-
-	// Validate Max Uses
 	if link.MaxUses > 0 && link.CurrentUses >= link.MaxUses {
-		_ = s.inviteLinkRepo.DeactivateInviteLink(ctx, token)
+		_ = s.inviteLinkRepo.DeactivateInviteLink(ctx, token, model.InviteLinkDeactivatedMaxUses)
 		return nil, errors.New("invite link has reached its maximum number of uses")
 	}
 
+	// Links to rallies in the trash cannot be redeemed
+	rally, err := s.rallyRepo.GetRallyByID(ctx, link.RallyID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rally: %w", err)
+	}
+	if rally == nil {
+		return nil, errors.New("invalid or expired invite link")
+	}
+
 	// Check if already a participant
 	existing, err := s.participantRepo.GetParticipantByRallyAndUser(ctx, link.RallyID, user.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check existing participant status: %w", err)
 	}
 
-	now := time.Now()
-	joinedStatus := model.ParticipationStatusJoined
-
 	if existing != nil {
+		if existing.Status == model.ParticipationStatusBanned {
+			return nil, errors.New("you are banned from this rally")
+		}
 		if existing.Status == model.ParticipationStatusJoined {
 			// Already joined — no-op, no usage increment
 			return &model.JoinViaLinkResponse{
@@ -292,79 +536,234 @@ func (s *InviteLinkService) JoinViaLink(ctx context.Context, idToken string, tok
 				Status:  string(model.ParticipationStatusJoined),
 			}, nil
 		}
+	}
 
-		// Determine the role to apply
-		var finalRole model.ParticipantRole
-		if existing.Status == model.ParticipationStatusInvited {
-			// Take the higher of existing invite role vs link role
-			finalRole = higherRole(existing.Role, link.RoleToGrant)
-		} else {
-			// Declined or left — fresh start, use the link's role
-			finalRole = link.RoleToGrant
-		}
+	// A direct invitation still lets the user in; anyone else has to ask first
+	if link.RequiresApproval && (existing == nil || existing.Status != model.ParticipationStatusInvited) {
+		return s.requestToJoin(ctx, user, link, existing, message)
+	}
 
-		// Update to joined with the resolved role
-		updates := &model.UpdateParticipantRequest{
-			Status: &joinedStatus,
-			Role:   &finalRole,
-		}
-		if _, err := s.participantRepo.UpdateParticipant(ctx, existing.ID.Hex(), updates); err != nil {
+	// Determine the role to apply
+	finalRole := link.RoleToGrant
+	if existing != nil && existing.Status == model.ParticipationStatusInvited {
+		// Take the higher of existing invite role vs link role
+		finalRole = higherRole(existing.Role, link.RoleToGrant)
+	}
+	// Declined, left or no record — fresh start with the link's role
+
+	joined, err := s.redeemLink(ctx, link, func(sessCtx mongo.SessionContext) (*model.RallyParticipant, error) {
+		participant, err := s.participantRepo.UpsertJoinedParticipant(sessCtx, link.RallyID, user.ID, finalRole, link.CreatedBy)
+		if err != nil {
 			return nil, fmt.Errorf("failed to join rally: %w", err)
 		}
-
-		// Increment link usage on successful join
-		if err := s.inviteLinkRepo.IncrementLinkUsage(ctx, token); err != nil {
-			return nil, fmt.Errorf("failed to process invite link usage: %w", err)
+		if participant == nil {
+			// Banned after the check above; the failed transaction gives the link use back
+			return nil, errors.New("you are banned from this rally")
 		}
+		return participant, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	action := model.AuditActionCreate
+	if existing != nil {
+		action = model.AuditActionUpdate
+	}
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    link.RallyID,
+		ActorID:    user.ID,
+		Action:     action,
+		TargetType: model.AuditTargetParticipant,
+		TargetID:   joined.ID,
+		Changes:    diffFields(existing, joined),
+	})
+
+	// Let whoever shared the link know it worked
+	s.notifications.Notify(ctx, &model.Notification{
+		UserID:  link.CreatedBy,
+		Type:    model.NotificationTypeJoinedViaLink,
+		ActorID: &user.ID,
+		RallyID: &link.RallyID,
+		Data:    map[string]interface{}{"role": joined.Role},
+	})
+
+	msgType := model.RealtimeParticipantAdded
+	if existing != nil {
+		msgType = model.RealtimeParticipantUpdated
+	}
+	publishParticipantUpdate(ctx, s.pubsub, link.RallyID, user.ID, joined.UserID, joined.Status, msgType, convertToParticipantResponse(joined))
+
+	return &model.JoinViaLinkResponse{
+		Success: true,
+		Message: "Successfully joined the rally",
+		RallyID: link.RallyID.Hex(),
+		Role:    string(finalRole),
+		Status:  string(model.ParticipationStatusJoined),
+	}, nil
+}
 
+// requestToJoin files a pending join request through an approval-only link. The link use is counted
+// when the request is filed, so MaxUses also bounds how many requests a link can produce.
+func (s *InviteLinkService) requestToJoin(ctx context.Context, user *model.User, link *model.InviteLink, existing *model.RallyParticipant, message string) (*model.JoinViaLinkResponse, error) {
+	if existing != nil && existing.Status == model.ParticipationStatusPending {
+		// Already waiting — no-op, no usage increment
 		return &model.JoinViaLinkResponse{
 			Success: true,
-			Message: "Successfully joined the rally",
+			Message: "Your request to join is awaiting approval",
 			RallyID: link.RallyID.Hex(),
-			Role:    string(finalRole),
-			Status:  string(model.ParticipationStatusJoined),
+			Role:    string(existing.Role),
+			Status:  string(model.ParticipationStatusPending),
 		}, nil
 	}
 
-	// No existing record — create new participant with "joined" status
-	participant := &model.RallyParticipant{
-		ID:        primitive.NewObjectID(),
-		RallyID:   link.RallyID,
-		UserID:    user.ID,
-		Role:      link.RoleToGrant,
-		Status:    model.ParticipationStatusJoined,
-		InvitedBy: &link.CreatedBy,
-		JoinedAt:  &now,
-		InvitedAt: now,
-	}
+	action := model.AuditActionCreate
+	if existing != nil {
+		action = model.AuditActionUpdate
+	}
+
+	participant, err := s.redeemLink(ctx, link, func(sessCtx mongo.SessionContext) (*model.RallyParticipant, error) {
+		if existing != nil {
+			// Declined or left — reuse the record for the new request
+			participant, err := s.participantRepo.SetJoinRequest(sessCtx, existing.ID, link.RoleToGrant, link.CreatedBy, message)
+			if err != nil {
+				return nil, fmt.Errorf("failed to request to join rally: %w", err)
+			}
+			if participant == nil {
+				// Banned after the check above; the failed transaction gives the link use back
+				return nil, errors.New("you are banned from this rally")
+			}
+			return participant, nil
+		}
 
-	if err := s.participantRepo.CreateParticipant(ctx, participant); err != nil {
-		return nil, fmt.Errorf("failed to join rally: %w", err)
+		participant := &model.RallyParticipant{
+			ID:             primitive.NewObjectID(),
+			RallyID:        link.RallyID,
+			UserID:         user.ID,
+			Role:           link.RoleToGrant,
+			Status:         model.ParticipationStatusPending,
+			InvitedBy:      &link.CreatedBy,
+			InvitedAt:      time.Now(),
+			RequestMessage: message,
+		}
+		if err := s.participantRepo.CreateParticipant(sessCtx, participant); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				// Another join for the same user created the record first
+				return nil, errors.New("a join for this rally is already in progress")
+			}
+			return nil, fmt.Errorf("failed to request to join rally: %w", err)
+		}
+		return participant, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Increment link usage on successful join
-	if err := s.inviteLinkRepo.IncrementLinkUsage(ctx, token); err != nil {
-		return nil, fmt.Errorf("failed to process invite link usage: %w", err)
-	}
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    link.RallyID,
+		ActorID:    user.ID,
+		Action:     action,
+		TargetType: model.AuditTargetParticipant,
+		TargetID:   participant.ID,
+		Changes:    diffFields(existing, participant),
+	})
+
+	s.notifications.Notify(ctx, &model.Notification{
+		UserID:  link.CreatedBy,
+		Type:    model.NotificationTypeJoinRequest,
+		ActorID: &user.ID,
+		RallyID: &link.RallyID,
+		Data:    map[string]interface{}{"participantId": participant.ID.Hex()},
+	})
 
 	return &model.JoinViaLinkResponse{
 		Success: true,
-		Message: "Successfully joined the rally",
+		Message: "Your request to join is awaiting approval",
 		RallyID: link.RallyID.Hex(),
-		Role:    string(link.RoleToGrant),
-		Status:  string(model.ParticipationStatusJoined),
+		Role:    string(participant.Role),
+		Status:  string(model.ParticipationStatusPending),
 	}, nil
 }
 
+// errInviteLinkUnavailable signals that the conditional redemption update matched no link
+var errInviteLinkUnavailable = errors.New("invite link is no longer redeemable")
+
+// redeemLink claims one use of an invite link, runs apply (the participant write) and records the
+// redemption, all in one transaction. The claim only succeeds while the link is active, unexpired
+// and below MaxUses, so once the last use is taken every concurrent redemption fails instead of
+// overshooting the limit, and a failed participant write gives the use back.
+func (s *InviteLinkService) redeemLink(ctx context.Context, link *model.InviteLink, apply func(sessCtx mongo.SessionContext) (*model.RallyParticipant, error)) (*model.RallyParticipant, error) {
+	session, err := s.db.Client().StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	result, err := session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		redeemed, err := s.inviteLinkRepo.RedeemInviteLink(sessCtx, link.Token, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("failed to process invite link usage: %w", err)
+		}
+		if redeemed == nil {
+			return nil, errInviteLinkUnavailable
+		}
+
+		participant, err := apply(sessCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.redemptionRepo.CreateRedemption(sessCtx, &model.InviteLinkRedemption{
+			LinkID:  redeemed.ID,
+			RallyID: redeemed.RallyID,
+			UserID:  participant.UserID,
+			Role:    participant.Role,
+			Status:  participant.Status,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to record invite link redemption: %w", err)
+		}
+
+		return participant, nil
+	})
+	if err == nil {
+		return result.(*model.RallyParticipant), nil
+	}
+	if !errors.Is(err, errInviteLinkUnavailable) {
+		return nil, err
+	}
+
+	// Lost the race for the last use, or the link expired or was revoked in the meantime
+	current, err := s.inviteLinkRepo.GetInviteLinkByToken(ctx, link.Token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check invite link: %w", err)
+	}
+	switch {
+	case current == nil || !current.IsActive:
+		return nil, errors.New("invalid or expired invite link")
+	case current.ExpiresAt != nil && time.Now().After(*current.ExpiresAt):
+		_ = s.inviteLinkRepo.DeactivateInviteLink(ctx, link.Token, model.InviteLinkDeactivatedExpired)
+		return nil, errors.New("invite link has expired")
+	case current.MaxUses > 0 && current.CurrentUses >= current.MaxUses:
+		_ = s.inviteLinkRepo.DeactivateInviteLink(ctx, link.Token, model.InviteLinkDeactivatedMaxUses)
+		return nil, errors.New("invite link has reached its maximum number of uses")
+	default:
+		// The link changed between the claim and this read (e.g. it was edited or reactivated);
+		// nothing shows it is used up, so leave it alone and let the user try again
+		return nil, errors.New("invite link changed while joining, please try again")
+	}
+}
+
+// participantRoleRank orders roles from least to most privileged
+var participantRoleRank = map[model.ParticipantRole]int{
+	model.ParticipantRoleParticipant: 0,
+	model.ParticipantRoleEditor:      1,
+	model.ParticipantRoleOwner:       2,
+}
+
 // higherRole returns the more privileged of two roles.
 // Precedence: owner > editor > participant.
 func higherRole(a, b model.ParticipantRole) model.ParticipantRole {
-	rank := map[model.ParticipantRole]int{
-		model.ParticipantRoleParticipant: 0,
-		model.ParticipantRoleEditor:      1,
-		model.ParticipantRoleOwner:       2,
-	}
-	if rank[b] > rank[a] {
+	if participantRoleRank[b] > participantRoleRank[a] {
 		return b
 	}
 	return a
@@ -382,5 +781,10 @@ func convertToInviteLinkResponse(link *model.InviteLink) *model.InviteLinkRespon
 		CurrentUses: link.CurrentUses,
 		IsActive:    link.IsActive,
 		CreatedAt:   link.CreatedAt,
+
+		RequiresApproval: link.RequiresApproval,
+		PreviewCount:     link.PreviewCount,
+
+		DeactivatedReason: link.DeactivatedReason,
 	}
 }
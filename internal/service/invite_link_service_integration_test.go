@@ -0,0 +1,184 @@
+//go:build integration
+
+// Integration tests for invite link redemption. They need a MongoDB replica set (transactions are
+// not available on a standalone server):
+//
+//	MONGODB_TEST_URI="mongodb://localhost:27017/?replicaSet=rs0" go test -tags integration ./internal/service/...
+package service
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// newIntegrationDB connects to the test replica set and returns a throwaway database that is
+// dropped when the test finishes
+func newIntegrationDB(t *testing.T) *mongo.Database {
+	t.Helper()
+
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_URI is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("failed to connect to MongoDB: %v", err)
+	}
+
+	db := client.Database("rally_it_" + primitive.NewObjectID().Hex())
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = db.Drop(ctx)
+		_ = client.Disconnect(ctx)
+	})
+
+	// Transactions cannot create collections on older servers, so create them up front
+	for _, name := range []string{"invite_links", "invite_link_redemptions", "rally_participants"} {
+		if err := db.CreateCollection(ctx, name); err != nil {
+			t.Fatalf("failed to create collection %s: %v", name, err)
+		}
+	}
+
+	return db
+}
+
+// newIntegrationInviteLinkService builds an InviteLinkService with just the dependencies redeemLink uses
+func newIntegrationInviteLinkService(t *testing.T, db *mongo.Database) *InviteLinkService {
+	t.Helper()
+
+	participantRepo := repository.NewRallyParticipantRepository(db)
+	if err := participantRepo.EnsureIndexes(context.Background()); err != nil {
+		t.Fatalf("failed to create participant indexes: %v", err)
+	}
+
+	return &InviteLinkService{
+		db:              db,
+		inviteLinkRepo:  repository.NewInviteLinkRepository(db),
+		redemptionRepo:  repository.NewInviteLinkRedemptionRepository(db),
+		participantRepo: participantRepo,
+	}
+}
+
+func TestRedeemLinkConcurrentJoinsNeverOvershootMaxUses(t *testing.T) {
+	db := newIntegrationDB(t)
+	s := newIntegrationInviteLinkService(t, db)
+	ctx := context.Background()
+
+	const maxUses = 5
+	const joiners = 40
+
+	link := &model.InviteLink{
+		ID:          primitive.NewObjectID(),
+		RallyID:     primitive.NewObjectID(),
+		CreatedBy:   primitive.NewObjectID(),
+		Token:       primitive.NewObjectID().Hex(),
+		RoleToGrant: model.ParticipantRoleParticipant,
+		MaxUses:     maxUses,
+		IsActive:    true,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.inviteLinkRepo.CreateInviteLink(ctx, link); err != nil {
+		t.Fatalf("failed to create invite link: %v", err)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	start := make(chan struct{})
+	for i := 0; i < joiners; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			userID := primitive.NewObjectID()
+			<-start
+
+			_, err := s.redeemLink(ctx, link, func(sessCtx mongo.SessionContext) (*model.RallyParticipant, error) {
+				return s.participantRepo.UpsertJoinedParticipant(sessCtx, link.RallyID, userID, link.RoleToGrant, link.CreatedBy)
+			})
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if succeeded != maxUses {
+		t.Errorf("succeeded joins = %d, want %d", succeeded, maxUses)
+	}
+
+	current, err := s.inviteLinkRepo.GetInviteLinkByToken(ctx, link.Token)
+	if err != nil {
+		t.Fatalf("failed to reload invite link: %v", err)
+	}
+	if current.CurrentUses != maxUses {
+		t.Errorf("current uses = %d, want %d", current.CurrentUses, maxUses)
+	}
+
+	joined, err := s.participantRepo.CountJoinedParticipants(ctx, link.RallyID)
+	if err != nil {
+		t.Fatalf("failed to count participants: %v", err)
+	}
+	if joined != maxUses {
+		t.Errorf("joined participants = %d, want %d", joined, maxUses)
+	}
+
+	redemptions, err := db.Collection("invite_link_redemptions").CountDocuments(ctx, bson.M{"link_id": link.ID})
+	if err != nil {
+		t.Fatalf("failed to count redemptions: %v", err)
+	}
+	if redemptions != maxUses {
+		t.Errorf("redemptions = %d, want %d", redemptions, maxUses)
+	}
+}
+
+func TestUpsertJoinedParticipantKeepsBan(t *testing.T) {
+	db := newIntegrationDB(t)
+	s := newIntegrationInviteLinkService(t, db)
+	ctx := context.Background()
+
+	banned := &model.RallyParticipant{
+		RallyID: primitive.NewObjectID(),
+		UserID:  primitive.NewObjectID(),
+		Role:    model.ParticipantRoleParticipant,
+		Status:  model.ParticipationStatusBanned,
+	}
+	if err := s.participantRepo.CreateParticipant(ctx, banned); err != nil {
+		t.Fatalf("failed to create participant: %v", err)
+	}
+
+	participant, err := s.participantRepo.UpsertJoinedParticipant(ctx, banned.RallyID, banned.UserID, model.ParticipantRoleEditor, primitive.NewObjectID())
+	if err != nil {
+		t.Fatalf("UpsertJoinedParticipant returned error: %v", err)
+	}
+	if participant != nil {
+		t.Fatalf("UpsertJoinedParticipant joined a banned user with status %q", participant.Status)
+	}
+
+	current, err := s.participantRepo.GetParticipantByRallyAndUser(ctx, banned.RallyID, banned.UserID)
+	if err != nil {
+		t.Fatalf("failed to reload participant: %v", err)
+	}
+	if current == nil || current.Status != model.ParticipationStatusBanned {
+		t.Errorf("participant after join attempt = %+v, want status banned", current)
+	}
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// maxExpenseAmount caps expense amounts (in minor units) so proportional splits cannot overflow int64
+	maxExpenseAmount = 1_000_000_000_000_000
+	// maxExpenseShares caps the shares of a single participant in the shares split mode
+	maxExpenseShares = 1000
+)
+
+// splitExpense resolves how much each participant owes for an expense of amount minor units. Splits
+// carry the participants in the order given by the client along with their shares (shares mode) or
+// amounts (exact mode). Rounding leftovers of the equal and shares modes are handed out one minor unit
+// at a time, so the resolved amounts always add up to the expense amount exactly.
+func splitExpense(amount int64, mode model.ExpenseSplitMode, splits []model.ExpenseSplit) ([]model.ExpenseSplit, error) {
+	if len(splits) == 0 {
+		return nil, errors.New("at least one participant must share the expense")
+	}
+
+	resolved := make([]model.ExpenseSplit, len(splits))
+	switch mode {
+	case model.ExpenseSplitEqual:
+		n := int64(len(splits))
+		for i, split := range splits {
+			resolved[i] = model.ExpenseSplit{UserID: split.UserID, Amount: amount / n}
+			// The first participants absorb the remainder, one minor unit each
+			if int64(i) < amount%n {
+				resolved[i].Amount++
+			}
+		}
+
+	case model.ExpenseSplitShares:
+		shares := make([]int64, len(splits))
+		for i, split := range splits {
+			if split.Shares <= 0 || split.Shares > maxExpenseShares {
+				return nil, errors.New("shares must be between 1 and 1000")
+			}
+			shares[i] = split.Shares
+		}
+
+		for i, part := range allocateProportionally(amount, shares) {
+			resolved[i] = model.ExpenseSplit{UserID: splits[i].UserID, Shares: splits[i].Shares, Amount: part}
+		}
+
+	case model.ExpenseSplitExact:
+		var total int64
+		for i, split := range splits {
+			if split.Amount < 0 {
+				return nil, errors.New("split amounts cannot be negative")
+			}
+			resolved[i] = model.ExpenseSplit{UserID: split.UserID, Amount: split.Amount}
+			total += split.Amount
+		}
+		if total != amount {
+			return nil, errors.New("split amounts must add up to the expense amount")
+		}
+
+	default:
+		return nil, errors.New("invalid split mode")
+	}
+
+	return resolved, nil
+}
+
+// allocateProportionally divides total minor units between weights that are not all zero using the
+// largest remainder method: every part is rounded down, then the leftover minor units go to the parts
+// that were rounded down the most (earlier parts first on ties). The parts always add up to total.
+func allocateProportionally(total int64, weights []int64) []int64 {
+	var weightSum big.Int
+	for _, weight := range weights {
+		weightSum.Add(&weightSum, big.NewInt(weight))
+	}
+
+	// Products can exceed int64 (a large amount spread by large weights), so work in big integers
+	parts := make([]int64, len(weights))
+	remainders := make([]*big.Int, len(weights))
+	allocated := int64(0)
+	for i, weight := range weights {
+		var quotient, remainder big.Int
+		quotient.Mul(big.NewInt(total), big.NewInt(weight))
+		quotient.QuoRem(&quotient, &weightSum, &remainder)
+		parts[i] = quotient.Int64()
+		remainders[i] = &remainder
+		allocated += parts[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]].Cmp(remainders[order[b]]) > 0
+	})
+	for i := int64(0); i < total-allocated; i++ {
+		parts[order[i]]++
+	}
+
+	return parts
+}
+
+// convertAmount converts amount minor units of the from currency into minor units of the to currency,
+// where rate is the value of one major unit of from in major units of to. The result is rounded to the
+// nearest minor unit, halves away from zero.
+func convertAmount(amount int64, from string, to string, rate *big.Rat) (int64, error) {
+	value := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), rate)
+
+	// Rescale from the minor unit of one currency to the minor unit of the other (e.g. 2 decimals for
+	// USD, none for VND)
+	shift := utils.CurrencyExponent(to) - utils.CurrencyExponent(from)
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(shift))), nil))
+	if shift >= 0 {
+		value.Mul(value, scale)
+	} else {
+		value.Quo(value, scale)
+	}
+
+	// Round half away from zero: add half of the denominator to the numerator, then truncate
+	num := new(big.Int).Abs(value.Num())
+	den := value.Denom()
+	num.Add(num.Mul(num, big.NewInt(2)), den)
+	num.Quo(num, new(big.Int).Mul(den, big.NewInt(2)))
+	if value.Sign() < 0 {
+		num.Neg(num)
+	}
+
+	if !num.IsInt64() || num.Int64() > maxExpenseAmount || num.Int64() < -maxExpenseAmount {
+		return 0, errors.New("converted amount is too large")
+	}
+	return num.Int64(), nil
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// settleUp turns net balances (positive: owed money, negative: owes money) that add up to zero into
+// a short list of transfers by repeatedly matching the largest debtor with the largest creditor.
+// Every transfer clears at least one of the two, so there are fewer transfers than participants.
+func settleUp(nets map[primitive.ObjectID]int64) []model.SettlementTransfer {
+	type party struct {
+		userID primitive.ObjectID
+		amount int64
+	}
+
+	var creditors, debtors []party
+	for userID, net := range nets {
+		switch {
+		case net > 0:
+			creditors = append(creditors, party{userID, net})
+		case net < 0:
+			debtors = append(debtors, party{userID, -net})
+		}
+	}
+	// Largest first; ties are broken by ID so the plan is stable between requests
+	byAmount := func(parties []party) func(a, b int) bool {
+		return func(a, b int) bool {
+			if parties[a].amount != parties[b].amount {
+				return parties[a].amount > parties[b].amount
+			}
+			return parties[a].userID.Hex() < parties[b].userID.Hex()
+		}
+	}
+	sort.Slice(creditors, byAmount(creditors))
+	sort.Slice(debtors, byAmount(debtors))
+
+	transfers := []model.SettlementTransfer{}
+	for c, d := 0, 0; c < len(creditors) && d < len(debtors); {
+		amount := min(creditors[c].amount, debtors[d].amount)
+		transfers = append(transfers, model.SettlementTransfer{
+			FromUserID: debtors[d].userID.Hex(),
+			ToUserID:   creditors[c].userID.Hex(),
+			Amount:     amount,
+		})
+
+		creditors[c].amount -= amount
+		debtors[d].amount -= amount
+		if creditors[c].amount == 0 {
+			c++
+		}
+		if debtors[d].amount == 0 {
+			d++
+		}
+	}
+
+	return transfers
+}
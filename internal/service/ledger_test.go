@@ -0,0 +1,94 @@
+package service
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestConvertAmount(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount int64
+		from   string
+		to     string
+		rate   string
+		want   int64
+	}{
+		{"cents to a zero-decimal currency", 1050, "USD", "VND", "25000", 262500},
+		{"zero-decimal currency to cents", 262500, "VND", "USD", "1/25000", 1050},
+		{"cents to a three-decimal currency", 100, "USD", "KWD", "3/10", 300},
+		{"same exponent", 1000, "EUR", "USD", "1.08", 1080},
+		{"rounds down below a half", 3, "EUR", "USD", "1.1", 3},
+		{"rounds a half away from zero", 1, "EUR", "USD", "1.5", 2},
+		{"rounds a negative half away from zero", -1, "EUR", "USD", "1.5", -2},
+		{"zero amount", 0, "USD", "VND", "25000", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, ok := new(big.Rat).SetString(tt.rate)
+			if !ok {
+				t.Fatalf("invalid rate %q", tt.rate)
+			}
+
+			got, err := convertAmount(tt.amount, tt.from, tt.to, rate)
+			if err != nil {
+				t.Fatalf("convertAmount returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("convertAmount(%d, %s, %s, %s) = %d, want %d", tt.amount, tt.from, tt.to, tt.rate, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertAmountTooLarge(t *testing.T) {
+	if _, err := convertAmount(maxExpenseAmount, "USD", "EUR", big.NewRat(10, 1)); err == nil {
+		t.Error("convertAmount accepted a result above maxExpenseAmount")
+	}
+	if _, err := convertAmount(-maxExpenseAmount, "USD", "EUR", big.NewRat(10, 1)); err == nil {
+		t.Error("convertAmount accepted a result below -maxExpenseAmount")
+	}
+}
+
+func TestAllocateProportionally(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int64
+		weights []int64
+		want    []int64
+	}{
+		{"even split", 90, []int64{1, 1, 1}, []int64{30, 30, 30}},
+		{"leftover goes to earlier parts on ties", 100, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{"leftover goes to the largest remainder", 10, []int64{1, 2, 3}, []int64{2, 3, 5}},
+		{"zero weight gets nothing", 7, []int64{0, 1}, []int64{0, 7}},
+		{"zero total", 0, []int64{1, 2}, []int64{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := allocateProportionally(tt.total, tt.weights)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("allocateProportionally(%d, %v) = %v, want %v", tt.total, tt.weights, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllocateProportionallyLargeAmountsAddUp(t *testing.T) {
+	// total * weight overflows int64 here, which must not change the result
+	weights := []int64{maxExpenseShares, maxExpenseShares - 1, 1, 7}
+	parts := allocateProportionally(maxExpenseAmount, weights)
+
+	var sum int64
+	for _, part := range parts {
+		if part < 0 {
+			t.Fatalf("allocateProportionally returned a negative part: %v", parts)
+		}
+		sum += part
+	}
+	if sum != maxExpenseAmount {
+		t.Errorf("parts add up to %d, want %d", sum, maxExpenseAmount)
+	}
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"firebase.google.com/go/v4/auth"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type NotificationService struct {
+	firebaseAuth     *auth.Client
+	notificationRepo repository.NotificationRepository
+	userRepo         repository.UserRepository
+	push             *PushDispatcher
+}
+
+// pushTimeout bounds how long a background push dispatch may take
+const pushTimeout = 30 * time.Second
+
+func NewNotificationService(
+	firebaseAuth *auth.Client,
+	notificationRepo repository.NotificationRepository,
+	userRepo repository.UserRepository,
+	push *PushDispatcher,
+) *NotificationService {
+	return &NotificationService{
+		firebaseAuth:     firebaseAuth,
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		push:             push,
+	}
+}
+
+// Notify delivers notifications to their recipients' notification centers and devices. Like audit entries,
+// notifications are best-effort: a failure is logged and never fails the operation that produced them.
+// Notifications addressed to their own actor are dropped.
+func (s *NotificationService) Notify(ctx context.Context, notifications ...*model.Notification) {
+	pending := make([]*model.Notification, 0, len(notifications))
+	for _, notification := range notifications {
+		if notification.ActorID != nil && *notification.ActorID == notification.UserID {
+			continue
+		}
+		pending = append(pending, notification)
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	if err := s.notificationRepo.CreateNotifications(ctx, pending); err != nil {
+		log.Printf("[notifications] failed to create %d %s notifications: %v", len(pending), pending[0].Type, err)
+	}
+
+	if s.push != nil {
+		// Pushing waits on the push service, so it runs in the background rather than delaying the
+		// request, with its own context since the request's is cancelled once the response is sent
+		go func() {
+			pushCtx, cancel := context.WithTimeout(context.Background(), pushTimeout)
+			defer cancel()
+			s.push.Dispatch(pushCtx, pending)
+		}()
+	}
+}
+
+// GetNotifications retrieves a page of the authenticated user's notifications, newest first.
+// cursor is the nextCursor of the previous page, or empty for the first page.
+func (s *NotificationService) GetNotifications(ctx context.Context, idToken string, cursor string, limit int, unreadOnly bool) (*model.NotificationListResponse, error) {
+	user, err := authenticateUser(ctx, s.firebaseAuth, s.userRepo, idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var before *primitive.ObjectID
+	if cursor != "" {
+		cursorID, err := primitive.ObjectIDFromHex(cursor)
+		if err != nil {
+			return nil, errors.New("invalid cursor")
+		}
+		before = &cursorID
+	}
+
+	// Fetch one extra notification to learn whether another page exists
+	notifications, err := s.notificationRepo.GetNotifications(ctx, user.ID, before, limit+1, unreadOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+
+	hasMore := len(notifications) > limit
+	if hasMore {
+		notifications = notifications[:limit]
+	}
+
+	unread, err := s.notificationRepo.CountUnread(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	response := &model.NotificationListResponse{
+		Notifications: notifications,
+		UnreadCount:   unread,
+		HasMore:       hasMore,
+	}
+	if hasMore {
+		response.NextCursor = notifications[len(notifications)-1].ID
+	}
+
+	return response, nil
+}
+
+// GetUnreadCount counts the authenticated user's unread notifications
+func (s *NotificationService) GetUnreadCount(ctx context.Context, idToken string) (*model.UnreadNotificationCountResponse, error) {
+	user, err := authenticateUser(ctx, s.firebaseAuth, s.userRepo, idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := s.notificationRepo.CountUnread(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	return &model.UnreadNotificationCountResponse{UnreadCount: unread}, nil
+}
+
+// MarkRead marks one of the authenticated user's notifications as read
+func (s *NotificationService) MarkRead(ctx context.Context, idToken string, notificationID string) error {
+	user, err := authenticateUser(ctx, s.firebaseAuth, s.userRepo, idToken)
+	if err != nil {
+		return err
+	}
+
+	notificationObjID, err := primitive.ObjectIDFromHex(notificationID)
+	if err != nil {
+		return errors.New("notification not found")
+	}
+
+	if err := s.notificationRepo.MarkRead(ctx, user.ID, notificationObjID); err != nil {
+		if err.Error() == "notification not found" {
+			return err
+		}
+		return fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+
+	return nil
+}
+
+// MarkAllRead marks every notification of the authenticated user as read
+func (s *NotificationService) MarkAllRead(ctx context.Context, idToken string) error {
+	user, err := authenticateUser(ctx, s.firebaseAuth, s.userRepo, idToken)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.notificationRepo.MarkAllRead(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,633 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/realtime"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/repository"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	maxPollQuestionLength   = 200 // Longest poll question accepted, in characters
+	maxPollOptionTextLength = 100 // Longest poll option accepted, in characters
+	maxPollOptions          = 20  // Most options a poll can offer
+)
+
+type PollService struct {
+	db              *mongo.Database
+	pollRepo        repository.PollRepository
+	voteRepo        repository.PollVoteRepository
+	rallyRepo       repository.RallyRepository
+	eventRepo       repository.EventRepository
+	activityService *ActivityService
+	auditRepo       repository.AuditLogRepository
+	pubsub          realtime.PubSub
+}
+
+func NewPollService(
+	db *mongo.Database,
+	pollRepo repository.PollRepository,
+	voteRepo repository.PollVoteRepository,
+	rallyRepo repository.RallyRepository,
+	eventRepo repository.EventRepository,
+	activityService *ActivityService,
+	auditRepo repository.AuditLogRepository,
+	pubsub realtime.PubSub,
+) *PollService {
+	return &PollService{
+		db:              db,
+		pollRepo:        pollRepo,
+		voteRepo:        voteRepo,
+		rallyRepo:       rallyRepo,
+		eventRepo:       eventRepo,
+		activityService: activityService,
+		auditRepo:       auditRepo,
+		pubsub:          pubsub,
+	}
+}
+
+// CreatePoll creates a poll in a rally, optionally attached to one of its events (middleware ensures
+// joined participant)
+func (s *PollService) CreatePoll(ctx context.Context, user *model.User, rallyID string, req *model.CreatePollRequest) (*model.PollResponse, error) {
+	rallyObjID, err := primitive.ObjectIDFromHex(rallyID)
+	if err != nil {
+		return nil, errors.New("invalid rally ID")
+	}
+	if err := ensureRallyEditable(ctx, s.rallyRepo, rallyID); err != nil {
+		return nil, err
+	}
+
+	question := strings.TrimSpace(req.Question)
+	if question == "" {
+		return nil, errors.New("poll question is required")
+	}
+	if utf8.RuneCountInString(question) > maxPollQuestionLength {
+		return nil, errors.New("poll question is too long")
+	}
+	if len(req.Options) < 2 || len(req.Options) > maxPollOptions {
+		return nil, errors.New("a poll must have between 2 and 20 options")
+	}
+	if req.Deadline != nil && !req.Deadline.After(time.Now()) {
+		return nil, errors.New("poll deadline must be in the future")
+	}
+
+	poll := &model.Poll{
+		ID:             primitive.NewObjectID(),
+		RallyID:        rallyObjID,
+		Question:       question,
+		Options:        make([]model.PollOption, len(req.Options)),
+		MultipleChoice: req.MultipleChoice,
+		Anonymous:      req.Anonymous,
+		Deadline:       req.Deadline,
+		Status:         model.PollStatusOpen,
+		CreatedBy:      user.ID,
+	}
+	for i, option := range req.Options {
+		text := strings.TrimSpace(option.Text)
+		if text == "" {
+			return nil, errors.New("poll option text is required")
+		}
+		if utf8.RuneCountInString(text) > maxPollOptionTextLength {
+			return nil, errors.New("poll option text is too long")
+		}
+		poll.Options[i] = model.PollOption{
+			ID:            primitive.NewObjectID(),
+			Text:          text,
+			GooglePlaceID: option.GooglePlaceID,
+			Lat:           option.Lat,
+			Lng:           option.Lng,
+		}
+	}
+	if req.EventID != "" {
+		event, err := s.getRallyEvent(ctx, rallyObjID, req.EventID)
+		if err != nil {
+			return nil, err
+		}
+		poll.EventID = &event.ID
+	}
+
+	if err := s.pollRepo.CreatePoll(ctx, poll); err != nil {
+		return nil, fmt.Errorf("failed to create poll: %w", err)
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    poll.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionCreate,
+		TargetType: model.AuditTargetPoll,
+		TargetID:   poll.ID,
+		Changes:    diffFields(nil, poll),
+	})
+
+	publishRallyUpdate(ctx, s.pubsub, poll.RallyID, user.ID, model.RealtimePollCreated, convertToPollResponse(poll, nil, nil))
+
+	return convertToPollResponse(poll, nil, &user.ID), nil
+}
+
+// GetPoll retrieves a poll with its current results and the caller's vote (middleware ensures joined
+// participant)
+func (s *PollService) GetPoll(ctx context.Context, user *model.User, rallyID string, pollID string) (*model.PollResponse, error) {
+	poll, err := s.getPoll(ctx, rallyID, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	votes, err := s.voteRepo.GetVotesByPoll(ctx, poll.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poll votes: %w", err)
+	}
+
+	return convertToPollResponse(poll, votes, &user.ID), nil
+}
+
+// GetPollsList retrieves a paginated list of a rally's polls with their results, newest first,
+// optionally only open or closed ones (middleware ensures joined participant). The status filter
+// follows the stored status, so polls past their deadline still count as open until they are closed.
+func (s *PollService) GetPollsList(ctx context.Context, user *model.User, rallyID string, status model.PollStatus, page, pageSize int) (*model.PollListResponse, error) {
+	rallyObjID, err := primitive.ObjectIDFromHex(rallyID)
+	if err != nil {
+		return nil, errors.New("invalid rally ID")
+	}
+	if status != "" && status != model.PollStatusOpen && status != model.PollStatusClosed {
+		return nil, errors.New("invalid poll status")
+	}
+
+	polls, total, err := s.pollRepo.GetPollsByRally(ctx, rallyObjID, status, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get polls: %w", err)
+	}
+
+	pollIDs := make([]primitive.ObjectID, len(polls))
+	for i, poll := range polls {
+		pollIDs[i] = poll.ID
+	}
+	votes, err := s.voteRepo.GetVotesByPolls(ctx, pollIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poll votes: %w", err)
+	}
+	votesByPoll := make(map[primitive.ObjectID][]model.PollVote, len(polls))
+	for _, vote := range votes {
+		votesByPoll[vote.PollID] = append(votesByPoll[vote.PollID], vote)
+	}
+
+	responses := make([]model.PollResponse, len(polls))
+	for i := range polls {
+		responses[i] = *convertToPollResponse(&polls[i], votesByPoll[polls[i].ID], &user.ID)
+	}
+
+	totalPages := utils.CalcTotalPages(total, pageSize)
+
+	return &model.PollListResponse{
+		Polls:      responses,
+		Total:      int(total),
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+		Pagination: model.PaginationMetadata{
+			HasNextPage:     page < totalPages,
+			HasPreviousPage: page > 1,
+		},
+	}, nil
+}
+
+// Vote casts or replaces the caller's vote in a poll that is still open and before its deadline
+// (middleware ensures joined participant, so only joined participants can vote)
+func (s *PollService) Vote(ctx context.Context, user *model.User, rallyID string, pollID string, req *model.VotePollRequest) (*model.PollResponse, error) {
+	poll, err := s.getVotablePoll(ctx, rallyID, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.OptionIDs) == 0 {
+		return nil, errors.New("at least one option must be chosen")
+	}
+	if !poll.MultipleChoice && len(req.OptionIDs) > 1 {
+		return nil, errors.New("only one option can be chosen in this poll")
+	}
+
+	optionIDs := make([]primitive.ObjectID, 0, len(req.OptionIDs))
+	chosen := make(map[primitive.ObjectID]bool, len(req.OptionIDs))
+	for _, optionID := range req.OptionIDs {
+		option := findPollOption(poll, optionID)
+		if option == nil {
+			return nil, errors.New("invalid poll option")
+		}
+		if chosen[option.ID] {
+			return nil, errors.New("an option can only be chosen once")
+		}
+		chosen[option.ID] = true
+		optionIDs = append(optionIDs, option.ID)
+	}
+
+	err = s.withVotablePoll(ctx, poll.ID, func(sessCtx mongo.SessionContext) error {
+		if err := s.voteRepo.UpsertVote(sessCtx, &model.PollVote{
+			PollID:    poll.ID,
+			RallyID:   poll.RallyID,
+			UserID:    user.ID,
+			OptionIDs: optionIDs,
+		}); err != nil {
+			return fmt.Errorf("failed to vote: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.publishResults(ctx, user, poll)
+}
+
+// RetractVote removes the caller's vote from a poll that is still open and before its deadline
+// (middleware ensures joined participant)
+func (s *PollService) RetractVote(ctx context.Context, user *model.User, rallyID string, pollID string) (*model.PollResponse, error) {
+	poll, err := s.getVotablePoll(ctx, rallyID, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.withVotablePoll(ctx, poll.ID, func(sessCtx mongo.SessionContext) error {
+		if err := s.voteRepo.DeleteVote(sessCtx, poll.ID, user.ID); err != nil {
+			if err.Error() == "vote not found" {
+				return err
+			}
+			return fmt.Errorf("failed to retract vote: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.publishResults(ctx, user, poll)
+}
+
+// ClosePoll stops a poll and records its winning option: the option with the most votes, or the one
+// picked by the caller among the options tied for the most votes (middleware ensures owner or editor).
+// With CreateActivity, the winning option becomes an activity through ActivityService, in the poll's
+// event or the one given in the request; if that fails the poll is reopened.
+func (s *PollService) ClosePoll(ctx context.Context, user *model.User, rallyID string, pollID string, req *model.ClosePollRequest) (*model.PollResponse, error) {
+	poll, err := s.getPoll(ctx, rallyID, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if poll.Status == model.PollStatusClosed {
+		return nil, errors.New("poll is already closed")
+	}
+	if err := ensureRallyEditable(ctx, s.rallyRepo, rallyID); err != nil {
+		return nil, err
+	}
+
+	eventID := ""
+	if req.CreateActivity {
+		switch {
+		case poll.EventID != nil:
+			eventID = poll.EventID.Hex()
+		case req.EventID != "":
+			event, err := s.getRallyEvent(ctx, poll.RallyID, req.EventID)
+			if err != nil {
+				return nil, err
+			}
+			eventID = event.ID.Hex()
+		default:
+			return nil, errors.New("an event is required to create the activity")
+		}
+	}
+
+	session, err := s.db.Client().StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	// Votes are counted and the poll closed in one transaction. A vote arriving meanwhile writes the
+	// poll too (see withVotablePoll), so one of the two is retried and no vote is stored uncounted.
+	var votes []model.PollVote
+	var winner *model.PollOption
+	var closed *model.Poll
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		var err error
+		votes, err = s.voteRepo.GetVotesByPoll(sessCtx, poll.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get poll votes: %w", err)
+		}
+
+		winner, err = pickPollWinner(poll, votes, req.OptionID, req.CreateActivity)
+		if err != nil {
+			return nil, err
+		}
+
+		var winningOptionID *primitive.ObjectID
+		if winner != nil {
+			winningOptionID = &winner.ID
+		}
+		closed, err = s.pollRepo.ClosePoll(sessCtx, poll.ID, user.ID, winningOptionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to close poll: %w", err)
+		}
+		if closed == nil {
+			return nil, errors.New("poll is already closed")
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if req.CreateActivity {
+		activity, err := s.activityService.CreateActivity(ctx, user, eventID, &model.CreateActivityRequest{
+			Name:          winner.Text,
+			GooglePlaceID: winner.GooglePlaceID,
+			Lat:           winner.Lat,
+			Lng:           winner.Lng,
+			StartTime:     req.StartTime,
+			EndTime:       req.EndTime,
+			Notes:         "Chosen by poll: " + poll.Question,
+			ActivityOrder: req.ActivityOrder,
+		})
+		if err != nil {
+			if reopenErr := s.pollRepo.ReopenPoll(ctx, poll.ID); reopenErr != nil {
+				return nil, fmt.Errorf("failed to reopen poll after %v: %w", err, reopenErr)
+			}
+			return nil, err
+		}
+
+		activityID, err := primitive.ObjectIDFromHex(activity.ID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid activity ID: %w", err)
+		}
+		updated, err := s.pollRepo.SetPollActivity(ctx, poll.ID, activityID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to link poll activity: %w", err)
+		}
+		if updated != nil {
+			closed = updated
+		}
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    closed.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionUpdate,
+		TargetType: model.AuditTargetPoll,
+		TargetID:   closed.ID,
+		Changes:    diffFields(poll, closed),
+	})
+
+	publishRallyUpdate(ctx, s.pubsub, closed.RallyID, user.ID, model.RealtimePollClosed, convertToPollResponse(closed, votes, nil))
+
+	return convertToPollResponse(closed, votes, &user.ID), nil
+}
+
+// DeletePoll permanently removes a poll and its votes. The participant who created it may delete it,
+// as may owners and editors (middleware ensures joined participant).
+func (s *PollService) DeletePoll(ctx context.Context, user *model.User, callerParticipant *model.RallyParticipant, rallyID string, pollID string) error {
+	poll, err := s.getPoll(ctx, rallyID, pollID)
+	if err != nil {
+		return err
+	}
+	if poll.CreatedBy != user.ID && callerParticipant.Role != model.ParticipantRoleOwner && callerParticipant.Role != model.ParticipantRoleEditor {
+		return errors.New("unauthorized: insufficient permissions")
+	}
+	if err := ensureRallyEditable(ctx, s.rallyRepo, rallyID); err != nil {
+		return err
+	}
+
+	if err := s.pollRepo.DeletePoll(ctx, poll.ID); err != nil {
+		if err.Error() == "poll not found" {
+			return err
+		}
+		return fmt.Errorf("failed to delete poll: %w", err)
+	}
+	if err := s.voteRepo.DeleteVotesByPoll(ctx, poll.ID); err != nil {
+		return fmt.Errorf("failed to delete poll votes: %w", err)
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    poll.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionDelete,
+		TargetType: model.AuditTargetPoll,
+		TargetID:   poll.ID,
+		Changes:    diffFields(poll, nil),
+	})
+
+	publishRallyUpdate(ctx, s.pubsub, poll.RallyID, user.ID, model.RealtimePollDeleted, &model.RealtimeDeletedData{ID: poll.ID.Hex()})
+
+	return nil
+}
+
+// getPoll loads a poll and checks it belongs to the rally
+func (s *PollService) getPoll(ctx context.Context, rallyID string, pollID string) (*model.Poll, error) {
+	poll, err := s.pollRepo.GetPollByID(ctx, pollID)
+	if err != nil {
+		return nil, errors.New("poll not found")
+	}
+	if poll == nil || poll.RallyID.Hex() != rallyID {
+		return nil, errors.New("poll not found")
+	}
+	return poll, nil
+}
+
+// getVotablePoll loads a poll that still accepts votes in a rally that can still be edited
+func (s *PollService) getVotablePoll(ctx context.Context, rallyID string, pollID string) (*model.Poll, error) {
+	poll, err := s.getPoll(ctx, rallyID, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if !isPollVotingOpen(poll) {
+		return nil, errors.New("poll is closed")
+	}
+	if err := ensureRallyEditable(ctx, s.rallyRepo, rallyID); err != nil {
+		return nil, err
+	}
+	return poll, nil
+}
+
+// withVotablePoll runs apply (a vote change) in a transaction that first checks the poll still accepts
+// votes and writes it, so the change conflicts with a concurrent ClosePoll instead of being stored
+// after the votes were counted
+func (s *PollService) withVotablePoll(ctx context.Context, pollID primitive.ObjectID, apply func(sessCtx mongo.SessionContext) error) error {
+	session, err := s.db.Client().StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		votable, err := s.pollRepo.TouchVotablePoll(sessCtx, pollID, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("failed to check poll: %w", err)
+		}
+		if !votable {
+			return nil, errors.New("poll is closed")
+		}
+		return nil, apply(sessCtx)
+	})
+	return err
+}
+
+// getRallyEvent loads a live event and checks it belongs to the rally
+func (s *PollService) getRallyEvent(ctx context.Context, rallyID primitive.ObjectID, eventID string) (*model.Event, error) {
+	event, err := s.eventRepo.GetEventByID(ctx, eventID)
+	if err != nil || event == nil || event.RallyID != rallyID {
+		return nil, errors.New("event not found")
+	}
+	return event, nil
+}
+
+// publishResults reloads a poll's votes after the caller's vote changed, notifies the rally and returns
+// the new results
+func (s *PollService) publishResults(ctx context.Context, user *model.User, poll *model.Poll) (*model.PollResponse, error) {
+	votes, err := s.voteRepo.GetVotesByPoll(ctx, poll.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poll votes: %w", err)
+	}
+
+	publishRallyUpdate(ctx, s.pubsub, poll.RallyID, user.ID, model.RealtimePollUpdated, convertToPollResponse(poll, votes, nil))
+
+	return convertToPollResponse(poll, votes, &user.ID), nil
+}
+
+// isPollVotingOpen reports whether a poll still accepts votes: it is open and its deadline, if any,
+// has not passed
+func isPollVotingOpen(poll *model.Poll) bool {
+	return poll.Status == model.PollStatusOpen && (poll.Deadline == nil || time.Now().Before(*poll.Deadline))
+}
+
+// findPollOption returns the option of a poll with the given ID, or nil if there is none
+func findPollOption(poll *model.Poll, optionID string) *model.PollOption {
+	for i := range poll.Options {
+		if poll.Options[i].ID.Hex() == optionID {
+			return &poll.Options[i]
+		}
+	}
+	return nil
+}
+
+// pickPollWinner returns the option with the most votes. On a tie, optionID must name one of the tied
+// options; without it the poll closes without a winner, unless one is needed for an activity. A poll
+// nobody voted in has no winner.
+func pickPollWinner(poll *model.Poll, votes []model.PollVote, optionID string, winnerRequired bool) (*model.PollOption, error) {
+	counts := countPollVotes(votes)
+	most := 0
+	for _, option := range poll.Options {
+		most = max(most, counts[option.ID])
+	}
+
+	if most == 0 {
+		if optionID != "" {
+			return nil, errors.New("option is not a winning option")
+		}
+		if winnerRequired {
+			return nil, errors.New("poll has no votes")
+		}
+		return nil, nil
+	}
+
+	var leaders []*model.PollOption
+	for i := range poll.Options {
+		if counts[poll.Options[i].ID] == most {
+			leaders = append(leaders, &poll.Options[i])
+		}
+	}
+
+	if optionID != "" {
+		for _, option := range leaders {
+			if option.ID.Hex() == optionID {
+				return option, nil
+			}
+		}
+		return nil, errors.New("option is not a winning option")
+	}
+	if len(leaders) == 1 {
+		return leaders[0], nil
+	}
+	if winnerRequired {
+		return nil, errors.New("poll is tied; choose the winning option")
+	}
+	return nil, nil
+}
+
+// countPollVotes returns the number of votes each option received
+func countPollVotes(votes []model.PollVote) map[primitive.ObjectID]int {
+	counts := make(map[primitive.ObjectID]int)
+	for _, vote := range votes {
+		for _, optionID := range vote.OptionIDs {
+			counts[optionID]++
+		}
+	}
+	return counts
+}
+
+// convertToPollResponse converts a Poll model and its votes to PollResponse. Voters are left out of
+// anonymous polls, and the viewer's own vote is only filled in when viewerID is set.
+func convertToPollResponse(poll *model.Poll, votes []model.PollVote, viewerID *primitive.ObjectID) *model.PollResponse {
+	counts := countPollVotes(votes)
+	voters := make(map[primitive.ObjectID][]string)
+	var myVote []string
+	for _, vote := range votes {
+		if viewerID != nil && vote.UserID == *viewerID {
+			for _, optionID := range vote.OptionIDs {
+				myVote = append(myVote, optionID.Hex())
+			}
+		}
+		if !poll.Anonymous {
+			for _, optionID := range vote.OptionIDs {
+				voters[optionID] = append(voters[optionID], vote.UserID.Hex())
+			}
+		}
+	}
+
+	options := make([]model.PollOptionResponse, len(poll.Options))
+	for i, option := range poll.Options {
+		options[i] = model.PollOptionResponse{
+			ID:            option.ID.Hex(),
+			Text:          option.Text,
+			GooglePlaceID: option.GooglePlaceID,
+			Lat:           option.Lat,
+			Lng:           option.Lng,
+			VoteCount:     counts[option.ID],
+			Voters:        voters[option.ID],
+		}
+	}
+
+	resp := &model.PollResponse{
+		ID:             poll.ID.Hex(),
+		RallyID:        poll.RallyID.Hex(),
+		Question:       poll.Question,
+		Options:        options,
+		MultipleChoice: poll.MultipleChoice,
+		Anonymous:      poll.Anonymous,
+		Deadline:       poll.Deadline,
+		Status:         poll.Status,
+		VotingOpen:     isPollVotingOpen(poll),
+		TotalVoters:    len(votes),
+		MyVote:         myVote,
+		ClosedAt:       poll.ClosedAt,
+		CreatedBy:      poll.CreatedBy.Hex(),
+		CreatedAt:      poll.CreatedAt,
+		UpdatedAt:      poll.UpdatedAt,
+	}
+	if poll.EventID != nil {
+		resp.EventID = poll.EventID.Hex()
+	}
+	if poll.WinningOptionID != nil {
+		resp.WinningOptionID = poll.WinningOptionID.Hex()
+	}
+	if poll.ActivityID != nil {
+		resp.ActivityID = poll.ActivityID.Hex()
+	}
+	if poll.ClosedBy != nil {
+		resp.ClosedBy = poll.ClosedBy.Hex()
+	}
+	return resp
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/infrastructure/push"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// pushTemplate is the localized title and body of a push notification. Placeholders in braces are
+// filled in from the notification: {actor}, {rally}, {event} and {role}.
+type pushTemplate struct {
+	Title string
+	Body  string
+}
+
+// defaultPushLanguage is used for devices whose locale has no templates
+const defaultPushLanguage = "en"
+
+// pushTemplates holds the push text of every notification type that is pushed, by language.
+// Types without a template (e.g. follows) stay in the in-app notification center only.
+var pushTemplates = map[string]map[model.NotificationType]pushTemplate{
+	"en": {
+		model.NotificationTypeRallyInvitation:  {Title: "Rally invitation", Body: "{actor} invited you to {rally}"},
+		model.NotificationTypeRoleChanged:      {Title: "Role updated", Body: "You are now {role} in {rally}"},
+		model.NotificationTypeJoinedViaLink:    {Title: "New participant", Body: "{actor} joined {rally} through your invite link"},
+		model.NotificationTypeJoinRequest:      {Title: "Join request", Body: "{actor} asked to join {rally}"},
+		model.NotificationTypeEventTimeChanged: {Title: "Schedule change", Body: "{event} in {rally} was rescheduled"},
+	},
+	"vi": {
+		model.NotificationTypeRallyInvitation:  {Title: "Lời mời tham gia rally", Body: "{actor} đã mời bạn tham gia {rally}"},
+		model.NotificationTypeRoleChanged:      {Title: "Vai trò đã thay đổi", Body: "Bạn hiện là {role} trong {rally}"},
+		model.NotificationTypeJoinedViaLink:    {Title: "Thành viên mới", Body: "{actor} đã tham gia {rally} qua liên kết mời của bạn"},
+		model.NotificationTypeJoinRequest:      {Title: "Yêu cầu tham gia", Body: "{actor} muốn tham gia {rally}"},
+		model.NotificationTypeEventTimeChanged: {Title: "Thay đổi lịch trình", Body: "{event} trong {rally} đã được dời lịch"},
+	},
+}
+
+// PushDispatcher sends notifications to the registered devices of their recipients
+type PushDispatcher struct {
+	sender     push.Sender
+	deviceRepo repository.DeviceRepository
+	userRepo   repository.UserRepository
+	rallyRepo  repository.RallyRepository
+}
+
+func NewPushDispatcher(
+	sender push.Sender,
+	deviceRepo repository.DeviceRepository,
+	userRepo repository.UserRepository,
+	rallyRepo repository.RallyRepository,
+) *PushDispatcher {
+	return &PushDispatcher{
+		sender:     sender,
+		deviceRepo: deviceRepo,
+		userRepo:   userRepo,
+		rallyRepo:  rallyRepo,
+	}
+}
+
+// Dispatch pushes notifications to every device of their recipients, in each device's language, and
+// prunes the tokens the push service rejects as invalid. Failures are logged, never returned.
+func (d *PushDispatcher) Dispatch(ctx context.Context, notifications []*model.Notification) {
+	var recipientIDs []primitive.ObjectID
+	seen := make(map[primitive.ObjectID]bool)
+	for _, notification := range notifications {
+		if _, ok := pushTemplates[defaultPushLanguage][notification.Type]; !ok || seen[notification.UserID] {
+			continue
+		}
+		seen[notification.UserID] = true
+		recipientIDs = append(recipientIDs, notification.UserID)
+	}
+	if len(recipientIDs) == 0 {
+		return
+	}
+
+	devices, err := d.deviceRepo.GetDevicesByUsers(ctx, recipientIDs)
+	if err != nil {
+		log.Printf("[push] failed to load devices: %v", err)
+		return
+	}
+	devicesByUser := make(map[primitive.ObjectID][]*model.Device)
+	for _, device := range devices {
+		devicesByUser[device.UserID] = append(devicesByUser[device.UserID], device)
+	}
+
+	actorNames := make(map[primitive.ObjectID]string)
+	rallyNames := make(map[primitive.ObjectID]string)
+
+	var messages []*push.Message
+	for _, notification := range notifications {
+		userDevices := devicesByUser[notification.UserID]
+		if _, ok := pushTemplates[defaultPushLanguage][notification.Type]; !ok || len(userDevices) == 0 {
+			continue
+		}
+
+		replacer := strings.NewReplacer(
+			"{actor}", d.actorName(ctx, notification.ActorID, actorNames),
+			"{rally}", d.rallyName(ctx, notification.RallyID, rallyNames),
+			"{event}", fmt.Sprint(notification.Data["eventName"]),
+			"{role}", fmt.Sprint(notification.Data["role"]),
+		)
+		data := pushData(notification)
+
+		for _, device := range userDevices {
+			template := pushTemplates[pushLanguage(device.Locale)][notification.Type]
+			messages = append(messages, &push.Message{
+				Token: device.Token,
+				Title: replacer.Replace(template.Title),
+				Body:  replacer.Replace(template.Body),
+				Data:  data,
+			})
+		}
+	}
+	if len(messages) == 0 {
+		return
+	}
+
+	invalidTokens, err := d.sender.Send(ctx, messages)
+	if err != nil {
+		log.Printf("[push] failed to send %d push notifications: %v", len(messages), err)
+	}
+	if len(invalidTokens) > 0 {
+		pruned, err := d.deviceRepo.DeleteDevicesByTokens(ctx, invalidTokens)
+		if err != nil {
+			log.Printf("[push] failed to prune %d invalid tokens: %v", len(invalidTokens), err)
+			return
+		}
+		log.Printf("[push] pruned %d invalid tokens", pruned)
+	}
+}
+
+// actorName resolves the username shown for a notification's actor, caching lookups in names
+func (d *PushDispatcher) actorName(ctx context.Context, actorID *primitive.ObjectID, names map[primitive.ObjectID]string) string {
+	if actorID == nil {
+		return "Someone"
+	}
+	if name, ok := names[*actorID]; ok {
+		return name
+	}
+
+	name := "Someone"
+	if actor, err := d.userRepo.GetUserByID(ctx, actorID.Hex()); err == nil && actor != nil {
+		name = actor.Username
+	}
+	names[*actorID] = name
+	return name
+}
+
+// rallyName resolves the name of a notification's rally, caching lookups in names
+func (d *PushDispatcher) rallyName(ctx context.Context, rallyID *primitive.ObjectID, names map[primitive.ObjectID]string) string {
+	if rallyID == nil {
+		return ""
+	}
+	if name, ok := names[*rallyID]; ok {
+		return name
+	}
+
+	name := "a rally"
+	if rally, err := d.rallyRepo.GetRallyByID(ctx, rallyID.Hex()); err == nil && rally != nil {
+		name = rally.Name
+	}
+	names[*rallyID] = name
+	return name
+}
+
+// pushData is the payload the app uses to open the notification's target when it is tapped
+func pushData(notification *model.Notification) map[string]string {
+	data := map[string]string{
+		"type":           string(notification.Type),
+		"notificationId": notification.ID.Hex(),
+	}
+	if notification.RallyID != nil {
+		data["rallyId"] = notification.RallyID.Hex()
+	}
+	if notification.EventID != nil {
+		data["eventId"] = notification.EventID.Hex()
+	}
+	return data
+}
+
+// pushLanguage picks the template language for a locale such as "vi-VN" (or Android's "vi_VN"),
+// falling back to English
+func pushLanguage(locale string) string {
+	language := strings.ToLower(strings.SplitN(strings.ReplaceAll(locale, "_", "-"), "-", 2)[0])
+	if _, ok := pushTemplates[language]; ok {
+		return language
+	}
+	return defaultPushLanguage
+}
@@ -4,35 +4,62 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"firebase.google.com/go/v4/auth"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/infrastructure/mailer"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/realtime"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/repository"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/utils"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type RallyParticipantService struct {
+	db              *mongo.Database
 	firebaseAuth    *auth.Client
 	participantRepo repository.RallyParticipantRepository
 	rallyRepo       repository.RallyRepository
 	userRepo        repository.UserRepository
 	followRepo      repository.FollowRepository
+	emailInviteRepo repository.EmailInvitationRepository
+	auditRepo       repository.AuditLogRepository
+	notifications   *NotificationService
+	pubsub          realtime.PubSub
+	mailer          mailer.Mailer
+	appURL          string
 }
 
 func NewRallyParticipantService(
+	db *mongo.Database,
 	firebaseAuth *auth.Client,
 	participantRepo repository.RallyParticipantRepository,
 	rallyRepo repository.RallyRepository,
 	userRepo repository.UserRepository,
 	followRepo repository.FollowRepository,
+	emailInviteRepo repository.EmailInvitationRepository,
+	auditRepo repository.AuditLogRepository,
+	notifications *NotificationService,
+	pubsub realtime.PubSub,
+	mailer mailer.Mailer,
+	appURL string,
 ) *RallyParticipantService {
 	return &RallyParticipantService{
+		db:              db,
 		firebaseAuth:    firebaseAuth,
 		participantRepo: participantRepo,
 		rallyRepo:       rallyRepo,
 		userRepo:        userRepo,
 		followRepo:      followRepo,
+		emailInviteRepo: emailInviteRepo,
+		auditRepo:       auditRepo,
+		notifications:   notifications,
+		pubsub:          pubsub,
+		mailer:          mailer,
+		appURL:          appURL,
 	}
 }
 
@@ -67,6 +94,9 @@ func (s *RallyParticipantService) InviteParticipant(ctx context.Context, user *m
 		return nil, fmt.Errorf("failed to check existing participant: %w", err)
 	}
 	if existing != nil {
+		if existing.Status == model.ParticipationStatusBanned {
+			return nil, errors.New("user is banned from this rally")
+		}
 		return nil, errors.New("user is already a participant")
 	}
 
@@ -91,7 +121,177 @@ func (s *RallyParticipantService) InviteParticipant(ctx context.Context, user *m
 		return nil, fmt.Errorf("failed to create participant: %w", err)
 	}
 
-	return s.ConvertToParticipantResponse(participant), nil
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    rallyObjID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionCreate,
+		TargetType: model.AuditTargetParticipant,
+		TargetID:   participant.ID,
+		Changes:    diffFields(nil, participant),
+	})
+
+	s.notifications.Notify(ctx, &model.Notification{
+		UserID:  participant.UserID,
+		Type:    model.NotificationTypeRallyInvitation,
+		ActorID: &user.ID,
+		RallyID: &participant.RallyID,
+		Data:    map[string]interface{}{"role": participant.Role},
+	})
+
+	resp := s.ConvertToParticipantResponse(participant)
+	publishParticipantUpdate(ctx, s.pubsub, participant.RallyID, user.ID, participant.UserID, participant.Status, model.RealtimeParticipantAdded, resp)
+
+	return resp, nil
+}
+
+// InviteByEmail invites someone to a rally by email address (middleware ensures owner or editor role).
+// An address that belongs to a registered user is invited exactly like InviteParticipant. Otherwise a
+// pending email invitation is stored under the normalized address, to be claimed when that person
+// registers, and an invitation email is sent.
+func (s *RallyParticipantService) InviteByEmail(ctx context.Context, user *model.User, rallyID string, req *model.InviteParticipantRequest) (*model.RallyParticipantResponse, *model.EmailInvitationResponse, error) {
+	email := utils.NormalizeEmail(req.Email)
+	if !utils.IsValidEmail(email) {
+		return nil, nil, errors.New("invalid email address")
+	}
+
+	targetUser, err := s.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get target user: %w", err)
+	}
+	if targetUser != nil {
+		byUserID := &model.InviteParticipantRequest{UserID: targetUser.ID.Hex(), Role: req.Role}
+		participant, err := s.InviteParticipant(ctx, user, rallyID, byUserID)
+		return participant, nil, err
+	}
+
+	// Verify rally exists
+	rally, err := s.rallyRepo.GetRallyByID(ctx, rallyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get rally: %w", err)
+	}
+	if rally == nil {
+		return nil, nil, errors.New("rally not found")
+	}
+
+	existing, err := s.emailInviteRepo.GetEmailInvitationByRallyAndEmail(ctx, rally.ID, email)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to check existing email invitation: %w", err)
+	}
+	if existing != nil {
+		return nil, nil, errors.New("email is already invited")
+	}
+
+	// Default role to "participant" if not specified
+	role := model.ParticipantRoleParticipant
+	if req.Role != nil && *req.Role != "" {
+		role = *req.Role
+	}
+
+	invitation := &model.EmailInvitation{
+		RallyID:   rally.ID,
+		Email:     email,
+		Role:      role,
+		InvitedBy: user.ID,
+	}
+
+	if err := s.emailInviteRepo.CreateEmailInvitation(ctx, invitation); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			// A concurrent invite to the same address got there first
+			return nil, nil, errors.New("email is already invited")
+		}
+		return nil, nil, fmt.Errorf("failed to create email invitation: %w", err)
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    rally.ID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionCreate,
+		TargetType: model.AuditTargetEmailInvitation,
+		TargetID:   invitation.ID,
+		Changes:    diffFields(nil, invitation),
+	})
+
+	// The invitation is stored either way, so a delivery failure is logged rather than returned
+	if err := s.mailer.Send(ctx, s.buildInvitationEmail(user, rally, email)); err != nil {
+		log.Printf("[mailer] failed to send rally invitation to %s: %v", email, err)
+	}
+
+	return nil, convertToEmailInvitationResponse(invitation), nil
+}
+
+// GetEmailInvitations lists the outstanding invitations of a rally sent to unregistered email
+// addresses (middleware ensures owner or editor role)
+func (s *RallyParticipantService) GetEmailInvitations(ctx context.Context, rallyID string) ([]*model.EmailInvitationResponse, error) {
+	rallyObjID, err := primitive.ObjectIDFromHex(rallyID)
+	if err != nil {
+		return nil, errors.New("invalid rally ID")
+	}
+
+	invitations, err := s.emailInviteRepo.GetEmailInvitationsByRally(ctx, rallyObjID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email invitations: %w", err)
+	}
+
+	responses := make([]*model.EmailInvitationResponse, len(invitations))
+	for i, invitation := range invitations {
+		responses[i] = convertToEmailInvitationResponse(invitation)
+	}
+
+	return responses, nil
+}
+
+// CancelEmailInvitation withdraws an invitation sent to an unregistered email address
+// (middleware ensures owner or editor role)
+func (s *RallyParticipantService) CancelEmailInvitation(ctx context.Context, user *model.User, rallyID string, invitationID string) error {
+	if !primitive.IsValidObjectID(invitationID) {
+		return errors.New("email invitation not found")
+	}
+
+	invitation, err := s.emailInviteRepo.GetEmailInvitation(ctx, invitationID)
+	if err != nil {
+		return fmt.Errorf("failed to get email invitation: %w", err)
+	}
+	if invitation == nil || invitation.RallyID.Hex() != rallyID {
+		return errors.New("email invitation not found")
+	}
+
+	if err := s.emailInviteRepo.DeleteEmailInvitation(ctx, invitation.ID); err != nil {
+		return fmt.Errorf("failed to delete email invitation: %w", err)
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    invitation.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionDelete,
+		TargetType: model.AuditTargetEmailInvitation,
+		TargetID:   invitation.ID,
+		Changes:    diffFields(invitation, nil),
+	})
+
+	return nil
+}
+
+// buildInvitationEmail composes the email sent to someone invited to a rally before they have an account
+func (s *RallyParticipantService) buildInvitationEmail(inviter *model.User, rally *model.Rally, email string) *mailer.Message {
+	name := strings.TrimSpace(inviter.FirstName + " " + inviter.LastName)
+	if name == "" {
+		name = inviter.Username
+	}
+	if name == "" {
+		name = "A friend"
+	}
+	// Names are user-controlled; collapse any line breaks so they stay on one line of the email
+	name = strings.Join(strings.Fields(name), " ")
+	rallyName := strings.Join(strings.Fields(rally.Name), " ")
+
+	return &mailer.Message{
+		To:      email,
+		Subject: fmt.Sprintf("%s invited you to %s on Rally", name, rallyName),
+		Body: fmt.Sprintf(
+			"%s invited you to join the rally \"%s\".\n\nSign up at %s with this email address (%s) and the invitation will be waiting for you.\n",
+			name, rallyName, s.appURL, email,
+		),
+	}
 }
 
 // UpdateParticipant updates a participant's role or status
@@ -101,7 +301,7 @@ func (s *RallyParticipantService) UpdateParticipant(ctx context.Context, user *m
 	if err != nil {
 		return nil, fmt.Errorf("failed to get participant: %w", err)
 	}
-	if participant == nil {
+	if participant == nil || participant.RallyID.Hex() != rallyID {
 		return nil, errors.New("participant not found")
 	}
 
@@ -114,6 +314,13 @@ func (s *RallyParticipantService) UpdateParticipant(ctx context.Context, user *m
 
 	// Status changes: allowed for the participant themselves or owner/editor
 	if req.Status != nil {
+		// Bans go through RemoveParticipant so the role hierarchy is applied, and are lifted by removing the record
+		if *req.Status == model.ParticipationStatusBanned || participant.Status == model.ParticipationStatusBanned {
+			return nil, errors.New("banned status can only be changed by removing the participant")
+		}
+		if *req.Status == model.ParticipationStatusPending || participant.Status == model.ParticipationStatusPending {
+			return nil, errors.New("join requests can only be approved or rejected")
+		}
 		isSelf := user.ID == participant.UserID
 		if !isSelf {
 			if callerParticipant.Status != model.ParticipationStatusJoined {
@@ -122,19 +329,298 @@ func (s *RallyParticipantService) UpdateParticipant(ctx context.Context, user *m
 			if callerParticipant.Role != model.ParticipantRoleOwner && callerParticipant.Role != model.ParticipantRoleEditor {
 				return nil, errors.New("unauthorized: insufficient permissions")
 			}
+			// Same hierarchy as RemoveParticipant: editors can only change the status of lower roles
+			if callerParticipant.Role != model.ParticipantRoleOwner && participantRoleRank[participant.Role] >= participantRoleRank[callerParticipant.Role] {
+				return nil, errors.New("unauthorized: insufficient permissions")
+			}
 		}
 	}
 
-	updated, err := s.participantRepo.UpdateParticipant(ctx, participantID, req)
+	var updated *model.RallyParticipant
+	if losesJoinedOwnership(participant, req.Role, req.Status) {
+		// Demoting, leaving or declining as an owner must leave another joined owner behind
+		err = s.withOwnerSuccession(ctx, user, participant, func(sessCtx mongo.SessionContext) error {
+			var err error
+			updated, err = s.participantRepo.UpdateParticipant(sessCtx, participantID, req)
+			return err
+		})
+	} else {
+		updated, err = s.participantRepo.UpdateParticipant(ctx, participantID, req)
+	}
 	if err != nil {
+		if err.Error() == "rally must have at least one joined owner" {
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to update participant: %w", err)
 	}
 
-	return s.ConvertToParticipantResponse(updated), nil
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    participant.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionUpdate,
+		TargetType: model.AuditTargetParticipant,
+		TargetID:   participant.ID,
+		Changes:    diffFields(participant, updated),
+	})
+
+	if updated.Role != participant.Role {
+		s.notifications.Notify(ctx, roleChangedNotification(user.ID, participant, updated))
+	}
+
+	resp := s.ConvertToParticipantResponse(updated)
+	publishParticipantUpdate(ctx, s.pubsub, updated.RallyID, user.ID, updated.UserID, updated.Status, model.RealtimeParticipantUpdated, resp)
+
+	return resp, nil
+}
+
+// RemoveParticipant kicks a participant out of a rally by deleting their record, so they may rejoin later.
+// With ban set, the record is kept with the banned status instead, which blocks invitations and invite
+// links. Removing a banned participant lifts the ban. Editors may only remove plain participants
+// (middleware ensures joined owner or editor).
+func (s *RallyParticipantService) RemoveParticipant(ctx context.Context, user *model.User, callerParticipant *model.RallyParticipant, rallyID string, participantID string, ban bool) error {
+	participant, err := s.participantRepo.GetParticipant(ctx, participantID)
+	if err != nil {
+		return fmt.Errorf("failed to get participant: %w", err)
+	}
+	if participant == nil || participant.RallyID.Hex() != rallyID {
+		return errors.New("participant not found")
+	}
+
+	if participant.ID == callerParticipant.ID {
+		return errors.New("cannot remove yourself from a rally")
+	}
+	if callerParticipant.Role != model.ParticipantRoleOwner && participantRoleRank[participant.Role] >= participantRoleRank[callerParticipant.Role] {
+		return errors.New("unauthorized: insufficient permissions")
+	}
+
+	var updated *model.RallyParticipant
+	remove := func(ctx context.Context) error {
+		if ban {
+			bannedStatus := model.ParticipationStatusBanned
+			var err error
+			updated, err = s.participantRepo.UpdateParticipant(ctx, participantID, &model.UpdateParticipantRequest{Status: &bannedStatus})
+			return err
+		}
+		return s.participantRepo.DeleteParticipant(ctx, participantID)
+	}
+
+	if participant.Role == model.ParticipantRoleOwner && participant.Status == model.ParticipationStatusJoined {
+		err = s.withOwnerSuccession(ctx, user, participant, func(sessCtx mongo.SessionContext) error {
+			return remove(sessCtx)
+		})
+	} else {
+		err = remove(ctx)
+	}
+	if err != nil {
+		if err.Error() == "rally must have at least one joined owner" {
+			return err
+		}
+		return fmt.Errorf("failed to remove participant: %w", err)
+	}
+
+	entry := &model.AuditLog{
+		RallyID:    participant.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionDelete,
+		TargetType: model.AuditTargetParticipant,
+		TargetID:   participant.ID,
+		Changes:    diffFields(participant, nil),
+	}
+	if ban {
+		entry.Action = model.AuditActionUpdate
+		entry.Changes = diffFields(participant, updated)
+	}
+	recordAudit(ctx, s.auditRepo, entry)
+
+	// A ban also takes the participant out of the rally, so both cases read as a removal to clients
+	var status model.ParticipationStatus
+	if updated != nil {
+		status = updated.Status
+	}
+	publishParticipantUpdate(ctx, s.pubsub, participant.RallyID, user.ID, participant.UserID, status, model.RealtimeParticipantRemoved, &model.RealtimeDeletedData{
+		ID:     participant.ID.Hex(),
+		UserID: participant.UserID.Hex(),
+	})
+
+	return nil
+}
+
+// TransferOwnership hands a rally over to another joined participant in a single transaction: the
+// target is promoted to owner, Rally.OwnerID is pointed at them and the caller is demoted to editor
+// (middleware ensures joined owner)
+func (s *RallyParticipantService) TransferOwnership(ctx context.Context, user *model.User, callerParticipant *model.RallyParticipant, rallyID string, req *model.TransferOwnershipRequest) (*model.TransferOwnershipResponse, error) {
+
+	rally, err := s.rallyRepo.GetRallyByID(ctx, rallyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rally: %w", err)
+	}
+	if rally == nil {
+		return nil, errors.New("rally not found")
+	}
+
+	session, err := s.db.Client().StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	ownerRole := model.ParticipantRoleOwner
+	editorRole := model.ParticipantRoleEditor
+
+	var target, newOwner, previousOwner *model.RallyParticipant
+	var updatedRally *model.Rally
+
+	// The target is checked and promoted inside the transaction, behind the ownership guard, so a
+	// target leaving or being removed at the same time cannot end up as the only owner
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		if err := s.rallyRepo.TouchOwnershipGuard(sessCtx, rally.ID); err != nil {
+			return nil, fmt.Errorf("failed to lock rally ownership: %w", err)
+		}
+
+		var err error
+		target, err = s.participantRepo.GetParticipant(sessCtx, req.ParticipantID)
+		if err != nil || target == nil || target.RallyID != rally.ID {
+			return nil, errors.New("participant not found")
+		}
+		if target.ID == callerParticipant.ID {
+			return nil, errors.New("cannot transfer ownership to yourself")
+		}
+		if target.Status != model.ParticipationStatusJoined {
+			return nil, errors.New("target participant has not joined the rally")
+		}
+
+		newOwner, err = s.participantRepo.UpdateParticipantWithStatus(sessCtx, target.ID, model.ParticipationStatusJoined, &model.UpdateParticipantRequest{Role: &ownerRole})
+		if err != nil {
+			return nil, fmt.Errorf("failed to promote participant: %w", err)
+		}
+		if newOwner == nil {
+			return nil, errors.New("target participant has not joined the rally")
+		}
+		updatedRally, err = s.rallyRepo.SetRallyOwner(sessCtx, rally.ID, target.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update rally owner: %w", err)
+		}
+		previousOwner, err = s.participantRepo.UpdateParticipantWithStatus(sessCtx, callerParticipant.ID, model.ParticipationStatusJoined, &model.UpdateParticipantRequest{Role: &editorRole})
+		if err != nil {
+			return nil, fmt.Errorf("failed to demote owner: %w", err)
+		}
+		if previousOwner == nil {
+			return nil, errors.New("unauthorized: insufficient permissions")
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range []*model.AuditLog{
+		{TargetType: model.AuditTargetParticipant, TargetID: target.ID, Changes: diffFields(target, newOwner)},
+		{TargetType: model.AuditTargetRally, TargetID: rally.ID, Changes: diffFields(rally, updatedRally)},
+		{TargetType: model.AuditTargetParticipant, TargetID: callerParticipant.ID, Changes: diffFields(callerParticipant, previousOwner)},
+	} {
+		entry.RallyID = rally.ID
+		entry.ActorID = user.ID
+		entry.Action = model.AuditActionUpdate
+		recordAudit(ctx, s.auditRepo, entry)
+	}
+
+	s.notifications.Notify(ctx, roleChangedNotification(user.ID, target, newOwner))
+
+	resp := &model.TransferOwnershipResponse{
+		RallyID:       rally.ID.Hex(),
+		OwnerID:       updatedRally.OwnerID.Hex(),
+		NewOwner:      *s.ConvertToParticipantResponse(newOwner),
+		PreviousOwner: *s.ConvertToParticipantResponse(previousOwner),
+	}
+	publishParticipantUpdate(ctx, s.pubsub, rally.ID, user.ID, newOwner.UserID, newOwner.Status, model.RealtimeParticipantUpdated, &resp.NewOwner)
+	publishParticipantUpdate(ctx, s.pubsub, rally.ID, user.ID, previousOwner.UserID, previousOwner.Status, model.RealtimeParticipantUpdated, &resp.PreviousOwner)
+
+	return resp, nil
+}
+
+// losesJoinedOwnership reports whether applying the given role/status to a participant would take
+// away a joined owner from the rally
+func losesJoinedOwnership(p *model.RallyParticipant, role *model.ParticipantRole, status *model.ParticipationStatus) bool {
+	if p.Role != model.ParticipantRoleOwner || p.Status != model.ParticipationStatusJoined {
+		return false
+	}
+	return (role != nil && *role != model.ParticipantRoleOwner) ||
+		(status != nil && *status != model.ParticipationStatusJoined)
+}
+
+// withOwnerSuccession runs apply, which removes a joined owner from the rally, in a transaction that
+// first checks another joined owner remains. If the departing owner is the rally's OwnerID, ownership
+// passes to the longest-standing remaining owner. The transaction writes the rally's ownership guard
+// before the check, so two owners leaving at once conflict and the retry sees the other departure.
+func (s *RallyParticipantService) withOwnerSuccession(ctx context.Context, user *model.User, departing *model.RallyParticipant, apply func(sessCtx mongo.SessionContext) error) error {
+
+	rally, err := s.rallyRepo.GetRallyByID(ctx, departing.RallyID.Hex())
+	if err != nil {
+		return fmt.Errorf("failed to get rally: %w", err)
+	}
+	if rally == nil {
+		return errors.New("rally not found")
+	}
+
+	session, err := s.db.Client().StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	var updatedRally *model.Rally
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		if err := s.rallyRepo.TouchOwnershipGuard(sessCtx, departing.RallyID); err != nil {
+			return nil, fmt.Errorf("failed to lock rally ownership: %w", err)
+		}
+
+		successor, err := s.participantRepo.FindOtherJoinedOwner(sessCtx, departing.RallyID, departing.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find remaining owner: %w", err)
+		}
+		if successor == nil {
+			return nil, errors.New("rally must have at least one joined owner")
+		}
+
+		if err := apply(sessCtx); err != nil {
+			return nil, err
+		}
+
+		if rally.OwnerID == departing.UserID {
+			updatedRally, err = s.rallyRepo.SetRallyOwner(sessCtx, rally.ID, successor.UserID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to update rally owner: %w", err)
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if updatedRally != nil {
+		recordAudit(ctx, s.auditRepo, &model.AuditLog{
+			RallyID:    rally.ID,
+			ActorID:    user.ID,
+			Action:     model.AuditActionUpdate,
+			TargetType: model.AuditTargetRally,
+			TargetID:   rally.ID,
+			Changes:    diffFields(rally, updatedRally),
+		})
+	}
+
+	return nil
 }
 
 // ConvertToParticipantResponse converts a RallyParticipant model to RallyParticipantResponse
 func (s *RallyParticipantService) ConvertToParticipantResponse(p *model.RallyParticipant) *model.RallyParticipantResponse {
+	return convertToParticipantResponse(p)
+}
+
+// convertToParticipantResponse converts a RallyParticipant model to RallyParticipantResponse.
+// Shared with InviteLinkService, which publishes participants who join through a link.
+func convertToParticipantResponse(p *model.RallyParticipant) *model.RallyParticipantResponse {
 	invitedBy := ""
 	if p.InvitedBy != nil {
 		invitedBy = p.InvitedBy.Hex()
@@ -159,7 +645,7 @@ func (s *RallyParticipantService) GetParticipantsList(ctx context.Context, rally
 		return nil, errors.New("invalid rally ID")
 	}
 
-	participants, total, err := s.participantRepo.GetParticipantsList(ctx, rallyObjID, role, page, pageSize)
+	participants, total, err := s.participantRepo.GetParticipantsList(ctx, rallyObjID, role, "", page, pageSize)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get participants list: %w", err)
 	}
@@ -179,8 +665,111 @@ func (s *RallyParticipantService) GetParticipantsList(ctx context.Context, rally
 	}, nil
 }
 
+// GetJoinRequests retrieves a paginated list of pending join requests for a rally, newest first
+// (middleware ensures owner/editor)
+func (s *RallyParticipantService) GetJoinRequests(ctx context.Context, rallyID string, page, pageSize int) (*model.ParticipantListResponse, error) {
+	rallyObjID, err := primitive.ObjectIDFromHex(rallyID)
+	if err != nil {
+		return nil, errors.New("invalid rally ID")
+	}
+
+	requests, total, err := s.participantRepo.GetParticipantsList(ctx, rallyObjID, "", string(model.ParticipationStatusPending), page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get join requests: %w", err)
+	}
+
+	totalPages := utils.CalcTotalPages(total, pageSize)
+
+	return &model.ParticipantListResponse{
+		Participants: requests,
+		Total:        int(total),
+		Page:         page,
+		PageSize:     pageSize,
+		TotalPages:   totalPages,
+		Pagination: model.PaginationMetadata{
+			HasNextPage:     page < totalPages,
+			HasPreviousPage: page > 1,
+		},
+	}, nil
+}
+
+// ApproveJoinRequest lets a pending requester into the rally with the role of the link they used.
+// Only owners can approve requests for owner/editor roles, same as creating such links. The request
+// is only approved while it is still pending, so it cannot be approved twice or after being
+// withdrawn (middleware ensures owner/editor).
+func (s *RallyParticipantService) ApproveJoinRequest(ctx context.Context, user *model.User, callerParticipant *model.RallyParticipant, rallyID string, participantID string) (*model.RallyParticipantResponse, error) {
+	request, err := s.getJoinRequest(ctx, rallyID, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if participantRoleRank[request.Role] > participantRoleRank[model.ParticipantRoleParticipant] && callerParticipant.Role != model.ParticipantRoleOwner {
+		return nil, errors.New("only owners can approve join requests for owner/editor roles")
+	}
+
+	joinedStatus := model.ParticipationStatusJoined
+	updated, err := s.participantRepo.UpdateParticipantWithStatus(ctx, request.ID, model.ParticipationStatusPending, &model.UpdateParticipantRequest{Status: &joinedStatus})
+	if err != nil {
+		return nil, fmt.Errorf("failed to approve join request: %w", err)
+	}
+	if updated == nil {
+		return nil, errors.New("join request not found")
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    request.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionUpdate,
+		TargetType: model.AuditTargetParticipant,
+		TargetID:   request.ID,
+		Changes:    diffFields(request, updated),
+	})
+
+	resp := s.ConvertToParticipantResponse(updated)
+	publishParticipantUpdate(ctx, s.pubsub, updated.RallyID, user.ID, updated.UserID, updated.Status, model.RealtimeParticipantUpdated, resp)
+
+	return resp, nil
+}
+
+// RejectJoinRequest discards a pending join request; the requester may ask again through an active link
+// (middleware ensures owner/editor)
+func (s *RallyParticipantService) RejectJoinRequest(ctx context.Context, user *model.User, rallyID string, participantID string) error {
+	request, err := s.getJoinRequest(ctx, rallyID, participantID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.participantRepo.DeleteParticipant(ctx, participantID); err != nil {
+		return fmt.Errorf("failed to reject join request: %w", err)
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    request.RallyID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionDelete,
+		TargetType: model.AuditTargetParticipant,
+		TargetID:   request.ID,
+		Changes:    diffFields(request, nil),
+	})
+
+	return nil
+}
+
+// getJoinRequest loads a participant record and checks it is a pending join request of the rally
+func (s *RallyParticipantService) getJoinRequest(ctx context.Context, rallyID string, participantID string) (*model.RallyParticipant, error) {
+	participant, err := s.participantRepo.GetParticipant(ctx, participantID)
+	if err != nil {
+		return nil, errors.New("join request not found")
+	}
+	if participant == nil || participant.RallyID.Hex() != rallyID || participant.Status != model.ParticipationStatusPending {
+		return nil, errors.New("join request not found")
+	}
+	return participant, nil
+}
+
 // GetPendingInvitations retrieves all pending ("invited" status) invitations for the authenticated user.
-// TODO: This is a temporary endpoint until realtime notifications are implemented.
+// The invitation notifications served by GET /user/me/notifications only record that an invitation was
+// sent; this list is what is still open to accept or decline.
 func (s *RallyParticipantService) GetPendingInvitations(ctx context.Context, idToken string) (*model.PendingInvitationsResponse, error) {
 	user, err := authenticateUser(ctx, s.firebaseAuth, s.userRepo, idToken)
 	if err != nil {
@@ -229,3 +818,50 @@ func (s *RallyParticipantService) GetInvitableFriends(ctx context.Context, user
 		TotalPages: totalPages,
 	}, nil
 }
+
+// ExpireStaleInvitations removes invitations that were never answered and were sent before
+// invitedBefore, so they drop out of the invitee's pending list and the user can be invited again.
+// Unclaimed email invitations expire on the same schedule.
+func (s *RallyParticipantService) ExpireStaleInvitations(ctx context.Context, invitedBefore time.Time) error {
+
+	expired, err := s.participantRepo.DeleteStaleInvitations(ctx, invitedBefore)
+	if err != nil {
+		return fmt.Errorf("failed to expire invitations: %w", err)
+	}
+
+	expiredEmails, err := s.emailInviteRepo.DeleteStaleEmailInvitations(ctx, invitedBefore)
+	if err != nil {
+		return fmt.Errorf("failed to expire email invitations: %w", err)
+	}
+
+	if expired > 0 || expiredEmails > 0 {
+		log.Printf("[invitations] expired %d stale invitations and %d email invitations", expired, expiredEmails)
+	}
+
+	return nil
+}
+
+func convertToEmailInvitationResponse(invitation *model.EmailInvitation) *model.EmailInvitationResponse {
+	return &model.EmailInvitationResponse{
+		ID:        invitation.ID.Hex(),
+		RallyID:   invitation.RallyID.Hex(),
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		InvitedBy: invitation.InvitedBy.Hex(),
+		InvitedAt: invitation.InvitedAt,
+	}
+}
+
+// roleChangedNotification tells a participant that their role in a rally changed from before to after
+func roleChangedNotification(actorID primitive.ObjectID, before, after *model.RallyParticipant) *model.Notification {
+	return &model.Notification{
+		UserID:  after.UserID,
+		Type:    model.NotificationTypeRoleChanged,
+		ActorID: &actorID,
+		RallyID: &after.RallyID,
+		Data: map[string]interface{}{
+			"role":         after.Role,
+			"previousRole": before.Role,
+		},
+	}
+}
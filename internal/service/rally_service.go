@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"firebase.google.com/go/v4/auth"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/realtime"
 	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/repository"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/utils"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -17,29 +20,76 @@ type RallyService struct {
 	db              *mongo.Database
 	firebaseAuth    *auth.Client
 	rallyRepo       repository.RallyRepository
+	eventRepo       repository.EventRepository
+	activityRepo    repository.ActivityRepository
 	participantRepo repository.RallyParticipantRepository
+	inviteLinkRepo  repository.InviteLinkRepository
+	redemptionRepo  repository.InviteLinkRedemptionRepository
+	emailInviteRepo repository.EmailInvitationRepository
+	expenseRepo     repository.ExpenseRepository
+	rateRepo        repository.ExchangeRateRepository
+	budgetRepo      repository.BudgetRepository
+	pollRepo        repository.PollRepository
+	pollVoteRepo    repository.PollVoteRepository
+	commentRepo     repository.CommentRepository
 	userRepo        repository.UserRepository
+	auditRepo       repository.AuditLogRepository
+	pubsub          realtime.PubSub
 }
 
 func NewRallyService(
 	db *mongo.Database,
 	firebaseAuth *auth.Client,
 	rallyRepo repository.RallyRepository,
+	eventRepo repository.EventRepository,
+	activityRepo repository.ActivityRepository,
 	participantRepo repository.RallyParticipantRepository,
+	inviteLinkRepo repository.InviteLinkRepository,
+	redemptionRepo repository.InviteLinkRedemptionRepository,
+	emailInviteRepo repository.EmailInvitationRepository,
+	expenseRepo repository.ExpenseRepository,
+	rateRepo repository.ExchangeRateRepository,
+	budgetRepo repository.BudgetRepository,
+	pollRepo repository.PollRepository,
+	pollVoteRepo repository.PollVoteRepository,
+	commentRepo repository.CommentRepository,
 	userRepo repository.UserRepository,
+	auditRepo repository.AuditLogRepository,
+	pubsub realtime.PubSub,
 ) *RallyService {
 	return &RallyService{
 		db:              db,
 		firebaseAuth:    firebaseAuth,
 		rallyRepo:       rallyRepo,
+		eventRepo:       eventRepo,
+		activityRepo:    activityRepo,
 		participantRepo: participantRepo,
+		inviteLinkRepo:  inviteLinkRepo,
+		redemptionRepo:  redemptionRepo,
+		emailInviteRepo: emailInviteRepo,
+		expenseRepo:     expenseRepo,
+		rateRepo:        rateRepo,
+		budgetRepo:      budgetRepo,
+		pollRepo:        pollRepo,
+		pollVoteRepo:    pollVoteRepo,
+		commentRepo:     commentRepo,
 		userRepo:        userRepo,
+		auditRepo:       auditRepo,
+		pubsub:          pubsub,
 	}
 }
 
 // CreateRally creates a new rally, auto-adds the creator as owner, and invites participants
 func (s *RallyService) CreateRally(ctx context.Context, user *model.User, req *model.CreateRallyRequest) (*model.RallyResponse, error) {
 
+	if req.BaseCurrency != "" {
+		code, ok := utils.NormalizeCurrency(req.BaseCurrency)
+		if !ok {
+			return nil, errors.New("invalid currency")
+		}
+		req.BaseCurrency = code
+	}
+
 	session, err := s.db.Client().StartSession()
 	if err != nil {
 		return nil, fmt.Errorf("failed to start session: %w", err)
@@ -47,6 +97,8 @@ func (s *RallyService) CreateRally(ctx context.Context, user *model.User, req *m
 	defer session.EndSession(ctx)
 
 	var rallyResponse *model.RallyResponse
+	var createdRally *model.Rally
+	var invited []*model.RallyParticipant
 
 	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
 		rally := &model.Rally{
@@ -58,11 +110,14 @@ func (s *RallyService) CreateRally(ctx context.Context, user *model.User, req *m
 			Status:        model.RallyStatusDraft,
 			StartDate:     req.StartDate,
 			EndDate:       req.EndDate,
+			BaseCurrency:  req.BaseCurrency,
 		}
 
 		if err := s.rallyRepo.CreateRally(sessCtx, rally); err != nil {
 			return nil, fmt.Errorf("failed to create rally: %w", err)
 		}
+		createdRally = rally
+		invited = nil // Reset in case the transaction is retried
 
 		// Auto-add creator as owner participant
 		now := time.Now()
@@ -122,6 +177,7 @@ func (s *RallyService) CreateRally(ctx context.Context, user *model.User, req *m
 			if err := s.participantRepo.CreateParticipant(sessCtx, participant); err != nil {
 				return nil, fmt.Errorf("failed to invite participant %s: %w", p.UserID, err)
 			}
+			invited = append(invited, participant)
 		}
 
 		rallyResponse = s.ConvertToRallyResponse(rally)
@@ -132,11 +188,31 @@ func (s *RallyService) CreateRally(ctx context.Context, user *model.User, req *m
 		return nil, err
 	}
 
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    createdRally.ID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionCreate,
+		TargetType: model.AuditTargetRally,
+		TargetID:   createdRally.ID,
+		Changes:    diffFields(nil, createdRally),
+	})
+	for _, participant := range invited {
+		recordAudit(ctx, s.auditRepo, &model.AuditLog{
+			RallyID:    createdRally.ID,
+			ActorID:    user.ID,
+			Action:     model.AuditActionCreate,
+			TargetType: model.AuditTargetParticipant,
+			TargetID:   participant.ID,
+			Changes:    diffFields(nil, participant),
+		})
+	}
+
 	return rallyResponse, nil
 }
 
-// UpdateRally updates an existing rally (middleware ensures owner or editor role)
-func (s *RallyService) UpdateRally(ctx context.Context, rallyID string, req *model.UpdateRallyRequest) (*model.RallyResponse, error) {
+// UpdateRally updates an existing rally (middleware ensures owner or editor role).
+// If expectedVersion is set and stale, the current rally is returned along with a precondition error.
+func (s *RallyService) UpdateRally(ctx context.Context, user *model.User, caller *model.RallyParticipant, rallyID string, req *model.UpdateRallyRequest, expectedVersion *int64) (*model.RallyResponse, error) {
 
 	existing, err := s.rallyRepo.GetRallyByID(ctx, rallyID)
 	if err != nil {
@@ -146,12 +222,298 @@ func (s *RallyService) UpdateRally(ctx context.Context, rallyID string, req *mod
 		return nil, errors.New("rally not found")
 	}
 
-	updated, err := s.rallyRepo.UpdateRally(ctx, rallyID, req)
+	// An empty base currency goes back to balancing every currency separately
+	if req.BaseCurrency != nil && *req.BaseCurrency != "" {
+		code, ok := utils.NormalizeCurrency(*req.BaseCurrency)
+		if !ok {
+			return nil, errors.New("invalid currency")
+		}
+		req.BaseCurrency = &code
+	}
+
+	if req.Status != nil {
+		endDate := existing.EndDate
+		if req.EndDate != nil {
+			endDate = req.EndDate
+		}
+		if err := validateRallyStatusTransition(existing.Status, *req.Status, endDate, caller.Role); err != nil {
+			return nil, err
+		}
+		// The transition was validated against the status read above, so the write must not
+		// land on top of a concurrent status change
+		if expectedVersion == nil {
+			expectedVersion = &existing.Version
+		}
+	}
+
+	updated, err := s.rallyRepo.UpdateRally(ctx, rallyID, req, expectedVersion)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update rally: %w", err)
 	}
+	if updated == nil {
+		// The rally was changed (or deleted) since the client last read it
+		current, err := s.rallyRepo.GetRallyByID(ctx, rallyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rally: %w", err)
+		}
+		if current == nil {
+			return nil, errors.New("rally not found")
+		}
+		return s.ConvertToRallyResponse(current), errors.New("precondition failed: resource has been modified")
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    updated.ID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionUpdate,
+		TargetType: model.AuditTargetRally,
+		TargetID:   updated.ID,
+		Changes:    diffFields(existing, updated),
+	})
+
+	resp := s.ConvertToRallyResponse(updated)
+	publishRallyUpdate(ctx, s.pubsub, updated.ID, user.ID, model.RealtimeRallyUpdated, resp)
+
+	return resp, nil
+}
+
+// DeleteRally moves a rally to the trash together with its events and activities in a single
+// transaction. All cascaded documents share the rally's deleted_at marker so they can be restored
+// together (middleware ensures owner role)
+func (s *RallyService) DeleteRally(ctx context.Context, user *model.User, rallyID string) error {
+
+	existing, err := s.rallyRepo.GetRallyByID(ctx, rallyID)
+	if err != nil {
+		return fmt.Errorf("failed to get rally: %w", err)
+	}
+	if existing == nil {
+		return errors.New("rally not found")
+	}
+
+	session, err := s.db.Client().StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	deletedAt := time.Now()
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		eventIDs, err := s.eventRepo.GetEventIDsByRally(sessCtx, existing.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rally events: %w", err)
+		}
+
+		if err := s.activityRepo.SoftDeleteActivitiesByEvents(sessCtx, eventIDs, deletedAt); err != nil {
+			return nil, fmt.Errorf("failed to delete activities: %w", err)
+		}
+		if err := s.eventRepo.SoftDeleteEventsByRally(sessCtx, existing.ID, deletedAt); err != nil {
+			return nil, fmt.Errorf("failed to delete events: %w", err)
+		}
+		if err := s.rallyRepo.SoftDeleteRally(sessCtx, rallyID, deletedAt); err != nil {
+			return nil, fmt.Errorf("failed to delete rally: %w", err)
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	deleted := *existing
+	deleted.DeletedAt = &deletedAt
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    existing.ID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionDelete,
+		TargetType: model.AuditTargetRally,
+		TargetID:   existing.ID,
+		Changes:    diffFields(existing, &deleted),
+	})
+
+	publishRallyUpdate(ctx, s.pubsub, existing.ID, user.ID, model.RealtimeRallyDeleted, &model.RealtimeDeletedData{ID: existing.ID.Hex()})
+
+	return nil
+}
+
+// RestoreRally moves a rally out of the trash together with the events and activities that were
+// trashed along with it (middleware ensures owner role)
+func (s *RallyService) RestoreRally(ctx context.Context, user *model.User, rallyID string) (*model.RallyResponse, error) {
+
+	deleted, err := s.rallyRepo.GetDeletedRallyByID(ctx, rallyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rally: %w", err)
+	}
+	if deleted == nil {
+		return nil, errors.New("rally not found in trash")
+	}
+
+	session, err := s.db.Client().StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		eventIDs, err := s.eventRepo.GetAllEventIDsByRally(sessCtx, deleted.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rally events: %w", err)
+		}
+
+		if err := s.activityRepo.RestoreActivitiesByEvents(sessCtx, eventIDs, *deleted.DeletedAt); err != nil {
+			return nil, fmt.Errorf("failed to restore activities: %w", err)
+		}
+		if err := s.eventRepo.RestoreEventsByRally(sessCtx, deleted.ID, *deleted.DeletedAt); err != nil {
+			return nil, fmt.Errorf("failed to restore events: %w", err)
+		}
+		if err := s.rallyRepo.RestoreRally(sessCtx, rallyID); err != nil {
+			return nil, fmt.Errorf("failed to restore rally: %w", err)
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err := s.rallyRepo.GetRallyByID(ctx, rallyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rally: %w", err)
+	}
+	if restored == nil {
+		return nil, errors.New("rally not found")
+	}
+
+	recordAudit(ctx, s.auditRepo, &model.AuditLog{
+		RallyID:    restored.ID,
+		ActorID:    user.ID,
+		Action:     model.AuditActionRestore,
+		TargetType: model.AuditTargetRally,
+		TargetID:   restored.ID,
+		Changes:    diffFields(deleted, restored),
+	})
+
+	resp := s.ConvertToRallyResponse(restored)
+	publishRallyUpdate(ctx, s.pubsub, restored.ID, user.ID, model.RealtimeRallyUpdated, resp)
+
+	return resp, nil
+}
+
+// PurgeTrash permanently deletes rallies, events and activities that were moved to the trash before
+// the given time. Purged rallies take all of their events, activities, participants and invite links with them.
+func (s *RallyService) PurgeTrash(ctx context.Context, deletedBefore time.Time) error {
+
+	rallyIDs, err := s.rallyRepo.GetDeletedRallyIDs(ctx, deletedBefore)
+	if err != nil {
+		return fmt.Errorf("failed to get deleted rallies: %w", err)
+	}
+	for _, rallyID := range rallyIDs {
+		if err := s.purgeRally(ctx, rallyID); err != nil {
+			return err
+		}
+	}
+
+	eventIDs, err := s.eventRepo.GetDeletedEventIDs(ctx, deletedBefore)
+	if err != nil {
+		return fmt.Errorf("failed to get deleted events: %w", err)
+	}
+	if err := s.activityRepo.DeleteActivitiesByEvents(ctx, eventIDs); err != nil {
+		return fmt.Errorf("failed to purge activities: %w", err)
+	}
+	if err := s.eventRepo.DeleteEventsByIDs(ctx, eventIDs); err != nil {
+		return fmt.Errorf("failed to purge events: %w", err)
+	}
+
+	if err := s.activityRepo.PurgeDeletedActivities(ctx, deletedBefore); err != nil {
+		return fmt.Errorf("failed to purge activities: %w", err)
+	}
+
+	return nil
+}
+
+// AdvanceRallyStatuses moves rallies along their lifecycle based on their dates: drafts become
+// active once their start date has passed, and active rallies become completed once their end
+// date has passed. Activation runs first so a rally that has already ended reaches completed in a
+// single run without skipping the active state.
+func (s *RallyService) AdvanceRallyStatuses(ctx context.Context, now time.Time) error {
+
+	activated, err := s.rallyRepo.ActivateStartedRallies(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to activate rallies: %w", err)
+	}
+
+	completed, err := s.rallyRepo.CompleteEndedRallies(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to complete rallies: %w", err)
+	}
+
+	if activated > 0 || completed > 0 {
+		log.Printf("[rally-status] advanced rally statuses: %d activated, %d completed", activated, completed)
+	}
+
+	return nil
+}
+
+// purgeRally permanently deletes a rally together with its events, activities, participants,
+// invite links, email invitations, expenses, exchange rates, budgets, polls and comments in a single transaction
+func (s *RallyService) purgeRally(ctx context.Context, rallyID primitive.ObjectID) error {
+
+	session, err := s.db.Client().StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		eventIDs, err := s.eventRepo.GetAllEventIDsByRally(sessCtx, rallyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rally events: %w", err)
+		}
+
+		if err := s.activityRepo.DeleteActivitiesByEvents(sessCtx, eventIDs); err != nil {
+			return nil, fmt.Errorf("failed to delete activities: %w", err)
+		}
+		if err := s.eventRepo.DeleteEventsByRally(sessCtx, rallyID); err != nil {
+			return nil, fmt.Errorf("failed to delete events: %w", err)
+		}
+		if err := s.participantRepo.DeleteParticipantsByRally(sessCtx, rallyID); err != nil {
+			return nil, fmt.Errorf("failed to delete participants: %w", err)
+		}
+		if err := s.inviteLinkRepo.DeleteInviteLinksByRally(sessCtx, rallyID); err != nil {
+			return nil, fmt.Errorf("failed to delete invite links: %w", err)
+		}
+		if err := s.redemptionRepo.DeleteRedemptionsByRally(sessCtx, rallyID); err != nil {
+			return nil, fmt.Errorf("failed to delete invite link redemptions: %w", err)
+		}
+		if err := s.emailInviteRepo.DeleteEmailInvitationsByRally(sessCtx, rallyID); err != nil {
+			return nil, fmt.Errorf("failed to delete email invitations: %w", err)
+		}
+		if err := s.expenseRepo.DeleteExpensesByRally(sessCtx, rallyID); err != nil {
+			return nil, fmt.Errorf("failed to delete expenses: %w", err)
+		}
+		if err := s.rateRepo.DeleteExchangeRatesByRally(sessCtx, rallyID); err != nil {
+			return nil, fmt.Errorf("failed to delete exchange rates: %w", err)
+		}
+		if err := s.budgetRepo.DeleteBudgetsByRally(sessCtx, rallyID); err != nil {
+			return nil, fmt.Errorf("failed to delete budgets: %w", err)
+		}
+		if err := s.pollRepo.DeletePollsByRally(sessCtx, rallyID); err != nil {
+			return nil, fmt.Errorf("failed to delete polls: %w", err)
+		}
+		if err := s.pollVoteRepo.DeleteVotesByRally(sessCtx, rallyID); err != nil {
+			return nil, fmt.Errorf("failed to delete poll votes: %w", err)
+		}
+		if err := s.commentRepo.DeleteCommentsByRally(sessCtx, rallyID); err != nil {
+			return nil, fmt.Errorf("failed to delete comments: %w", err)
+		}
+		if err := s.rallyRepo.DeleteRally(sessCtx, rallyID.Hex()); err != nil {
+			return nil, fmt.Errorf("failed to delete rally: %w", err)
+		}
+
+		return nil, nil
+	})
 
-	return s.ConvertToRallyResponse(updated), nil
+	return err
 }
 
 // GetRalliesList retrieves a filtered and sorted list of rallies for a specific user with pagination
@@ -248,7 +610,10 @@ func (s *RallyService) ConvertToRallyResponse(rally *model.Rally) *model.RallyRe
 		Status:        rally.Status,
 		StartDate:     rally.StartDate,
 		EndDate:       rally.EndDate,
+		BaseCurrency:  rally.BaseCurrency,
 		CreatedAt:     rally.CreatedAt,
 		UpdatedAt:     rally.UpdatedAt,
+		DeletedAt:     rally.DeletedAt,
+		Version:       rally.Version,
 	}
 }
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/repository"
+)
+
+// rallyStatusTransitions lists the statuses a rally may move to from each status.
+// Leaving archived is additionally restricted to owners (see validateRallyStatusTransition).
+var rallyStatusTransitions = map[model.RallyStatus][]model.RallyStatus{
+	model.RallyStatusDraft:     {model.RallyStatusActive, model.RallyStatusInactive, model.RallyStatusArchived},
+	model.RallyStatusActive:    {model.RallyStatusInactive, model.RallyStatusCompleted, model.RallyStatusArchived},
+	model.RallyStatusInactive:  {model.RallyStatusActive, model.RallyStatusArchived},
+	model.RallyStatusCompleted: {model.RallyStatusArchived},
+	model.RallyStatusArchived:  {model.RallyStatusDraft, model.RallyStatusInactive, model.RallyStatusCompleted},
+}
+
+// validateRallyStatusTransition checks that a rally may move from its current status to next.
+// endDate is the end date the rally will have after the update.
+func validateRallyStatusTransition(current, next model.RallyStatus, endDate *time.Time, callerRole model.ParticipantRole) error {
+	if _, ok := rallyStatusTransitions[next]; !ok {
+		return errors.New("invalid rally status")
+	}
+	if current == next {
+		return nil
+	}
+
+	allowed := false
+	for _, status := range rallyStatusTransitions[current] {
+		if status == next {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return errors.New("invalid rally status transition")
+	}
+
+	if current == model.RallyStatusArchived && callerRole != model.ParticipantRoleOwner {
+		return errors.New("unauthorized: only owners can restore archived rallies")
+	}
+
+	if next == model.RallyStatusCompleted && (endDate == nil || endDate.After(time.Now())) {
+		return errors.New("cannot complete rally before its end date")
+	}
+
+	return nil
+}
+
+// isRallyReadOnly reports whether a rally's itinerary is frozen by its status
+func isRallyReadOnly(rally *model.Rally) bool {
+	return rally.Status == model.RallyStatusArchived || rally.Status == model.RallyStatusCompleted
+}
+
+// ensureRallyEditable rejects itinerary changes (events and activities) in archived or completed rallies
+func ensureRallyEditable(ctx context.Context, rallyRepo repository.RallyRepository, rallyID string) error {
+	rally, err := rallyRepo.GetRallyByID(ctx, rallyID)
+	if err != nil {
+		return fmt.Errorf("failed to get rally: %w", err)
+	}
+	if rally == nil {
+		return errors.New("rally not found")
+	}
+	if isRallyReadOnly(rally) {
+		return errors.New("rally is archived or completed and cannot be edited")
+	}
+	return nil
+}
+
+// getUnarchivedRally loads a rally whose ledger or discussion may still change. Unlike the itinerary,
+// expenses, exchange rates, budgets and comments stay open on completed rallies (expenses are often
+// settled after the trip), so only archiving freezes them. archivedMessage is the error returned for
+// an archived rally, naming what the caller was about to change.
+func getUnarchivedRally(ctx context.Context, rallyRepo repository.RallyRepository, rallyID string, archivedMessage string) (*model.Rally, error) {
+	rally, err := rallyRepo.GetRallyByID(ctx, rallyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rally: %w", err)
+	}
+	if rally == nil {
+		return nil, errors.New("rally not found")
+	}
+	if rally.Status == model.RallyStatusArchived {
+		return nil, errors.New(archivedMessage)
+	}
+	return rally, nil
+}
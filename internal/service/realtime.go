@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/realtime"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// publishRallyUpdate pushes a change to the clients subscribed to a rally. Like recordAudit it is
+// best-effort: the mutation has already been applied, so publish failures are only logged.
+func publishRallyUpdate(ctx context.Context, pubsub realtime.PubSub, rallyID, actorID primitive.ObjectID, msgType model.RealtimeMessageType, data interface{}) {
+	publish(ctx, pubsub, &model.RealtimeMessage{
+		Type:    msgType,
+		RallyID: rallyID.Hex(),
+		ActorID: actorID.Hex(),
+		Data:    data,
+	})
+}
+
+// publishParticipantUpdate pushes a change to a participation, recording whose it is and their status
+// afterwards (empty when the participant was removed) so their own streams can be ended
+func publishParticipantUpdate(ctx context.Context, pubsub realtime.PubSub, rallyID, actorID, userID primitive.ObjectID, status model.ParticipationStatus, msgType model.RealtimeMessageType, data interface{}) {
+	publish(ctx, pubsub, &model.RealtimeMessage{
+		Type:              msgType,
+		RallyID:           rallyID.Hex(),
+		ActorID:           actorID.Hex(),
+		UserID:            userID.Hex(),
+		ParticipantStatus: status,
+		Data:              data,
+	})
+}
+
+// publish stamps and sends a message, logging failures
+func publish(ctx context.Context, pubsub realtime.PubSub, msg *model.RealtimeMessage) {
+	if pubsub == nil {
+		return
+	}
+
+	msg.SentAt = time.Now()
+	if err := pubsub.Publish(ctx, msg); err != nil {
+		log.Printf("[realtime] failed to publish %s for rally %s: %v", msg.Type, msg.RallyID, err)
+	}
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/model"
+	"github.com/Hoi-Trang-Huynh/rally-backend-api/internal/realtime"
+)
+
+type RealtimeService struct {
+	pubsub realtime.PubSub
+}
+
+func NewRealtimeService(pubsub realtime.PubSub) *RealtimeService {
+	return &RealtimeService{
+		pubsub: pubsub,
+	}
+}
+
+// Subscribe starts streaming the changes of the participant's rally (middleware ensures joined participant).
+// The caller must close the subscription once the client disconnects.
+func (s *RealtimeService) Subscribe(ctx context.Context, participant *model.RallyParticipant) (*realtime.Subscription, error) {
+	sub, err := s.pubsub.Subscribe(ctx, participant.RallyID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to rally: %w", err)
+	}
+	return sub, nil
+}
+
+// EndsSubscription reports whether msg cuts a participant off from the rally's stream: the rally was
+// deleted, or the participant is no longer joined (they left, declined, were removed or banned). Only
+// the typed fields of the message are used, so this also works for messages decoded from a broker.
+// The message itself is still delivered first.
+func (s *RealtimeService) EndsSubscription(msg *model.RealtimeMessage, participant *model.RallyParticipant) bool {
+	if msg.Type == model.RealtimeRallyDeleted {
+		return true
+	}
+	return msg.UserID == participant.UserID.Hex() &&
+		msg.ParticipantStatus != model.ParticipationStatusJoined
+}
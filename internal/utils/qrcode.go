@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// QR code output formats supported by RenderQRCode
+const (
+	QRCodeFormatPNG = "png"
+	QRCodeFormatSVG = "svg"
+)
+
+// qrRecoveryLevels maps the standard error-correction letters to go-qrcode recovery levels
+var qrRecoveryLevels = map[string]qrcode.RecoveryLevel{
+	"L": qrcode.Low,
+	"M": qrcode.Medium,
+	"Q": qrcode.High,
+	"H": qrcode.Highest,
+}
+
+// RenderQRCode encodes content as a square QR code image of the given pixel size.
+// format is "png" or "svg"; level is one of the error-correction letters L, M, Q or H.
+func RenderQRCode(content, format string, size int, level string) ([]byte, error) {
+	recovery, ok := qrRecoveryLevels[level]
+	if !ok {
+		return nil, errors.New("invalid QR code error correction level")
+	}
+
+	q, err := qrcode.New(content, recovery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+
+	switch format {
+	case QRCodeFormatPNG:
+		return q.PNG(size)
+	case QRCodeFormatSVG:
+		return renderQRCodeSVG(q.Bitmap(), size), nil
+	default:
+		return nil, errors.New("invalid QR code format")
+	}
+}
+
+// renderQRCodeSVG draws the QR bitmap (including its quiet zone) as one path of unit squares,
+// scaled to size via the viewBox so the output stays crisp at any resolution.
+func renderQRCodeSVG(bitmap [][]bool, size int) []byte {
+	modules := len(bitmap)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, modules, modules)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#ffffff"/>`, modules, modules)
+	buf.WriteString(`<path fill="#000000" d="`)
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&buf, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+
+	return buf.Bytes()
+}